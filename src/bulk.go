@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency is the number of parallel MCP calls used by bulk operations
+const defaultConcurrency = 4
+
+// connectMCPSession connects to the configured MCP server for a long-running operation
+func connectMCPSession() (*MCPClient, string, error) {
+	serverURI, err := getMCPServerURI(mcpServerURI)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get MCP server URI: %w", err)
+	}
+
+	if verbose {
+		fmt.Printf("Connecting to MCP server at: %s\n", serverURI)
+	}
+
+	client, err := NewMCPSessionClient(serverURI)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create MCP client: %w", err)
+	}
+	return client, serverURI, nil
+}
+
+// safeCall runs an MCP call and converts a panic into a user-friendly error
+func safeCall(serverURI string, call func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", serverURI)
+		}
+	}()
+	return call()
+}
+
+// maxRetryDelay caps the delay between retries, so that doubling it can neither overflow nor leave
+// a failing call waiting for hours
+const maxRetryDelay = time.Minute
+
+// retryWithBackoff calls fn until it succeeds, retrying up to retries times. The delay starts at
+// delay and doubles after every attempt up to maxRetryDelay; onRetry, if set, is called before each
+// wait. Once ctx is done no more retries are made and the last error is returned.
+func retryWithBackoff(ctx context.Context, retries int, delay time.Duration, fn func() error, onRetry func(attempt int, delay time.Duration, err error)) error {
+	err := fn()
+	for attempt := 1; err != nil && attempt <= retries; attempt++ {
+		delay = backoffDelay(delay, 0)
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay = backoffDelay(delay, 1)
+		err = fn()
+	}
+	return err
+}
+
+// backoffDelay returns delay doubled the given number of times, capped at maxRetryDelay
+func backoffDelay(delay time.Duration, doublings int) time.Duration {
+	delay = min(delay, maxRetryDelay)
+	for i := 0; i < doublings && delay < maxRetryDelay; i++ {
+		delay = min(delay*2, maxRetryDelay)
+	}
+	return delay
+}
+
+// runConcurrently calls fn for every item using at most concurrency goroutines
+func runConcurrently[T any](items []T, concurrency int, fn func(T)) {
+	jobs := make(chan T)
+	go func() {
+		defer close(jobs)
+		for _, item := range items {
+			jobs <- item
+		}
+	}()
+	runWorkers(jobs, concurrency, fn)
+}
+
+// runWorkers drains jobs with at most concurrency goroutines and waits for them to finish
+func runWorkers[T any](jobs <-chan T, concurrency int, fn func(T)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				fn(job)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// bulkProgress is a progress bar that can be advanced from several goroutines.
+// A nil *bulkProgress is valid and reports nothing.
+type bulkProgress struct {
+	mu  sync.Mutex
+	bar *ProgressBar
+}
+
+// newBulkProgress creates a progress bar when progress output is enabled
+func newBulkProgress(message string, total int) *bulkProgress {
+	if !ShouldShowProgress() || total == 0 {
+		return nil
+	}
+	bar := NewProgressBar(message, total)
+	bar.Start()
+	return &bulkProgress{bar: bar}
+}
+
+// Increment advances the progress bar by one step
+func (p *bulkProgress) Increment() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bar.Increment()
+}
+
+// SetUnit enables throughput and ETA reporting in unit per second
+func (p *bulkProgress) SetUnit(unit string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bar.SetUnit(unit)
+}
+
+// Stop completes the progress bar, reporting an error when failed is non-zero
+func (p *bulkProgress) Stop(message string, failed int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if failed > 0 {
+		p.bar.StopWithError(message)
+		return
+	}
+	p.bar.Stop(message)
+}
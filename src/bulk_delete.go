@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDeletePreview is the number of documents listed before a bulk deletion is confirmed
+const maxDeletePreview = 50
+
+// Flags for bulk document deletion
+var (
+	deleteSelector    string
+	deleteGlobs       []string
+	deleteFromFile    string
+	deleteConcurrency int
+)
+
+// selectorTerm is one comparison of a metadata selector
+type selectorTerm struct {
+	Key    string
+	Op     string // "=", "!=", "exists" or "!exists"
+	Values []string
+}
+
+// documentSelector matches documents whose metadata satisfies every term
+type documentSelector []selectorTerm
+
+// parseSelector parses comma-separated terms such as 'meta.source=jira,meta.status!=open,meta.owner'.
+// The 'meta.' prefix is optional; 'key in (a|b)' matches any of several values and '!key' matches
+// documents without the key.
+func parseSelector(selector string) (documentSelector, error) {
+	var terms documentSelector
+	for _, raw := range strings.Split(selector, ",") {
+		term := strings.TrimSpace(raw)
+		if term == "" {
+			continue
+		}
+
+		var parsed selectorTerm
+		switch {
+		case strings.Contains(term, "!="):
+			key, value, _ := strings.Cut(term, "!=")
+			parsed = selectorTerm{Key: key, Op: "!=", Values: []string{strings.TrimSpace(value)}}
+		case strings.Contains(term, "="):
+			key, value, _ := strings.Cut(term, "=")
+			parsed = selectorTerm{Key: key, Op: "=", Values: []string{strings.TrimSpace(value)}}
+		case strings.Contains(term, " in ("):
+			key, values, _ := strings.Cut(term, " in (")
+			if !strings.HasSuffix(values, ")") {
+				return nil, fmt.Errorf("invalid selector term '%s' (use key in (a|b))", term)
+			}
+			parsed = selectorTerm{Key: key, Op: "=", Values: strings.Split(strings.TrimSuffix(values, ")"), "|")}
+		case strings.HasPrefix(term, "!"):
+			parsed = selectorTerm{Key: term[1:], Op: "!exists"}
+		default:
+			parsed = selectorTerm{Key: term, Op: "exists"}
+		}
+
+		parsed.Key = strings.TrimPrefix(strings.TrimSpace(parsed.Key), "meta.")
+		if parsed.Key == "" || strings.ContainsAny(parsed.Key, " =!()") {
+			return nil, fmt.Errorf("invalid selector term '%s'", term)
+		}
+		for i := range parsed.Values {
+			parsed.Values[i] = strings.TrimSpace(parsed.Values[i])
+		}
+		terms = append(terms, parsed)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+	return terms, nil
+}
+
+// Matches reports whether metadata satisfies every term of the selector
+func (s documentSelector) Matches(metadata map[string]interface{}) bool {
+	for _, term := range s {
+		value, ok := metadata[term.Key]
+		if ok && value == nil {
+			ok = false
+		}
+		switch term.Op {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		case "=", "!=":
+			matched := false
+			if ok {
+				for _, candidate := range term.Values {
+					if fmt.Sprint(value) == candidate {
+						matched = true
+						break
+					}
+				}
+			}
+			if matched != (term.Op == "=") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readNameFile reads document names, one per line, skipping blank lines and '#' comments.
+// "-" reads standard input.
+func readNameFile(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != stdinFileName {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open '%s': %w", path, err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	return names, nil
+}
+
+// bulkDeleteRequested reports whether document delete should select several documents
+func bulkDeleteRequested(args []string) bool {
+	return deleteSelector != "" || len(deleteGlobs) > 0 || deleteFromFile != "" || len(args) > 1
+}
+
+// DeleteReport summarizes a bulk deletion
+type DeleteReport struct {
+	Deleted  []string `json:"deleted"`
+	NotFound []string `json:"not_found"`
+	Failures []string `json:"failures"`
+	Duration float64  `json:"duration_seconds"`
+}
+
+// deleteDocuments deletes the documents named in names and --from-file, or matching --selector and
+// --glob, after previewing them and asking for one confirmation
+func deleteDocuments(vdbName, collectionName string, names []string) error {
+	var selector documentSelector
+	if deleteSelector != "" {
+		var err error
+		if selector, err = parseSelector(deleteSelector); err != nil {
+			return fmt.Errorf("invalid --selector: %w", err)
+		}
+	}
+	for _, pattern := range deleteGlobs {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	if deleteFromFile != "" {
+		fileNames, err := readNameFile(deleteFromFile)
+		if err != nil {
+			return err
+		}
+		if len(fileNames) == 0 {
+			return fmt.Errorf("no document names in '%s'", deleteFromFile)
+		}
+		names = append(names, fileNames...)
+	}
+	if deleteConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	var targets []string
+	var unlisted map[string]bool
+	client, serverURI, err := connectMCPSession()
+	if err == nil {
+		defer client.Close()
+		targets, unlisted, err = selectDocumentsToDelete(client, serverURI, vdbName, collectionName, names, selector, deleteGlobs)
+	}
+	if err != nil {
+		if dryRun {
+			// The preview only reads from the server; without it, describe the request
+			if !silent {
+				fmt.Printf("[DRY RUN] Would delete the selected documents from collection '%s' in vector database '%s'\n", collectionName, vdbName)
+			}
+			fmt.Fprintf(os.Stderr, "Preview unavailable: %v\n", err)
+			return nil
+		}
+		return err
+	}
+	if len(targets) == 0 {
+		if !silent {
+			fmt.Printf("No documents in collection '%s' of vector database '%s' match the selection\n", collectionName, vdbName)
+		}
+		return nil
+	}
+
+	if !silent {
+		verb := "will be"
+		if dryRun {
+			verb = "[DRY RUN] Would be"
+		}
+		printDeletePreview(targets, unlisted, collectionName, vdbName, verb)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if err := confirmDestructiveOperation("delete", fmt.Sprintf("%d document(s) from collection '%s' in vector database '%s'", len(targets), collectionName, vdbName)); err != nil {
+		return err
+	}
+
+	report := deleteDocumentNames(client, serverURI, vdbName, collectionName, targets, deleteConcurrency)
+	if !silent {
+		printDeleteReport(report, collectionName, vdbName)
+	}
+	if len(report.Failures) > 0 {
+		return fmt.Errorf("%d of %d document(s) could not be deleted", len(report.Failures), len(targets))
+	}
+	return nil
+}
+
+// selectDocumentsToDelete returns the sorted documents to delete and which of them were named
+// explicitly but are not in the collection's listing
+func selectDocumentsToDelete(client *MCPClient, serverURI, vdbName, collectionName string, names []string, selector documentSelector, globs []string) ([]string, map[string]bool, error) {
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return nil, nil, err
+	}
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	documents := make(map[string]DocumentListEntry)
+	for _, doc := range documentListEntries(entries) {
+		documents[doc.Name] = doc
+	}
+
+	matches := func(name string, metadata map[string]interface{}) bool {
+		if selector != nil && !selector.Matches(metadata) {
+			return false
+		}
+		if len(globs) > 0 && !matchesFilters(name, globs, nil) {
+			return false
+		}
+		return true
+	}
+
+	var targets []string
+	unlisted := make(map[string]bool)
+	if len(names) > 0 {
+		listedNames := sortedKeys(documents)
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			doc, listed := documents[name]
+			if !listed {
+				// A document written with --split-by is deleted with all of its parts
+				if parts := documentPartNames(listedNames, name); len(parts) > 0 {
+					for _, part := range parts {
+						if !seen[part] && matches(part, documents[part].Metadata) {
+							seen[part] = true
+							targets = append(targets, part)
+						}
+					}
+					continue
+				}
+			}
+			if !listed && (selector != nil || len(globs) > 0) {
+				continue
+			}
+			if listed && !matches(name, doc.Metadata) {
+				continue
+			}
+			if !listed {
+				unlisted[name] = true
+			}
+			targets = append(targets, name)
+		}
+	} else {
+		for name, doc := range documents {
+			if matches(name, doc.Metadata) {
+				targets = append(targets, name)
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets, unlisted, nil
+}
+
+func printDeletePreview(targets []string, unlisted map[string]bool, collectionName, vdbName, verb string) {
+	fmt.Printf("%s deleted from collection '%s' in vector database '%s': %d document(s)", verb, collectionName, vdbName, len(targets))
+	if len(unlisted) > 0 {
+		fmt.Printf(", %d of them not found in the collection", len(unlisted))
+	}
+	fmt.Println()
+	for i, name := range targets {
+		if i == maxDeletePreview {
+			fmt.Printf("  ... and %d more\n", len(targets)-maxDeletePreview)
+			break
+		}
+		if unlisted[name] {
+			fmt.Printf("  - %s (not found)\n", name)
+		} else {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+}
+
+// deleteDocumentNames deletes documents concurrently, separating documents that do not exist from failures
+func deleteDocumentNames(client *MCPClient, serverURI, vdbName, collectionName string, names []string, concurrency int) *DeleteReport {
+	start := time.Now()
+	progress := newBulkProgress(fmt.Sprintf("Deleting %d document(s) from '%s'...", len(names), collectionName), len(names))
+	progress.SetUnit("docs")
+
+	report := &DeleteReport{Deleted: []string{}, NotFound: []string{}, Failures: []string{}}
+	var mu sync.Mutex
+	runConcurrently(names, concurrency, func(name string) {
+		defer progress.Increment()
+		err := classifyDeleteError(deleteDocumentName(client, serverURI, vdbName, collectionName, name), name, collectionName, vdbName)
+
+		mu.Lock()
+		defer mu.Unlock()
+		var notFound *DocumentNotFoundError
+		switch {
+		case err == nil:
+			report.Deleted = append(report.Deleted, name)
+		case errors.As(err, &notFound):
+			report.NotFound = append(report.NotFound, name)
+		default:
+			report.Failures = append(report.Failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	})
+
+	sort.Strings(report.Deleted)
+	sort.Strings(report.NotFound)
+	sort.Strings(report.Failures)
+	report.Duration = time.Since(start).Seconds()
+	progress.Stop(fmt.Sprintf("Deleted %d document(s)", len(report.Deleted)), len(report.Failures)+len(report.NotFound))
+	return report
+}
+
+// classifyDeleteError converts the server's "not found" error of a delete or get into a
+// DocumentNotFoundError
+func classifyDeleteError(err error, docName, collectionName, vdbName string) error {
+	if err != nil && strings.Contains(err.Error(), "not found in collection") {
+		return &DocumentNotFoundError{DocumentName: docName, CollectionName: collectionName, VDBName: vdbName}
+	}
+	return err
+}
+
+func printDeleteReport(report *DeleteReport, collectionName, vdbName string) {
+	total := len(report.Deleted) + len(report.NotFound) + len(report.Failures)
+	if len(report.NotFound) == 0 && len(report.Failures) == 0 {
+		fmt.Printf("✅ Deleted %d document(s) from collection '%s' in vector database '%s' in %.2fs\n", len(report.Deleted), collectionName, vdbName, report.Duration)
+		return
+	}
+
+	fmt.Printf("Deleted %d of %d document(s) from collection '%s' in vector database '%s' in %.2fs\n", len(report.Deleted), total, collectionName, vdbName, report.Duration)
+	if len(report.NotFound) > 0 {
+		fmt.Printf("\n%d document(s) not found:\n", len(report.NotFound))
+		for _, name := range report.NotFound {
+			fmt.Printf("  ⚠️  %s\n", name)
+		}
+	}
+	if len(report.Failures) > 0 {
+		fmt.Printf("\n%d failure(s):\n", len(report.Failures))
+		for _, failure := range report.Failures {
+			fmt.Printf("  ❌ %s\n", failure)
+		}
+	}
+}
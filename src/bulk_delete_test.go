@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	selector, err := parseSelector("meta.source=jira, status!=closed,meta.owner,!meta.draft,meta.team in (docs|search)")
+	if err != nil {
+		t.Fatalf("parseSelector() error = %v", err)
+	}
+	expected := documentSelector{
+		{Key: "source", Op: "=", Values: []string{"jira"}},
+		{Key: "status", Op: "!=", Values: []string{"closed"}},
+		{Key: "owner", Op: "exists"},
+		{Key: "draft", Op: "!exists"},
+		{Key: "team", Op: "=", Values: []string{"docs", "search"}},
+	}
+	if !reflect.DeepEqual(selector, expected) {
+		t.Errorf("parseSelector() = %+v, expected %+v", selector, expected)
+	}
+
+	for _, invalid := range []string{"", " , ", "=jira", "meta.team in (docs", "meta. =x"} {
+		if _, err := parseSelector(invalid); err == nil {
+			t.Errorf("parseSelector(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestDocumentSelectorMatches(t *testing.T) {
+	selector, err := parseSelector("meta.source=jira,priority!=1,!draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		metadata map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{"source": "jira"}, true},
+		{map[string]interface{}{"source": "jira", "priority": int64(2)}, true},
+		{map[string]interface{}{"source": "jira", "priority": int64(1)}, false},
+		{map[string]interface{}{"source": "jira", "draft": true}, false},
+		{map[string]interface{}{"source": "jira", "draft": nil}, true},
+		{map[string]interface{}{"source": "confluence"}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := selector.Matches(test.metadata); got != test.expected {
+			t.Errorf("Matches(%v) = %v, expected %v", test.metadata, got, test.expected)
+		}
+	}
+}
+
+func TestReadNameFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "names.txt")
+	content := "# drafts to remove\ndrafts/a.md\n\n  drafts/b.md  \r\ndrafts/a.md\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := readNameFile(file)
+	if err != nil {
+		t.Fatalf("readNameFile() error = %v", err)
+	}
+	if expected := []string{"drafts/a.md", "drafts/b.md"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("readNameFile() = %v, expected %v", names, expected)
+	}
+
+	if _, err := readNameFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("readNameFile() expected an error for a missing file")
+	}
+}
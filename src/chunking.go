@@ -0,0 +1,567 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// Default chunk sizes applied by the server when chunk_size is not configured
+const (
+	defaultChunkSize         = 512
+	defaultSemanticChunkSize = 768
+)
+
+// Chunk is a span of a document produced by a chunking strategy.
+// Offsets are character (not byte) positions, matching the server.
+type Chunk struct {
+	Index int    `json:"index"`
+	Start int    `json:"offset_start"`
+	End   int    `json:"offset_end"`
+	Size  int    `json:"size"`
+	Text  string `json:"text"`
+}
+
+// ChunkHistogramBucket counts the chunks whose size falls in [Min, Max]
+type ChunkHistogramBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// ChunkPreview is the result of chunking a single document
+type ChunkPreview struct {
+	File       string                 `json:"file"`
+	Strategy   string                 `json:"strategy"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Source     string                 `json:"source"`
+	Characters int                    `json:"characters"`
+	ChunkCount int                    `json:"chunk_count"`
+	MinSize    int                    `json:"min_size"`
+	MaxSize    int                    `json:"max_size"`
+	MeanSize   float64                `json:"mean_size"`
+	Histogram  []ChunkHistogramBucket `json:"histogram"`
+	Chunks     []Chunk                `json:"chunks"`
+}
+
+// Flags for chunking preview
+var (
+	previewStrategy     string
+	previewChunkSize    int
+	previewChunkOverlap int
+	previewOutput       string
+)
+
+var chunkingPreviewCmd = &cobra.Command{
+	Use:   "preview FILE",
+	Short: "Preview how a document would be chunked",
+	Long: `Preview the chunks a document would produce with a chunking strategy before ingesting it.
+
+Fixed and Sentence chunking are computed locally using the same rules as the server, so no
+server connection is needed. Semantic chunking requires embeddings and is delegated to the
+MCP server's chunk_text tool.
+
+Chunk sizes and offsets are measured in characters. When --chunk-size is not set the server
+defaults are used: 512 for Fixed and Sentence, 768 for Semantic.`,
+	Example: `  maestro chunking preview README.md --strategy=Fixed --chunk-size=512 --chunk-overlap=64
+  maestro chunking preview notes.txt --strategy=Sentence --chunk-size=256
+  maestro chunking preview notes.txt --strategy=Sentence -o json
+  maestro chunking preview paper.md --strategy=Semantic --semantic-model=all-MiniLM-L6-v2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return previewChunking(args[0])
+	},
+}
+
+func init() {
+	chunkingPreviewCmd.Flags().StringVar(&previewStrategy, "strategy", "Fixed", "Chunking strategy to preview (None, Fixed, Sentence, Semantic)")
+	chunkingPreviewCmd.Flags().IntVar(&previewChunkSize, "chunk-size", 0, "Chunk size in characters (default 512, or 768 for Semantic)")
+	chunkingPreviewCmd.Flags().IntVar(&previewChunkOverlap, "chunk-overlap", 0, "Chunk overlap in characters")
+	chunkingPreviewCmd.Flags().StringVarP(&previewOutput, "output", "o", "text", "Output format (text, json)")
+	chunkingPreviewCmd.Flags().StringVar(&semanticModel, "semantic-model", "", "Semantic chunking model identifier (e.g., all-MiniLM-L6-v2)")
+	chunkingPreviewCmd.Flags().IntVar(&semanticWindowSize, "semantic-window-size", 0, "Semantic chunking window size (optional)")
+	chunkingPreviewCmd.Flags().Float64Var(&semanticThresholdPercentile, "semantic-threshold-percentile", 0, "Semantic chunking threshold percentile (0-100, optional)")
+}
+
+func previewChunking(fileName string) error {
+	strategy, err := normalizeChunkingStrategy(previewStrategy)
+	if err != nil {
+		return err
+	}
+	if previewOutput != "text" && previewOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", previewOutput)
+	}
+
+	if err := validateChunkingConfig(buildChunkingConfig(strategy, previewChunkSize, previewChunkOverlap), builtinChunkingStrategies); err != nil {
+		return err
+	}
+
+	chunkSize := previewChunkSize
+	if chunkSize == 0 {
+		chunkSize = defaultChunkSize
+		if strategy == "Semantic" {
+			chunkSize = defaultSemanticChunkSize
+		}
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", fileName, err)
+	}
+	text := string(content)
+
+	if verbose {
+		fmt.Printf("Previewing %s chunking of '%s'...\n", strategy, fileName)
+	}
+
+	preview := &ChunkPreview{
+		File:     fileName,
+		Strategy: strategy,
+		Source:   "local",
+	}
+
+	switch strategy {
+	case "None":
+		preview.Parameters = map[string]interface{}{}
+		preview.Chunks = noneChunks(text)
+	case "Fixed":
+		preview.Parameters = map[string]interface{}{"chunk_size": chunkSize, "overlap": previewChunkOverlap}
+		preview.Chunks = fixedChunks(text, chunkSize, previewChunkOverlap)
+	case "Sentence":
+		preview.Parameters = map[string]interface{}{"chunk_size": chunkSize, "overlap": previewChunkOverlap}
+		preview.Chunks = sentenceChunks(text, chunkSize, previewChunkOverlap)
+	case "Semantic":
+		config := buildChunkingConfig(strategy, chunkSize, previewChunkOverlap)
+		preview.Parameters = config["parameters"].(map[string]interface{})
+		preview.Source = "server"
+
+		if dryRun {
+			if !silent {
+				fmt.Printf("[DRY RUN] Would request Semantic chunks for '%s' from the MCP server\n", fileName)
+			}
+			return nil
+		}
+
+		preview.Chunks, err = fetchServerChunks(text, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	summarizeChunks(preview, text, chunkSize)
+
+	if previewOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(preview)
+	}
+
+	printChunkPreview(preview)
+	return nil
+}
+
+// normalizeChunkingStrategy returns the canonical spelling of a chunking strategy name
+func normalizeChunkingStrategy(strategy string) (string, error) {
+	for _, known := range []string{"None", "Fixed", "Sentence", "Semantic"} {
+		if strings.EqualFold(strategy, known) {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("unknown chunking strategy '%s' (supported: None, Fixed, Sentence, Semantic)", strategy)
+}
+
+// fetchServerChunks asks the MCP server to chunk text with the given configuration
+func fetchServerChunks(text string, config map[string]interface{}) ([]Chunk, error) {
+	serverURI, err := getMCPServerURI(mcpServerURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server URI: %w", err)
+	}
+
+	if verbose {
+		fmt.Printf("Connecting to MCP server at: %s\n", serverURI)
+	}
+
+	client, err := NewMCPClient(serverURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MCP client: %w", err)
+	}
+	defer client.Close()
+
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var chunkErr error
+		result, chunkErr = client.ChunkText(text, config)
+		return chunkErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to chunk text on the MCP server: %w", err)
+	}
+
+	chunks, err := parseServerChunks(text, result)
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// parseServerChunks extracts chunks from a chunk_text result. Offsets are taken from the
+// response when present and otherwise located by searching the original text.
+func parseServerChunks(text, result string) ([]Chunk, error) {
+	payload, ok := extractJSONPayload(result)
+	if !ok {
+		return nil, fmt.Errorf("unexpected chunk_text response")
+	}
+
+	var items []interface{}
+	switch v := payload.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items, _ = v["chunks"].([]interface{})
+	}
+	if items == nil {
+		return nil, fmt.Errorf("unexpected chunk_text response")
+	}
+
+	runes := []rune(text)
+	chunks := make([]Chunk, 0, len(items))
+	cursor := 0
+	for i, item := range items {
+		var chunkText string
+		start, end := -1, -1
+		switch v := item.(type) {
+		case string:
+			chunkText = v
+		case map[string]interface{}:
+			chunkText = firstString(v, "text", "content")
+			if s, ok := v["offset_start"].(float64); ok {
+				start = int(s)
+			}
+			if e, ok := v["offset_end"].(float64); ok {
+				end = int(e)
+			}
+		}
+
+		size := len([]rune(chunkText))
+		if start < 0 {
+			start = indexRunes(runes, []rune(chunkText), cursor)
+			if start < 0 {
+				start = cursor
+			}
+		}
+		if end < 0 {
+			end = start + size
+		}
+		cursor = start + 1
+
+		chunks = append(chunks, Chunk{Index: i, Start: start, End: end, Size: size, Text: chunkText})
+	}
+	return chunks, nil
+}
+
+// indexRunes returns the first position of needle in haystack at or after from, or -1
+func indexRunes(haystack, needle []rune, from int) int {
+	if from < 0 {
+		from = 0
+	}
+	if from > len(haystack) {
+		return -1
+	}
+	rest := string(haystack[from:])
+	idx := strings.Index(rest, string(needle))
+	if idx < 0 {
+		return -1
+	}
+	return from + utf8.RuneCountInString(rest[:idx])
+}
+
+// estimateChunkCount returns the number of chunks a chunking config produces for text.
+// It reports false for strategies that cannot be computed locally.
+func estimateChunkCount(config map[string]interface{}, text string) (int, bool) {
+	strategy := "None"
+	params := map[string]interface{}{}
+	if config != nil {
+		strategy, _ = config["strategy"].(string)
+		if p, ok := config["parameters"].(map[string]interface{}); ok {
+			params = p
+		}
+	}
+
+	size := intParam(params, "chunk_size", defaultChunkSize)
+	overlap := intParam(params, "overlap", 0)
+	switch strategy {
+	case "", "None":
+		return len(noneChunks(text)), true
+	case "Fixed":
+		return len(fixedChunks(text, size, overlap)), true
+	case "Sentence":
+		return len(sentenceChunks(text, size, overlap)), true
+	}
+	return 0, false
+}
+
+// intParam reads an integer chunking parameter that may have been decoded from JSON as a float
+func intParam(params map[string]interface{}, key string, fallback int) int {
+	switch v := params[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return fallback
+}
+
+// noneChunks returns the whole document as a single chunk
+func noneChunks(text string) []Chunk {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	return []Chunk{newChunk(runes, 0, 0, len(runes))}
+}
+
+// fixedChunks splits text into windows of size characters that advance by size - overlap.
+// This mirrors the server's Fixed strategy.
+func fixedChunks(text string, size, overlap int) []Chunk {
+	runes := []rune(text)
+	var chunks []Chunk
+	for _, r := range fixedRanges(0, len(runes), size, overlap) {
+		chunks = append(chunks, newChunk(runes, len(chunks), r[0], r[1]))
+	}
+	return chunks
+}
+
+// fixedRanges returns the [start, end) windows covering [from, to)
+func fixedRanges(from, to, size, overlap int) [][2]int {
+	if to <= from {
+		return nil
+	}
+	if size <= 0 {
+		return [][2]int{{from, to}}
+	}
+	step := max(1, size-max(0, overlap))
+
+	var ranges [][2]int
+	for start := from; start < to; start += step {
+		end := start + size
+		if end > to {
+			end = to
+		}
+		ranges = append(ranges, [2]int{start, end})
+		if end == to {
+			break
+		}
+	}
+	return ranges
+}
+
+// sentenceChunks packs whole sentences into chunks of at most size characters.
+// This mirrors the server's Sentence strategy: sentences end at '.', '!' or '?' followed
+// by whitespace, or at a blank line; a chunk starts up to overlap characters before the
+// end of the previous one; sentences longer than size are split with fixed windows.
+func sentenceChunks(text string, size, overlap int) []Chunk {
+	runes := []rune(text)
+	if size <= 0 {
+		return noneChunks(text)
+	}
+
+	var chunks []Chunk
+	curStart, curEnd := -1, -1
+	flush := func() {
+		if curStart >= 0 {
+			chunks = append(chunks, newChunk(runes, len(chunks), curStart, curEnd))
+			curStart = -1
+		}
+	}
+
+	for _, span := range sentenceSpans(runes) {
+		s, e := span[0], span[1]
+		if e-s > size {
+			flush()
+			for _, r := range fixedRanges(s, e, size, overlap) {
+				chunks = append(chunks, newChunk(runes, len(chunks), r[0], r[1]))
+			}
+			continue
+		}
+		if curStart < 0 {
+			curStart, curEnd = s, e
+			continue
+		}
+		if e-curStart <= size {
+			curEnd = e
+			continue
+		}
+
+		prevStart, prevEnd := curStart, curEnd
+		flush()
+		start := s
+		if overlap > 0 {
+			start = max(prevEnd-overlap, prevStart+1, e-size)
+			start = min(start, s)
+		}
+		curStart, curEnd = start, e
+	}
+	flush()
+	return chunks
+}
+
+// sentenceSpans splits runes into sentences, each including its trailing whitespace
+func sentenceSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := 0
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '.' || r == '!' || r == '?':
+			j := i
+			for j < len(runes) && (runes[j] == '.' || runes[j] == '!' || runes[j] == '?') {
+				j++
+			}
+			k := j
+			for k < len(runes) && unicode.IsSpace(runes[k]) {
+				k++
+			}
+			if k > j {
+				spans = append(spans, [2]int{start, k})
+				start = k
+			}
+			i = k
+		case r == '\n' && i+1 < len(runes) && runes[i+1] == '\n':
+			k := i
+			for k < len(runes) && unicode.IsSpace(runes[k]) {
+				k++
+			}
+			if i > start {
+				spans = append(spans, [2]int{start, k})
+			} else if len(spans) > 0 {
+				spans[len(spans)-1][1] = k
+			}
+			start = k
+			i = k
+		default:
+			i++
+		}
+	}
+	if start < len(runes) {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
+
+func newChunk(runes []rune, index, start, end int) Chunk {
+	return Chunk{Index: index, Start: start, End: end, Size: end - start, Text: string(runes[start:end])}
+}
+
+// summarizeChunks fills in the size statistics and histogram of a preview
+func summarizeChunks(preview *ChunkPreview, text string, chunkSize int) {
+	preview.Characters = len([]rune(text))
+	preview.ChunkCount = len(preview.Chunks)
+	if preview.Chunks == nil {
+		preview.Chunks = []Chunk{}
+	}
+	if len(preview.Chunks) == 0 {
+		preview.Histogram = []ChunkHistogramBucket{}
+		return
+	}
+
+	total := 0
+	preview.MinSize = preview.Chunks[0].Size
+	for _, chunk := range preview.Chunks {
+		total += chunk.Size
+		preview.MinSize = min(preview.MinSize, chunk.Size)
+		preview.MaxSize = max(preview.MaxSize, chunk.Size)
+	}
+	preview.MeanSize = float64(total) / float64(len(preview.Chunks))
+	preview.Histogram = chunkHistogram(preview.Chunks, max(chunkSize, preview.MaxSize), 5)
+}
+
+// chunkHistogram counts chunk sizes in equal-width buckets spanning 1..upper. Fewer buckets are
+// returned when the last ones would start past upper, e.g. three of width 2 for upper 6.
+func chunkHistogram(chunks []Chunk, upper, buckets int) []ChunkHistogramBucket {
+	if upper < buckets {
+		buckets = max(upper, 1)
+	}
+	width := (upper + buckets - 1) / buckets
+	buckets = max((upper+width-1)/width, 1)
+
+	histogram := make([]ChunkHistogramBucket, buckets)
+	for i := range histogram {
+		histogram[i].Min = i*width + 1
+		histogram[i].Max = min((i+1)*width, upper)
+	}
+	histogram[0].Min = 0
+
+	for _, chunk := range chunks {
+		i := 0
+		if chunk.Size > 0 {
+			i = min((chunk.Size-1)/width, buckets-1)
+		}
+		histogram[i].Count++
+	}
+	return histogram
+}
+
+// printChunkPreview prints chunk boundaries, statistics and a size histogram
+func printChunkPreview(preview *ChunkPreview) {
+	if silent {
+		return
+	}
+
+	var params []string
+	for _, key := range []string{"chunk_size", "overlap", "window_size", "threshold_percentile", "model_name"} {
+		if value, ok := preview.Parameters[key]; ok {
+			params = append(params, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+	description := preview.Strategy
+	if len(params) > 0 {
+		description += ", " + strings.Join(params, ", ")
+	}
+	fmt.Printf("Chunking preview for '%s' (%s)\n\n", preview.File, description)
+
+	if len(preview.Chunks) == 0 {
+		fmt.Println("No chunks produced (document is empty)")
+		return
+	}
+
+	fmt.Printf("%5s %8s %8s %6s  %s\n", "#", "START", "END", "SIZE", "PREVIEW")
+	for _, chunk := range preview.Chunks {
+		fmt.Printf("%5d %8d %8d %6d  %s\n", chunk.Index, chunk.Start, chunk.End, chunk.Size, chunkSnippet(chunk.Text, 48))
+	}
+
+	fmt.Printf("\nChunks: %d  Characters: %d  Min: %d  Max: %d  Mean: %.1f\n",
+		preview.ChunkCount, preview.Characters, preview.MinSize, preview.MaxSize, preview.MeanSize)
+
+	fmt.Println("\nSize histogram:")
+	printChunkHistogram(preview.Histogram)
+}
+
+// printChunkHistogram prints one bar per bucket, scaled so that the largest count is 40 wide
+func printChunkHistogram(histogram []ChunkHistogramBucket) {
+	largest := 0
+	for _, bucket := range histogram {
+		largest = max(largest, bucket.Count)
+	}
+	for _, bucket := range histogram {
+		bar := 0
+		if largest > 0 {
+			bar = (bucket.Count*40 + largest - 1) / largest
+		}
+		fmt.Printf("  %11s │%s %d\n", fmt.Sprintf("%d-%d", bucket.Min, bucket.Max), strings.Repeat("█", bar), bucket.Count)
+	}
+}
+
+// chunkSnippet returns the start of a chunk on a single line, truncated to limit characters
+func chunkSnippet(text string, limit int) string {
+	snippet := []rune(strings.Join(strings.Fields(text), " "))
+	if len(snippet) > limit {
+		return fmt.Sprintf("%q…", string(snippet[:limit]))
+	}
+	return fmt.Sprintf("%q", string(snippet))
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChunkingParamSpec describes a chunking parameter advertised by the server
+type ChunkingParamSpec struct {
+	Name string
+	// Type is one of "int", "float", "string" or "bool"
+	Type         string
+	Min          *float64
+	Max          *float64
+	ExclusiveMin bool
+}
+
+// ChunkingStrategySpec describes a chunking strategy and the parameters it accepts
+type ChunkingStrategySpec struct {
+	Name       string
+	Parameters map[string]ChunkingParamSpec
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+// builtinChunkingStrategies mirrors get_supported_chunking_strategies and is used when the
+// server cannot be asked, e.g. in dry-run mode
+var builtinChunkingStrategies = []ChunkingStrategySpec{
+	{Name: "None", Parameters: map[string]ChunkingParamSpec{}},
+	{Name: "Fixed", Parameters: map[string]ChunkingParamSpec{
+		"chunk_size": {Name: "chunk_size", Type: "int", Min: floatPtr(0), ExclusiveMin: true},
+		"overlap":    {Name: "overlap", Type: "int", Min: floatPtr(0)},
+	}},
+	{Name: "Sentence", Parameters: map[string]ChunkingParamSpec{
+		"chunk_size": {Name: "chunk_size", Type: "int", Min: floatPtr(0), ExclusiveMin: true},
+		"overlap":    {Name: "overlap", Type: "int", Min: floatPtr(0)},
+	}},
+	{Name: "Semantic", Parameters: map[string]ChunkingParamSpec{
+		"chunk_size":           {Name: "chunk_size", Type: "int", Min: floatPtr(0), ExclusiveMin: true},
+		"overlap":              {Name: "overlap", Type: "int", Min: floatPtr(0)},
+		"window_size":          {Name: "window_size", Type: "int", Min: floatPtr(0)},
+		"threshold_percentile": {Name: "threshold_percentile", Type: "float", Min: floatPtr(0), Max: floatPtr(100)},
+		"model_name":           {Name: "model_name", Type: "string"},
+	}},
+}
+
+// chunkingParamFlags maps chunking parameters to the CLI flags that set them
+var chunkingParamFlags = map[string]string{
+	"chunk_size":           "--chunk-size",
+	"overlap":              "--chunk-overlap",
+	"window_size":          "--semantic-window-size",
+	"threshold_percentile": "--semantic-threshold-percentile",
+	"model_name":           "--semantic-model",
+}
+
+// Parameter descriptions such as "int>0", "int >= 0", "float 0-100" or "string"
+var paramSpecPattern = regexp.MustCompile(`^\s*([a-zA-Z]+)\s*(?:(>=|>)\s*(-?[0-9.]+)|\(?\s*(-?[0-9.]+)\s*(?:-|\.\.|to)\s*(-?[0-9.]+)\s*\)?)?\s*$`)
+
+// loadChunkingConfigFile reads a chunking config from a YAML or JSON file of the form
+//
+//	strategy: Sentence
+//	parameters:
+//	  chunk_size: 512
+//	  overlap: 32
+//
+// A top-level "chunking" or "chunking_config" key wrapping the config is also accepted.
+func loadChunkingConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunking config '%s': %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse chunking config '%s': %w", path, err)
+	}
+	for _, key := range []string{"chunking", "chunking_config"} {
+		if inner, ok := raw[key].(map[string]interface{}); ok && len(raw) == 1 {
+			raw = inner
+		}
+	}
+
+	var problems []string
+	for key := range raw {
+		if key != "strategy" && key != "parameters" {
+			problems = append(problems, fmt.Sprintf("unknown key '%s'%s", key, suggestionSuffix(key, []string{"strategy", "parameters"})))
+		}
+	}
+
+	strategy, ok := raw["strategy"].(string)
+	if !ok || strategy == "" {
+		problems = append(problems, "missing 'strategy'")
+	}
+
+	params := map[string]interface{}{}
+	if value, exists := raw["parameters"]; exists && value != nil {
+		params, ok = value.(map[string]interface{})
+		if !ok {
+			problems = append(problems, "'parameters' must be a mapping of parameter names to values")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid chunking config '%s':\n  - %s", path, strings.Join(problems, "\n  - "))
+	}
+
+	return map[string]interface{}{
+		"strategy":   strategy,
+		"parameters": params,
+	}, nil
+}
+
+// parseChunkingStrategies extracts strategy metadata from a get_supported_chunking_strategies result
+func parseChunkingStrategies(result string) []ChunkingStrategySpec {
+	payload, ok := extractJSONPayload(result)
+	if !ok {
+		return nil
+	}
+
+	var items []interface{}
+	switch v := payload.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		if strategies, ok := v["strategies"].([]interface{}); ok {
+			items = strategies
+		} else {
+			// A mapping of strategy name to its description
+			for _, name := range sortedKeys(v) {
+				entry, _ := v[name].(map[string]interface{})
+				if entry == nil {
+					entry = map[string]interface{}{}
+				}
+				items = append(items, map[string]interface{}{"name": name, "parameters": entry["parameters"]})
+			}
+		}
+	}
+
+	var specs []ChunkingStrategySpec
+	for _, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := firstString(entry, "name", "strategy")
+		if name == "" {
+			continue
+		}
+
+		spec := ChunkingStrategySpec{Name: name, Parameters: map[string]ChunkingParamSpec{}}
+		switch params := entry["parameters"].(type) {
+		case map[string]interface{}:
+			for param, desc := range params {
+				spec.Parameters[param] = parseChunkingParamSpec(param, desc)
+			}
+		case []interface{}:
+			for _, param := range params {
+				switch p := param.(type) {
+				case string:
+					spec.Parameters[p] = ChunkingParamSpec{Name: p}
+				case map[string]interface{}:
+					if paramName := firstString(p, "name"); paramName != "" {
+						spec.Parameters[paramName] = parseChunkingParamSpec(paramName, p)
+					}
+				}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// parseChunkingParamSpec interprets a parameter description, which may be a type string
+// ("int>0", "float 0-100"), an object with type/min/max fields, or an example default value
+func parseChunkingParamSpec(name string, desc interface{}) ChunkingParamSpec {
+	spec := ChunkingParamSpec{Name: name}
+
+	switch d := desc.(type) {
+	case string:
+		// Free-form descriptions and example values leave the type unchecked
+		match := paramSpecPattern.FindStringSubmatch(d)
+		if match == nil {
+			break
+		}
+		spec.Type = normalizeParamType(match[1])
+		if spec.Type == "" {
+			break
+		}
+		if match[2] != "" {
+			if v, err := strconv.ParseFloat(match[3], 64); err == nil {
+				spec.Min = floatPtr(v)
+				spec.ExclusiveMin = match[2] == ">"
+			}
+		} else if match[4] != "" {
+			lo, errLo := strconv.ParseFloat(match[4], 64)
+			hi, errHi := strconv.ParseFloat(match[5], 64)
+			if errLo == nil && errHi == nil {
+				spec.Min, spec.Max = floatPtr(lo), floatPtr(hi)
+			}
+		}
+	case map[string]interface{}:
+		if t, ok := d["type"].(string); ok {
+			spec.Type = normalizeParamType(t)
+		}
+		for _, key := range []string{"min", "minimum"} {
+			if v, ok := d[key].(float64); ok {
+				spec.Min = floatPtr(v)
+			}
+		}
+		if v, ok := d["exclusive_minimum"].(float64); ok {
+			spec.Min, spec.ExclusiveMin = floatPtr(v), true
+		}
+		for _, key := range []string{"max", "maximum"} {
+			if v, ok := d[key].(float64); ok {
+				spec.Max = floatPtr(v)
+			}
+		}
+	case float64:
+		// JSON does not keep 95.0 apart from 95, so a numeric default only says the
+		// parameter is a number; integers are checked when the server declares the type
+		spec.Type = "float"
+		spec.Min = floatPtr(0)
+	case bool:
+		spec.Type = "bool"
+	}
+	return spec
+}
+
+func normalizeParamType(t string) string {
+	switch strings.ToLower(t) {
+	case "int", "integer":
+		return "int"
+	case "float", "number", "double":
+		return "float"
+	case "str", "string":
+		return "string"
+	case "bool", "boolean":
+		return "bool"
+	}
+	return ""
+}
+
+// fetchChunkingStrategies asks the server which chunking strategies and parameters it supports,
+// falling back to the built-in list when the server does not advertise them
+func fetchChunkingStrategies(client *MCPClient, serverURI string) []ChunkingStrategySpec {
+	var result string
+	err := safeCall(serverURI, func() error {
+		var listErr error
+		result, listErr = client.GetSupportedChunkingStrategies()
+		return listErr
+	})
+	if err == nil {
+		if specs := parseChunkingStrategies(result); len(specs) > 0 {
+			return specs
+		}
+	}
+
+	if verbose {
+		fmt.Println("Server did not advertise chunking strategies; validating against built-in defaults")
+	}
+	return builtinChunkingStrategies
+}
+
+// validateChunkingConfig checks a chunking config against the advertised strategies.
+// The strategy name is normalized to its advertised spelling and integer parameters are
+// converted to ints. A nil config (no chunking) is always valid.
+func validateChunkingConfig(config map[string]interface{}, specs []ChunkingStrategySpec) error {
+	if config == nil {
+		return nil
+	}
+
+	strategy, _ := config["strategy"].(string)
+	var spec *ChunkingStrategySpec
+	var names []string
+	for i := range specs {
+		names = append(names, specs[i].Name)
+		if strings.EqualFold(specs[i].Name, strategy) {
+			spec = &specs[i]
+		}
+	}
+	if spec == nil {
+		return fmt.Errorf("unknown chunking strategy '%s'%s (supported: %s)", strategy, suggestionSuffix(strategy, names), strings.Join(names, ", "))
+	}
+	config["strategy"] = spec.Name
+
+	params, _ := config["parameters"].(map[string]interface{})
+	var problems []string
+	for _, name := range sortedKeys(params) {
+		value := params[name]
+		paramSpec, ok := spec.Parameters[name]
+		if !ok {
+			problems = append(problems, unsupportedParamProblem(name, spec, specs))
+			continue
+		}
+		normalized, problem := checkChunkingParam(paramSpec, value)
+		if problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		params[name] = normalized
+	}
+
+	if len(problems) == 0 {
+		size, hasSize := params["chunk_size"].(int)
+		overlap, hasOverlap := params["overlap"].(int)
+		if hasOverlap {
+			if !hasSize {
+				size = defaultChunkSize
+				if spec.Name == "Semantic" {
+					size = defaultSemanticChunkSize
+				}
+			}
+			if overlap >= size {
+				problems = append(problems, fmt.Sprintf("overlap (%d) must be smaller than chunk_size (%d)", overlap, size))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid chunking config for strategy '%s':\n  - %s", spec.Name, strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// unsupportedParamProblem explains why a parameter does not apply to a strategy
+func unsupportedParamProblem(name string, spec *ChunkingStrategySpec, specs []ChunkingStrategySpec) string {
+	label := paramLabel(name)
+
+	var owners []string
+	for _, other := range specs {
+		if _, ok := other.Parameters[name]; ok {
+			owners = append(owners, other.Name)
+		}
+	}
+	if len(owners) > 0 {
+		return fmt.Sprintf("%s does not apply to strategy '%s' (only to: %s)", label, spec.Name, strings.Join(owners, ", "))
+	}
+
+	supported := sortedKeys(spec.Parameters)
+	if len(supported) == 0 {
+		return fmt.Sprintf("unknown parameter %s; strategy '%s' takes no parameters", label, spec.Name)
+	}
+	return fmt.Sprintf("unknown parameter %s%s (supported: %s)", label, suggestionSuffix(name, supported), strings.Join(supported, ", "))
+}
+
+// checkChunkingParam validates a value against its spec and returns the normalized value
+func checkChunkingParam(spec ChunkingParamSpec, value interface{}) (interface{}, string) {
+	label := paramLabel(spec.Name)
+
+	var number float64
+	isNumber := true
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case float64:
+		number = v
+	default:
+		isNumber = false
+	}
+
+	switch spec.Type {
+	case "int":
+		if !isNumber || number != math.Trunc(number) {
+			return nil, fmt.Sprintf("%s must be an integer, got %v", label, value)
+		}
+		value = int(number)
+	case "float":
+		if !isNumber {
+			return nil, fmt.Sprintf("%s must be a number, got %v", label, value)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Sprintf("%s must be a string, got %v", label, value)
+		}
+		return value, ""
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Sprintf("%s must be true or false, got %v", label, value)
+		}
+		return value, ""
+	default:
+		return value, ""
+	}
+
+	if spec.Min != nil {
+		if spec.ExclusiveMin && number <= *spec.Min {
+			return nil, fmt.Sprintf("%s must be greater than %v, got %v", label, *spec.Min, number)
+		}
+		if !spec.ExclusiveMin && number < *spec.Min {
+			return nil, fmt.Sprintf("%s must be at least %v, got %v", label, *spec.Min, number)
+		}
+	}
+	if spec.Max != nil && number > *spec.Max {
+		return nil, fmt.Sprintf("%s must be at most %v, got %v", label, *spec.Max, number)
+	}
+	return value, ""
+}
+
+// describeChunkingConfig formats a chunking config as "Strategy (key=value, ...)"
+func describeChunkingConfig(config map[string]interface{}) string {
+	if config == nil {
+		return "None"
+	}
+	strategy, _ := config["strategy"].(string)
+	if strategy == "" {
+		strategy = "None"
+	}
+	params, _ := config["parameters"].(map[string]interface{})
+	if len(params) == 0 {
+		return strategy
+	}
+	return fmt.Sprintf("%s (%s)", strategy, describeChunkingParams(params))
+}
+
+// describeChunkingParams formats chunking parameters as "key=value, ..." in key order
+func describeChunkingParams(params map[string]interface{}) string {
+	var parts []string
+	for _, key := range sortedKeys(params) {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, params[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paramLabel names a parameter together with the flag that sets it
+func paramLabel(name string) string {
+	if flag, ok := chunkingParamFlags[name]; ok {
+		return fmt.Sprintf("'%s' (%s)", name, flag)
+	}
+	return fmt.Sprintf("'%s'", name)
+}
+
+// suggestionSuffix returns ", did you mean 'x'?" for the most similar candidate, if any is
+// similar enough
+func suggestionSuffix(input string, candidates []string) string {
+	best, bestScore := "", 0
+	for _, candidate := range candidates {
+		a, b := strings.ToLower(input), strings.ToLower(candidate)
+		// calculateSimilarity counts the characters of its first argument found in the second, so
+		// score both ways to penalize extra letters on either side
+		score := min(calculateSimilarity(a, b), calculateSimilarity(b, a))
+		if commonPrefixLen(a, b) >= 4 {
+			// Abbreviations such as "params" for "parameters"
+			score = max(score, 80)
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	// the same threshold as command suggestions
+	if bestScore <= 70 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean '%s'?", best)
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateChunkingConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		contains string
+	}{
+		{"none without config", nil, ""},
+		{"valid fixed", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 512, "overlap": 32}}, ""},
+		{"valid semantic", map[string]interface{}{"strategy": "Semantic", "parameters": map[string]interface{}{"window_size": 1, "threshold_percentile": 90.5, "model_name": "all-MiniLM-L6-v2"}}, ""},
+		{"unknown strategy", map[string]interface{}{"strategy": "Sentense", "parameters": map[string]interface{}{}}, "did you mean 'Sentence'?"},
+		{"inapplicable parameter", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"window_size": 2}}, "'window_size' (--semantic-window-size) does not apply to strategy 'Fixed' (only to: Semantic)"},
+		{"misspelled parameter", map[string]interface{}{"strategy": "Sentence", "parameters": map[string]interface{}{"chunk_sise": 100}}, "did you mean 'chunk_size'?"},
+		{"parameters on none", map[string]interface{}{"strategy": "None", "parameters": map[string]interface{}{"chunk_size": 100}}, "does not apply to strategy 'None'"},
+		{"non-integer size", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 1.5}}, "must be an integer"},
+		{"negative overlap", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"overlap": -1}}, "must be at least 0"},
+		{"zero size", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 0}}, "must be greater than 0"},
+		{"percentile out of range", map[string]interface{}{"strategy": "Semantic", "parameters": map[string]interface{}{"threshold_percentile": 150}}, "must be at most 100"},
+		{"overlap exceeds size", map[string]interface{}{"strategy": "Sentence", "parameters": map[string]interface{}{"chunk_size": 100, "overlap": 100}}, "overlap (100) must be smaller than chunk_size (100)"},
+		{"overlap exceeds default size", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"overlap": 600}}, "chunk_size (512)"},
+	}
+
+	for _, tt := range tests {
+		err := validateChunkingConfig(tt.config, builtinChunkingStrategies)
+		if tt.contains == "" {
+			if err != nil {
+				t.Errorf("%s: validateChunkingConfig() returned error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.contains) {
+			t.Errorf("%s: validateChunkingConfig() = %v, expected error containing %q", tt.name, err, tt.contains)
+		}
+	}
+}
+
+func TestValidateChunkingConfigNormalizes(t *testing.T) {
+	config := map[string]interface{}{"strategy": "sentence", "parameters": map[string]interface{}{"chunk_size": 256.0}}
+
+	if err := validateChunkingConfig(config, builtinChunkingStrategies); err != nil {
+		t.Fatalf("validateChunkingConfig() returned error: %v", err)
+	}
+	if config["strategy"] != "Sentence" {
+		t.Errorf("strategy = %v, expected Sentence", config["strategy"])
+	}
+	if size, ok := config["parameters"].(map[string]interface{})["chunk_size"].(int); !ok || size != 256 {
+		t.Errorf("chunk_size = %#v, expected int 256", config["parameters"].(map[string]interface{})["chunk_size"])
+	}
+}
+
+func TestParseChunkingStrategiesDefaults(t *testing.T) {
+	input := `[{"name": "Semantic", "parameters": {"chunk_size": 768, "threshold_percentile": 95.0, "model_name": "all-MiniLM-L6-v2"}}]`
+
+	specs := parseChunkingStrategies(input)
+	if len(specs) != 1 {
+		t.Fatalf("parseChunkingStrategies() returned %d strategies, expected 1", len(specs))
+	}
+	if percentile := specs[0].Parameters["threshold_percentile"]; percentile.Type != "float" {
+		t.Errorf("threshold_percentile spec = %+v, expected a float inferred from 95.0", percentile)
+	}
+
+	config := map[string]interface{}{"strategy": "Semantic", "parameters": map[string]interface{}{"threshold_percentile": 92.5}}
+	if err := validateChunkingConfig(config, specs); err != nil {
+		t.Errorf("validateChunkingConfig() returned error for a fractional percentile: %v", err)
+	}
+	config = map[string]interface{}{"strategy": "Semantic", "parameters": map[string]interface{}{"chunk_size": -1}}
+	if err := validateChunkingConfig(config, specs); err == nil {
+		t.Error("validateChunkingConfig() should reject a negative value for a numeric default")
+	}
+}
+
+func TestParseChunkingStrategies(t *testing.T) {
+	input := `Supported chunking strategies: [
+  {"name": "None", "parameters": {}},
+  {"name": "Fixed", "parameters": {"chunk_size": "int>0", "overlap": "int>=0"}},
+  {"name": "Semantic", "parameters": {"chunk_size": "int>0", "threshold_percentile": "float 0-100", "model_name": "string", "window_size": {"type": "integer", "minimum": 0}}}
+]`
+
+	specs := parseChunkingStrategies(input)
+	if len(specs) != 3 {
+		t.Fatalf("parseChunkingStrategies() returned %d strategies, expected 3", len(specs))
+	}
+
+	var semantic ChunkingStrategySpec
+	for _, spec := range specs {
+		if spec.Name == "Semantic" {
+			semantic = spec
+		}
+	}
+	percentile := semantic.Parameters["threshold_percentile"]
+	if percentile.Type != "float" || percentile.Min == nil || *percentile.Min != 0 || percentile.Max == nil || *percentile.Max != 100 {
+		t.Errorf("threshold_percentile spec = %+v", percentile)
+	}
+	size := semantic.Parameters["chunk_size"]
+	if size.Type != "int" || size.Min == nil || *size.Min != 0 || !size.ExclusiveMin {
+		t.Errorf("chunk_size spec = %+v", size)
+	}
+	if window := semantic.Parameters["window_size"]; window.Type != "int" || window.Min == nil {
+		t.Errorf("window_size spec = %+v", window)
+	}
+
+	// Advertised metadata drives validation
+	err := validateChunkingConfig(map[string]interface{}{"strategy": "Sentence", "parameters": map[string]interface{}{}}, specs)
+	if err == nil || !strings.Contains(err.Error(), "supported: None, Fixed, Semantic)") {
+		t.Errorf("validateChunkingConfig() = %v, expected unsupported strategy error", err)
+	}
+}
+
+func TestLoadChunkingConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "chunking.yaml")
+	if err := os.WriteFile(valid, []byte("chunking:\n  strategy: Sentence\n  parameters:\n    chunk_size: 512\n    overlap: 32\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadChunkingConfigFile(valid)
+	if err != nil {
+		t.Fatalf("loadChunkingConfigFile() returned error: %v", err)
+	}
+	if config["strategy"] != "Sentence" || config["parameters"].(map[string]interface{})["overlap"] != 32 {
+		t.Errorf("loadChunkingConfigFile() = %v", config)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("strategy: Fixed\nparams:\n  chunk_size: 512\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadChunkingConfigFile(invalid); err == nil || !strings.Contains(err.Error(), "did you mean 'parameters'?") {
+		t.Errorf("loadChunkingConfigFile() = %v, expected unknown key error with suggestion", err)
+	}
+}
+
+func TestResultTextKeepsHTMLCharacters(t *testing.T) {
+	response := &MCPResponse{Result: map[string]interface{}{"description": "size <= 512 & overlap > 0"}}
+	text, ok := response.resultText()
+	if !ok || text != `{"description":"size <= 512 & overlap > 0"}` {
+		t.Errorf("resultText() = %q, %v", text, ok)
+	}
+	if text, _ := (&MCPResponse{Result: "Fixed, Sentence"}).resultText(); text != "Fixed, Sentence" {
+		t.Errorf("resultText() of a string = %q", text)
+	}
+}
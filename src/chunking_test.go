@@ -0,0 +1,163 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func chunkBounds(chunks []Chunk) [][2]int {
+	var bounds [][2]int
+	for _, chunk := range chunks {
+		bounds = append(bounds, [2]int{chunk.Start, chunk.End})
+	}
+	return bounds
+}
+
+func TestFixedChunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		size     int
+		overlap  int
+		expected [][2]int
+	}{
+		{"no overlap", strings.Repeat("a", 10), 4, 0, [][2]int{{0, 4}, {4, 8}, {8, 10}}},
+		{"with overlap", strings.Repeat("a", 10), 4, 2, [][2]int{{0, 4}, {2, 6}, {4, 8}, {6, 10}}},
+		{"exact fit", strings.Repeat("a", 8), 4, 0, [][2]int{{0, 4}, {4, 8}}},
+		{"shorter than size", "abc", 512, 0, [][2]int{{0, 3}}},
+		{"empty", "", 4, 0, nil},
+	}
+
+	for _, tt := range tests {
+		result := chunkBounds(fixedChunks(tt.text, tt.size, tt.overlap))
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: fixedChunks() = %v, expected %v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestFixedChunksCountsCharacters(t *testing.T) {
+	chunks := fixedChunks("héllo wörld", 5, 0)
+	if len(chunks) != 3 || chunks[0].Text != "héllo" || chunks[1].Text != " wörl" || chunks[2].Text != "d" {
+		t.Errorf("fixedChunks() split multi-byte text incorrectly: %+v", chunks)
+	}
+}
+
+func TestSentenceChunks(t *testing.T) {
+	text := "One two. Three four! Five six? Seven."
+
+	chunks := sentenceChunks(text, 21, 0)
+	var texts []string
+	for _, chunk := range chunks {
+		texts = append(texts, chunk.Text)
+	}
+	expected := []string{"One two. Three four! ", "Five six? Seven."}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("sentenceChunks() = %q, expected %q", texts, expected)
+	}
+
+	// Every character is covered exactly once without overlap
+	if chunks[0].Start != 0 || chunks[0].End != chunks[1].Start || chunks[1].End != len(text) {
+		t.Errorf("sentenceChunks() boundaries = %v", chunkBounds(chunks))
+	}
+}
+
+func TestSentenceChunksOverlap(t *testing.T) {
+	text := "One two. Three four! Five six? Seven."
+
+	chunks := sentenceChunks(text, 24, 6)
+	if len(chunks) < 2 {
+		t.Fatalf("sentenceChunks() = %v, expected at least two chunks", chunkBounds(chunks))
+	}
+	for i := 1; i < len(chunks); i++ {
+		if chunks[i].Start >= chunks[i-1].End {
+			t.Errorf("chunk %d does not overlap the previous chunk: %v", i, chunkBounds(chunks))
+		}
+	}
+	for _, chunk := range chunks {
+		if chunk.Size > 24 {
+			t.Errorf("chunk %d exceeds the chunk size: %d", chunk.Index, chunk.Size)
+		}
+	}
+}
+
+func TestSentenceChunksSplitsLongSentences(t *testing.T) {
+	text := "Short. " + strings.Repeat("x", 25)
+
+	result := chunkBounds(sentenceChunks(text, 10, 0))
+	expected := [][2]int{{0, 7}, {7, 17}, {17, 27}, {27, 32}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("sentenceChunks() = %v, expected %v", result, expected)
+	}
+}
+
+func TestSentenceSpansBlankLines(t *testing.T) {
+	runes := []rune("# Title\n\nFirst para. Second\n\n\nLast")
+
+	result := sentenceSpans(runes)
+	expected := [][2]int{{0, 9}, {9, 21}, {21, 30}, {30, 34}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("sentenceSpans() = %v, expected %v", result, expected)
+	}
+}
+
+func TestChunkHistogram(t *testing.T) {
+	chunks := []Chunk{{Size: 10}, {Size: 100}, {Size: 101}, {Size: 500}, {Size: 512}}
+
+	result := chunkHistogram(chunks, 512, 5)
+	expected := []ChunkHistogramBucket{
+		{Min: 0, Max: 103, Count: 3},
+		{Min: 104, Max: 206, Count: 0},
+		{Min: 207, Max: 309, Count: 0},
+		{Min: 310, Max: 412, Count: 0},
+		{Min: 413, Max: 512, Count: 2},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("chunkHistogram() = %+v, expected %+v", result, expected)
+	}
+
+	// a width of 2 covers 1..6 in three buckets; a fourth and fifth would start past upper
+	result = chunkHistogram([]Chunk{{Size: 1}, {Size: 4}, {Size: 6}}, 6, 5)
+	expected = []ChunkHistogramBucket{
+		{Min: 0, Max: 2, Count: 1},
+		{Min: 3, Max: 4, Count: 1},
+		{Min: 5, Max: 6, Count: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("chunkHistogram() with upper 6 = %+v, expected %+v", result, expected)
+	}
+	for upper := 1; upper <= 40; upper++ {
+		for _, bucket := range chunkHistogram(nil, upper, 5) {
+			if bucket.Min > bucket.Max || bucket.Max > upper {
+				t.Errorf("chunkHistogram(upper %d) has bucket %+v", upper, bucket)
+			}
+		}
+	}
+}
+
+func TestParseServerChunks(t *testing.T) {
+	text := "Alpha beta. Gamma delta."
+
+	withOffsets := `Chunked text into 2 chunks: [{"text": "Alpha beta. ", "offset_start": 0, "offset_end": 12}, {"text": "Gamma delta.", "offset_start": 12, "offset_end": 24}]`
+	chunks, err := parseServerChunks(text, withOffsets)
+	if err != nil {
+		t.Fatalf("parseServerChunks() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(chunkBounds(chunks), [][2]int{{0, 12}, {12, 24}}) {
+		t.Errorf("parseServerChunks() = %v", chunkBounds(chunks))
+	}
+
+	withoutOffsets := `{"chunks": ["Alpha beta.", "Gamma delta."]}`
+	chunks, err = parseServerChunks(text, withoutOffsets)
+	if err != nil {
+		t.Fatalf("parseServerChunks() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(chunkBounds(chunks), [][2]int{{0, 11}, {12, 24}}) {
+		t.Errorf("parseServerChunks() = %v", chunkBounds(chunks))
+	}
+
+	if _, err := parseServerChunks(text, "no chunks"); err == nil {
+		t.Error("parseServerChunks() should fail without a JSON payload")
+	}
+}
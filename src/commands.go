@@ -14,7 +14,9 @@ var vdbCmd = &cobra.Command{
 	Aliases: []string{"vdb"},
 	Example: `  maestro vectordb list
   maestro vectordb create config.yaml
-  maestro vectordb delete my-vdb`,
+  maestro vectordb delete my-vdb
+  maestro vectordb export my-vdb -o kb.tar.gz
+  maestro vectordb import kb.tar.gz`,
 }
 
 var vdbListCmd = &cobra.Command{
@@ -61,7 +63,11 @@ var collectionCmd = &cobra.Command{
 	Aliases: []string{"coll"},
 	Example: `  maestro collection list --vdb=my-vdb
   maestro collection create --vdb=my-vdb --name=my-collection
-  maestro collection delete my-collection --vdb=my-vdb`,
+  maestro collection delete my-collection --vdb=my-vdb
+  maestro collection migrate --vdb=my-vdb --name=my-collection --to-name=my-collection-v2 --embedding=new-model
+  maestro collection update --vdb=my-vdb --name=my-collection --chunking-strategy=Sentence --rechunk
+  maestro collection stats --vdb=my-vdb --name=my-collection
+  maestro collection dedupe --vdb=my-vdb --name=my-collection`,
 }
 
 var collectionInfoCmd = &cobra.Command{
@@ -119,7 +125,8 @@ var collectionCreateCmd = &cobra.Command{
 	Short: "Create a collection",
 	Long:  `Create a collection in a vector database.`,
 	Example: `  maestro collection create --vdb=my-vdb --name=my-collection
-  maestro collection create --vdb=my-vdb --name=my-collection --embedding=text-embedding-3-small`,
+  maestro collection create --vdb=my-vdb --name=my-collection --embedding=text-embedding-3-small
+  maestro collection create --vdb=my-vdb --name=my-collection --chunking-config=chunking.yaml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		vdbName, _ := cmd.Flags().GetString("vdb")
 		collectionName, _ := cmd.Flags().GetString("name")
@@ -171,15 +178,26 @@ var documentCmd = &cobra.Command{
 	Long:    `Manage documents within collections.`,
 	Aliases: []string{"doc"},
 	Example: `  maestro document list --vdb=my-vdb --collection=my-collection
+  maestro document get my-doc --vdb=my-vdb --collection=my-collection
   maestro document create --vdb=my-vdb --collection=my-collection --name=my-doc --file=./data.txt
-  maestro document delete my-doc --vdb=my-vdb --collection=my-collection`,
+  maestro document update my-doc --vdb=my-vdb --collection=my-collection --file=./data.txt --keep-versions=3
+  maestro document delete my-doc --vdb=my-vdb --collection=my-collection
+  maestro document delete --selector 'meta.source=jira' --vdb=my-vdb --collection=my-collection
+  maestro document ingest ./docs --vdb=my-vdb --collection=my-collection --include='*.md'
+  maestro document ingest ./wiki --vdb=my-vdb --collection=wiki --redact=mask
+  maestro document ingest-repo ~/src/service --vdb=my-vdb --collection=code
+  maestro document sync ./docs --vdb=my-vdb --collection=my-collection --prune
+  maestro document watch ./docs --vdb=my-vdb --collection=my-collection
+  maestro document extract ./manual.pdf
+  maestro document import tickets.jsonl --vdb=my-vdb --collection=tickets --text-field=body --name-field=id`,
 }
 
 var documentListCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List documents",
-	Long:    `List documents in a collection.`,
-	Example: `  maestro document list --vdb=my-vdb --collection=my-collection`,
+	Use:   "list",
+	Short: "List documents",
+	Long:  `List documents in a collection.`,
+	Example: `  maestro document list --vdb=my-vdb --collection=my-collection
+  maestro document list --vdb=my-vdb --collection=my-collection -o wide`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		vdbName, _ := cmd.Flags().GetString("vdb")
 		collectionName, _ := cmd.Flags().GetString("collection")
@@ -209,9 +227,19 @@ var documentListCmd = &cobra.Command{
 var documentCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a document",
-	Long:  `Create a document in a collection.`,
+	Long: `Create a document in a collection.
+
+The content is read from --file, from standard input with --file=-, or fetched over HTTP(S) with --url.
+With --upsert, an existing document of the same name is replaced as by 'document update'.
+
+Text larger than --max-size is rejected, or with --split-by written as parts NAME#part-1, NAME#part-2, ...
+that share a 'parent' metadata key. 'document get' and 'document delete' treat the parts as one document.`,
 	Example: `  maestro document create --vdb=my-vdb --collection=my-collection --name=my-doc --file=./data.txt
-  maestro document create --vdb=my-vdb --collection=my-collection --name=my-doc --file=./data.txt --embedding=text-embedding-3-small`,
+  maestro document create --vdb=my-vdb --collection=my-collection --name=my-doc --file=./data.txt --embedding=text-embedding-3-small
+  cat notes.md | maestro document create --vdb=my-vdb --collection=my-collection --name=notes --file=-
+  maestro document create --vdb=my-vdb --collection=my-collection --name=guide --url=https://example.com/guide.html
+  maestro document create --vdb=my-vdb --collection=my-collection --name=my-doc --file=./data.txt --upsert --keep-versions=3
+  maestro document create --vdb=my-vdb --collection=my-collection --name=handbook --file=./handbook.md --split-by=heading`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		vdbName, _ := cmd.Flags().GetString("vdb")
 		collectionName, _ := cmd.Flags().GetString("collection")
@@ -239,8 +267,8 @@ var documentCreateCmd = &cobra.Command{
 		if documentName == "" {
 			return fmt.Errorf("--name flag is required")
 		}
-		if fileName == "" {
-			return fmt.Errorf("--file flag is required")
+		if fileName == "" && documentURL == "" {
+			return fmt.Errorf("--file flag is required (or use --url)")
 		}
 
 		// Set the documentFileName variable that createDocument expects
@@ -251,14 +279,31 @@ var documentCreateCmd = &cobra.Command{
 }
 
 var documentDeleteCmd = &cobra.Command{
-	Use:   "delete",
-	Short: "Delete a document",
-	Long:  `Delete a document from a collection.`,
+	Use:   "delete [NAME...]",
+	Short: "Delete documents",
+	Long: `Delete a document from a collection.
+
+Several documents can be deleted at once by naming them, by listing names in a file with
+--from-file (one per line, '-' for standard input), or by selecting them with --selector and
+--glob. --selector matches document metadata with comma-separated terms that must all hold:
+'meta.key=value', 'meta.key!=value', 'meta.key in (a|b)', 'meta.key' (set) and '!meta.key'
+(not set). --glob matches document names, where '*' does not cross '/' and '**' does.
+
+The selected documents are listed before a single confirmation and then deleted in parallel.
+Documents that no longer exist are reported without failing the command.`,
 	Example: `  maestro document delete my-doc --vdb=my-vdb --collection=my-collection
-  maestro document delete my-doc --vdb=my-vdb --collection=my-collection --force`,
-	Args: cobra.ExactArgs(1),
+  maestro document delete my-doc --vdb=my-vdb --collection=my-collection --force
+  maestro document delete --selector 'meta.source=jira' --vdb=my-vdb --collection=my-collection
+  maestro document delete --glob 'drafts/*' --vdb=my-vdb --collection=my-collection --dry-run
+  maestro document delete --from-file names.txt --vdb=my-vdb --collection=my-collection`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// Without --selector, --glob or --from-file, at least one document must be named
+		if len(args) == 0 && deleteSelector == "" && len(deleteGlobs) == 0 && deleteFromFile == "" {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		documentName := args[0]
 		vdbName, _ := cmd.Flags().GetString("vdb")
 		collectionName, _ := cmd.Flags().GetString("collection")
 
@@ -280,7 +325,11 @@ var documentDeleteCmd = &cobra.Command{
 			}
 		}
 
-		return deleteDocument(vdbName, collectionName, documentName)
+		if bulkDeleteRequested(args) {
+			cmd.SilenceUsage = true
+			return deleteDocuments(vdbName, collectionName, args)
+		}
+		return deleteDocument(vdbName, collectionName, args[0])
 	},
 }
 
@@ -333,7 +382,8 @@ var chunkingCmd = &cobra.Command{
 	Short:   "Manage chunking strategies",
 	Long:    `Discover and manage document chunking strategies.`,
 	Aliases: []string{"chunks"},
-	Example: `  maestro chunking list`,
+	Example: `  maestro chunking list
+  maestro chunking preview README.md --strategy=Sentence --chunk-size=512`,
 }
 
 var chunkingListCmd = &cobra.Command{
@@ -397,12 +447,23 @@ func init() {
 	// Add flags to document commands
 	documentListCmd.Flags().String("vdb", "", "Vector database name")
 	documentListCmd.Flags().String("collection", "", "Collection name")
+	documentListCmd.Flags().StringVarP(&documentListOutput, "output", "o", "text", "Output format (text, wide, json); wide and json include metadata")
 	documentCreateCmd.Flags().String("vdb", "", "Vector database name")
 	documentCreateCmd.Flags().String("collection", "", "Collection name")
 	documentCreateCmd.Flags().String("name", "", "Document name")
-	documentCreateCmd.Flags().String("file", "", "File path")
+	documentCreateCmd.Flags().String("file", "", "File path, or - to read standard input")
+	documentCreateCmd.Flags().StringVar(&documentURL, "url", "", "Fetch the document content over HTTP(S)")
+	documentCreateCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send the file content as-is instead of extracting its text")
+	addMetadataFlags(documentCreateCmd)
+	addSplitFlags(documentCreateCmd)
+	addRedactFlags(documentCreateCmd)
+	addUpsertFlags(documentCreateCmd)
 	documentDeleteCmd.Flags().String("vdb", "", "Vector database name")
 	documentDeleteCmd.Flags().String("collection", "", "Collection name")
+	documentDeleteCmd.Flags().StringVar(&deleteSelector, "selector", "", "Delete documents whose metadata matches, e.g. 'meta.source=jira'")
+	documentDeleteCmd.Flags().StringSliceVar(&deleteGlobs, "glob", nil, "Delete documents whose names match a glob pattern (repeatable)")
+	documentDeleteCmd.Flags().StringVar(&deleteFromFile, "from-file", "", "Delete the documents named in a file, one per line ('-' for stdin)")
+	documentDeleteCmd.Flags().IntVar(&deleteConcurrency, "concurrency", defaultConcurrency, "Number of documents to delete in parallel")
 
 	// Add flags to embedding commands
 	embeddingListCmd.Flags().String("vdb", "", "Vector database name")
@@ -423,6 +484,7 @@ func init() {
 	collectionCreateCmd.Flags().StringVar(&collectionChunkStrategy, "chunking-strategy", "None", "Chunking strategy to use for the collection (None, Fixed, Sentence, Semantic)")
 	collectionCreateCmd.Flags().IntVar(&collectionChunkSize, "chunk-size", 0, "Chunk size in characters (optional; defaults may vary by strategy)")
 	collectionCreateCmd.Flags().IntVar(&collectionChunkOverlap, "chunk-overlap", 0, "Chunk overlap in characters (optional)")
+	collectionCreateCmd.Flags().StringVar(&collectionChunkingConfigFile, "chunking-config", "", "YAML or JSON file with the chunking strategy and parameters")
 	// Semantic-specific parameters
 	collectionCreateCmd.Flags().StringVar(&semanticModel, "semantic-model", "", "Semantic chunking model identifier (e.g., all-MiniLM-L6-v2)")
 	collectionCreateCmd.Flags().IntVar(&semanticWindowSize, "semantic-window-size", 0, "Semantic chunking window size (optional)")
@@ -200,9 +200,12 @@ func (cp *CompletionProvider) CompleteCommands(partial string) ([]CompletionItem
 		"document", "doc",
 		"embedding", "embed",
 		"chunking", "chunks",
+		"ingest",
 		"status",
 		"query",
+		"chat",
 		"validate",
+		"eval",
 	}
 
 	var completions []CompletionItem
@@ -225,15 +228,19 @@ func (cp *CompletionProvider) CompleteSubcommands(command, partial string) ([]Co
 
 	switch command {
 	case "vectordb", "vdb":
-		subcommands = []string{"list", "create", "delete"}
+		subcommands = []string{"list", "create", "delete", "export", "import"}
 	case "collection", "coll":
-		subcommands = []string{"list", "info", "create", "delete"}
+		subcommands = []string{"list", "info", "create", "delete", "migrate", "update", "stats", "dedupe"}
 	case "document", "doc":
-		subcommands = []string{"list", "create", "delete"}
+		subcommands = []string{"list", "create", "delete", "ingest", "ingest-repo", "sync", "watch", "extract", "import", "get", "update", "history"}
 	case "embedding", "embed":
 		subcommands = []string{"list"}
 	case "chunking", "chunks":
-		subcommands = []string{"list"}
+		subcommands = []string{"list", "preview"}
+	case "ingest":
+		subcommands = []string{"runs"}
+	case "eval":
+		subcommands = []string{"retrieval"}
 	default:
 		return nil, nil
 	}
@@ -257,8 +264,17 @@ func (cp *CompletionProvider) CompleteFlags(partial string) ([]CompletionItem, e
 	flags := []string{
 		"--vdb", "--collection", "--name", "--file", "--embedding",
 		"--verbose", "--silent", "--dry-run", "--force",
-		"--mcp-server-uri", "--doc-limit",
-		"--chunking-strategy", "--chunk-size", "--chunk-overlap",
+		"--mcp-server-uri", "--doc-limit", "--interactive", "--where", "--min-score", "--max-distance", "--target",
+		"--rerank", "--candidates", "--rerank-weight", "--dataset", "--k", "--baseline", "--tolerance",
+		"--output", "--concurrency", "--resume", "--checkpoint", "--sample", "--top",
+		"--include", "--exclude", "--hidden", "--vendored", "--failed", "--prune", "--raw", "--url",
+		"--format", "--text-field", "--name-field", "--metadata-fields", "--skip-invalid", "--meta", "--meta-file",
+		"--all", "--out", "--upsert", "--keep-versions", "--selector", "--glob", "--from-file",
+		"--max-size", "--split-by", "--part-size", "--redact", "--redact-config",
+		"--dedupe", "--dedupe-threshold", "--delete", "--threshold",
+		"--debounce", "--retries", "--retry-delay", "--log-format",
+		"--to-vdb", "--to-name", "--swap", "--rechunk", "--backup", "--no-backup",
+		"--chunking-strategy", "--strategy", "--chunk-size", "--chunk-overlap", "--chunking-config",
 		"--semantic-model", "--semantic-window-size", "--semantic-threshold-percentile",
 		"-h", "--help", "--version",
 	}
@@ -330,11 +346,11 @@ func GetCompletionsForContext(args []string, currentWord string) ([]CompletionIt
 			if vdbName != "" {
 				return provider.CompleteCollections(vdbName, currentWord)
 			}
-		case "--file":
+		case "--file", "--chunking-config":
 			return provider.CompleteFiles(currentWord)
 		case "--embedding":
 			return provider.CompleteEmbeddings(currentWord)
-		case "--chunking-strategy":
+		case "--chunking-strategy", "--strategy":
 			return provider.CompleteChunkingStrategies(currentWord)
 		}
 	}
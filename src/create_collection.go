@@ -78,6 +78,8 @@ var (
 	collectionChunkStrategy string
 	collectionChunkSize     int
 	collectionChunkOverlap  int
+	// Chunking config file, an alternative to the chunking flags
+	collectionChunkingConfigFile string
 	// Semantic-specific params
 	semanticModel               string
 	semanticWindowSize          int
@@ -92,6 +94,7 @@ func init() {
 		cmd.Flags().StringVar(&collectionChunkStrategy, "chunking-strategy", "None", "Chunking strategy to use for the collection (None, Fixed, Sentence, Semantic)")
 		cmd.Flags().IntVar(&collectionChunkSize, "chunk-size", 0, "Chunk size in characters (optional; defaults may vary by strategy)")
 		cmd.Flags().IntVar(&collectionChunkOverlap, "chunk-overlap", 0, "Chunk overlap in characters (optional)")
+		cmd.Flags().StringVar(&collectionChunkingConfigFile, "chunking-config", "", "YAML or JSON file with the chunking strategy and parameters")
 	}
 }
 
@@ -100,7 +103,16 @@ func createCollection(vdbName, collectionName string) error {
 		fmt.Printf("Creating collection '%s' in vector database '%s'...\n", collectionName, vdbName)
 	}
 
+	chunkCfg, err := resolveChunkingConfig(collectionChunkStrategy, collectionChunkSize, collectionChunkOverlap, collectionChunkingConfigFile)
+	if err != nil {
+		return err
+	}
+
 	if dryRun {
+		// Without a server, validate against the built-in strategy list
+		if err := validateChunkingConfig(chunkCfg, builtinChunkingStrategies); err != nil {
+			return err
+		}
 		if !silent {
 			fmt.Printf("[DRY RUN] Would create collection '%s' in vector database '%s' with embedding '%s'\n", collectionName, vdbName, collectionEmbedding)
 		}
@@ -134,6 +146,10 @@ func createCollection(vdbName, collectionName string) error {
 		return fmt.Errorf("vector database '%s' does not exist. Please create it first", vdbName)
 	}
 
+	if err := validateChunkingConfig(chunkCfg, fetchChunkingStrategies(client, serverURI)); err != nil {
+		return err
+	}
+
 	// Call the MCP server to create the collection with panic recovery
 	var createErr error
 	func() {
@@ -143,34 +159,6 @@ func createCollection(vdbName, collectionName string) error {
 				createErr = fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", serverURI)
 			}
 		}()
-		// Build chunking config if provided
-		var chunkCfg map[string]interface{} = nil
-		if collectionChunkStrategy != "None" {
-			params := map[string]interface{}{}
-			if collectionChunkSize > 0 {
-				params["chunk_size"] = collectionChunkSize
-			}
-			if collectionChunkOverlap > 0 {
-				params["overlap"] = collectionChunkOverlap
-			}
-			if collectionChunkStrategy == "Semantic" {
-				if semanticWindowSize > 0 {
-					params["window_size"] = semanticWindowSize
-				}
-				if semanticThresholdPercentile > 0 {
-					params["threshold_percentile"] = semanticThresholdPercentile
-				}
-				if semanticModel != "" {
-					// Use 'model_name' to align with server and semantic chunking API
-					params["model_name"] = semanticModel
-				}
-			}
-			chunkCfg = map[string]interface{}{
-				"strategy":   collectionChunkStrategy,
-				"parameters": params,
-			}
-		}
-
 		createErr = client.CreateCollectionWithChunking(vdbName, collectionName, collectionEmbedding, chunkCfg)
 	}()
 
@@ -188,3 +176,58 @@ func createCollection(vdbName, collectionName string) error {
 
 	return nil
 }
+
+// buildChunkingConfig returns the chunking config for create_collection, or nil for the None
+// strategy without parameters. Semantic-specific parameters come from the --semantic-* flags and
+// are included whenever set so that validateChunkingConfig can reject them for other strategies.
+func buildChunkingConfig(strategy string, chunkSize, chunkOverlap int) map[string]interface{} {
+	params := map[string]interface{}{}
+	if chunkSize != 0 {
+		params["chunk_size"] = chunkSize
+	}
+	if chunkOverlap != 0 {
+		params["overlap"] = chunkOverlap
+	}
+	if semanticWindowSize != 0 {
+		params["window_size"] = semanticWindowSize
+	}
+	if semanticThresholdPercentile != 0 {
+		params["threshold_percentile"] = semanticThresholdPercentile
+	}
+	if semanticModel != "" {
+		// Use 'model_name' to align with server and semantic chunking API
+		params["model_name"] = semanticModel
+	}
+
+	if (strategy == "" || strategy == "None") && len(params) == 0 {
+		return nil
+	}
+	if strategy == "" {
+		strategy = "None"
+	}
+
+	return map[string]interface{}{
+		"strategy":   strategy,
+		"parameters": params,
+	}
+}
+
+// resolveChunkingConfig returns the chunking config from a --chunking-config file or from the chunking flags
+func resolveChunkingConfig(strategy string, chunkSize, chunkOverlap int, configFile string) (map[string]interface{}, error) {
+	if configFile == "" {
+		return buildChunkingConfig(strategy, chunkSize, chunkOverlap), nil
+	}
+
+	if (strategy != "" && strategy != "None") || chunkSize != 0 || chunkOverlap != 0 ||
+		semanticModel != "" || semanticWindowSize != 0 || semanticThresholdPercentile != 0 {
+		return nil, fmt.Errorf("--chunking-config cannot be combined with --chunking-strategy, --chunk-size, --chunk-overlap or --semantic-* flags")
+	}
+	config, err := loadChunkingConfigFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if strategy, _ := config["strategy"].(string); strategy == "None" && len(config["parameters"].(map[string]interface{})) == 0 {
+		return nil, nil
+	}
+	return config, nil
+}
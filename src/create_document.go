@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -16,9 +15,14 @@ var createDocumentCmd = &cobra.Command{
 Usage:
   maestro create document VDB_NAME COLLECTION_NAME DOC_NAME --file-name=FILE_NAME [options]
   maestro create document VDB_NAME COLLECTION_NAME DOC_NAME --doc-file-name=FILE_NAME [options]
+  maestro create document VDB_NAME COLLECTION_NAME DOC_NAME --url=URL [options]
+
+Use --file-name=- to read the document from standard input.
 
 Examples:
 	maestro create document my-database my-collection my-doc --file-name=document.txt
+	cat notes.md | maestro create document my-database my-collection my-doc --file-name=-
+	maestro create document my-database my-collection my-doc --url=https://example.com/guide.html
 	# NOTE: --embed is deprecated and ignored; embedding is per collection`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -81,6 +85,7 @@ Examples:
 // Flags for document creation
 var (
 	documentFileName  string
+	documentURL       string
 	documentEmbedding string
 )
 
@@ -98,6 +103,12 @@ func init() {
 	for _, cmd := range commands {
 		cmd.Flags().StringVar(&documentFileName, "file-name", "", "File name containing the document content")
 		cmd.Flags().StringVar(&documentFileName, "doc-file-name", "", "File name containing the document content (alias for file-name)")
+		cmd.Flags().StringVar(&documentURL, "url", "", "Fetch the document content over HTTP(S) instead of reading a file")
+		cmd.Flags().BoolVar(&extractRaw, "raw", false, "Send the file content as-is instead of extracting its text")
+		addMetadataFlags(cmd)
+		addUpsertFlags(cmd)
+		addSplitFlags(cmd)
+		addRedactFlags(cmd)
 		// DEPRECATED: embedding per document is ignored; kept temporarily for compatibility
 		cmd.Flags().StringVar(&documentEmbedding, "embed", "default", "(DEPRECATED) Embedding model for the document (ignored; embedding is per collection)")
 	}
@@ -111,26 +122,68 @@ func createDocument(vdbName, collectionName, docName string) error {
 		progress.Start()
 	}
 
-	// Validate that we have a file name
+	// Validate that we have a file name or URL
 	fileName := getDocumentFileName()
-	if fileName == "" {
+	if fileName == "" && documentURL == "" {
 		if progress != nil {
 			progress.StopWithError("File name is required")
 		}
-		return fmt.Errorf("file name is required (use --file-name or --doc-file-name)")
+		return fmt.Errorf("file name is required (use --file-name, --doc-file-name or --url)")
+	}
+
+	if err := validateDocumentName(docName); err != nil {
+		if progress != nil {
+			progress.StopWithError("Invalid document name")
+		}
+		return err
+	}
+	if keepVersions < 0 || (keepVersions > 0 && !documentUpsert) {
+		if progress != nil {
+			progress.StopWithError("Invalid --keep-versions")
+		}
+		return fmt.Errorf("--keep-versions must be a positive number and requires --upsert")
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+	// Check that the source exists
+	if err := validateDocumentSource(fileName, documentURL); err != nil {
+		if progress != nil {
+			progress.StopWithError("Invalid document source")
+		}
+		return err
+	}
+
+	if err := loadUserMetadata(); err != nil {
+		if progress != nil {
+			progress.StopWithError("Invalid metadata")
+		}
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		if progress != nil {
+			progress.StopWithError("Invalid size limits")
+		}
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		if progress != nil {
+			progress.StopWithError("Invalid redaction options")
+		}
+		return err
+	}
+	if splitBy != "" && documentUpsert {
 		if progress != nil {
-			progress.StopWithError("File not found")
+			progress.StopWithError("Invalid --split-by")
 		}
-		return fmt.Errorf("file not found: %s", fileName)
+		return fmt.Errorf("--split-by cannot be combined with --upsert")
 	}
 
 	if dryRun {
 		if !silent {
-			fmt.Printf("[DRY RUN] Would create document '%s' in collection '%s' of vector database '%s' from file '%s'\n", docName, collectionName, vdbName, fileName)
+			action := "create"
+			if documentUpsert {
+				action = "create or replace"
+			}
+			fmt.Printf("[DRY RUN] Would %s document '%s' in collection '%s' of vector database '%s' from %s%s%s\n", action, docName, collectionName, vdbName, describeDocumentSource(fileName, documentURL), describeKeepVersions(keepVersions), describeSplit())
 		}
 		if progress != nil {
 			progress.Stop("Dry run completed")
@@ -138,6 +191,30 @@ func createDocument(vdbName, collectionName, docName string) error {
 		return nil
 	}
 
+	if progress != nil {
+		progress.Update("Reading document...")
+	}
+
+	extracted, err := readDocumentSource(fileName, documentURL, extractRaw)
+	if err != nil {
+		if progress != nil {
+			progress.StopWithError("Failed to read document")
+		}
+		return err
+	}
+	if err := redactDocument(docName, extracted); err != nil {
+		if progress != nil {
+			progress.StopWithError("Document dropped")
+		}
+		return fmt.Errorf("document '%s' was not created: %w", docName, err)
+	}
+	if err := checkDocumentSize(docName, extracted.Text); err != nil {
+		if progress != nil {
+			progress.StopWithError("Document too large")
+		}
+		return err
+	}
+
 	if progress != nil {
 		progress.Update("Connecting to MCP server...")
 	}
@@ -215,16 +292,45 @@ func createDocument(vdbName, collectionName, docName string) error {
 		progress.Update("Checking for existing document...")
 	}
 
-	// Check if document already exists (simple check by listing documents)
-	documentsResult, err := client.ListDocumentsInCollection(vdbName, collectionName)
-	if err != nil {
-		// If we can't list documents, we'll proceed anyway
-		if verbose {
-			fmt.Printf("Warning: Could not check for existing documents: %v\n", err)
+	// With --upsert, an existing document is replaced instead
+	if documentUpsert {
+		names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+		if err != nil {
+			if progress != nil {
+				progress.StopWithError("Failed to list documents")
+			}
+			return err
+		}
+		if documentExists(names, docName) {
+			if progress != nil {
+				progress.Update("Replacing document...")
+			}
+			metadata := documentMetadata(extracted, userMetadata)
+			result, err := replaceStoredDocument(client, serverURI, vdbName, collectionName, docName, extracted.Text, metadata, names, keepVersions)
+			if err != nil {
+				if progress != nil {
+					progress.StopWithError("Failed to replace document")
+				}
+				return err
+			}
+			if progress != nil {
+				progress.Stop("Document replaced successfully")
+			}
+			if !silent {
+				printReplaceResult(docName, collectionName, vdbName, result)
+				printRedactionReport(redactionReport())
+			}
+			return nil
 		}
 	} else {
-		// Simple check if document name exists in the result
-		if strings.Contains(strings.ToLower(documentsResult), strings.ToLower(docName)) {
+		// Check if the document already exists, whole or as the parts of a split document
+		names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+		if err != nil {
+			// If we can't list documents, we'll proceed anyway
+			if verbose {
+				fmt.Printf("Warning: Could not check for existing documents: %v\n", err)
+			}
+		} else if documentExists(names, docName) {
 			if progress != nil {
 				progress.StopWithError("Document already exists")
 			}
@@ -232,6 +338,22 @@ func createDocument(vdbName, collectionName, docName string) error {
 		}
 	}
 
+	// A document larger than --max-size is written as parts with --split-by
+	if shouldSplitDocument(extracted.Text) {
+		if progress != nil {
+			progress.Stop(fmt.Sprintf("Splitting %s document", formatBytes(int64(len(extracted.Text)))))
+		}
+		parts, err := writeDocumentParts(client, serverURI, vdbName, collectionName, docName, extracted, userMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to create document '%s' in collection '%s' of vector database '%s': %w", docName, collectionName, vdbName, err)
+		}
+		if !silent {
+			fmt.Printf("✅ Document '%s' created successfully in collection '%s' of vector database '%s' as %d parts of up to %s\n", docName, collectionName, vdbName, parts, formatBytes(partBytes))
+			printRedactionReport(redactionReport())
+		}
+		return nil
+	}
+
 	if progress != nil {
 		progress.Update("Creating document...")
 	}
@@ -245,7 +367,7 @@ func createDocument(vdbName, collectionName, docName string) error {
 				createErr = fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", serverURI)
 			}
 		}()
-		createErr = client.WriteDocument(vdbName, collectionName, docName, fileName, documentEmbedding)
+		createErr = client.WriteDocument(vdbName, collectionName, docName, extracted, userMetadata, documentEmbedding)
 	}()
 
 	if createErr != nil {
@@ -261,6 +383,7 @@ func createDocument(vdbName, collectionName, docName string) error {
 
 	if !silent {
 		fmt.Printf("✅ Document '%s' created successfully in collection '%s' of vector database '%s' with embedding '%s'\n", docName, collectionName, vdbName, documentEmbedding)
+		printRedactionReport(redactionReport())
 	}
 
 	if verbose {
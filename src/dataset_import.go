@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Import formats
+const (
+	importFormatJSONL = "jsonl"
+	importFormatCSV   = "csv"
+)
+
+// maxImportErrors is the number of invalid records listed before an import is aborted
+const maxImportErrors = 10
+
+// Flags for dataset import
+var (
+	datasetImportFormat         string
+	datasetImportTextField      string
+	datasetImportNameField      string
+	datasetImportMetadataFields []string
+	datasetImportSkipInvalid    bool
+	datasetImportConcurrency    int
+	datasetImportOutput         string
+)
+
+var documentImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import the records of a JSONL or CSV dataset as documents",
+	Long: `Write every record of a JSONL or CSV file to a collection as one document per record.
+
+The document text is taken from --text-field and its name from --name-field; without --name-field,
+documents are named FILE-N after the file's base name and the record number. The record's other
+fields become the document's metadata, or only those listed with --metadata-fields. JSON numbers,
+booleans and strings keep their type; nested objects and arrays are stored as JSON strings.
+
+The format is taken from the file extension (.jsonl, .ndjson, .csv) unless --format is set, and is
+required when FILE is - (standard input). CSV files must start with a header row.
+
+Every record is validated before anything is written: a record without text or name, or with a
+duplicate name, aborts the import unless --skip-invalid is set. Records whose document already exists
+are skipped, so an interrupted import can simply be run again.`,
+	Example: `  maestro document import tickets.jsonl --vdb=my-vdb --collection=tickets --text-field=body --name-field=id
+  maestro document import tickets.jsonl --vdb=my-vdb --collection=tickets --text-field=body --name-field=id --metadata-fields=status,priority
+  maestro document import faq.csv --vdb=my-vdb --collection=faq --text-field=answer --name-field=question_id
+  export-tickets | maestro document import - --format=jsonl --vdb=my-vdb --collection=tickets --text-field=body`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return importDocuments(vdbName, collectionName, args[0])
+	},
+}
+
+func init() {
+	documentImportCmd.Flags().String("vdb", "", "Vector database name")
+	documentImportCmd.Flags().String("collection", "", "Collection name")
+	documentImportCmd.Flags().StringVar(&datasetImportFormat, "format", "", "Dataset format (jsonl, csv); defaults to the file extension")
+	documentImportCmd.Flags().StringVar(&datasetImportTextField, "text-field", "text", "Field holding the document text")
+	documentImportCmd.Flags().StringVar(&datasetImportNameField, "name-field", "", "Field holding the document name (default: FILE-N)")
+	documentImportCmd.Flags().StringSliceVar(&datasetImportMetadataFields, "metadata-fields", nil, "Fields stored as metadata (default: all fields except the text)")
+	documentImportCmd.Flags().BoolVar(&datasetImportSkipInvalid, "skip-invalid", false, "Skip invalid records instead of aborting")
+	documentImportCmd.Flags().IntVar(&datasetImportConcurrency, "concurrency", defaultConcurrency, "Number of documents to write in parallel")
+	documentImportCmd.Flags().StringVarP(&datasetImportOutput, "output", "o", "text", "Summary format (text, json)")
+}
+
+// importRow is a record of a dataset as read from the file
+type importRow struct {
+	Line   int
+	Fields map[string]interface{}
+}
+
+// importFields selects the fields of a record that make up a document
+type importFields struct {
+	Text     string
+	Name     string
+	Metadata []string
+}
+
+// importRecord is a record converted to a document
+type importRecord struct {
+	Line     int
+	Name     string
+	Text     string
+	Metadata map[string]interface{}
+}
+
+// ImportFailure records a record that could not be written
+type ImportFailure struct {
+	Line     int    `json:"line"`
+	Document string `json:"document"`
+	Error    string `json:"error"`
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Source     string          `json:"source"`
+	Database   string          `json:"database"`
+	Collection string          `json:"collection"`
+	Total      int             `json:"total"`
+	Imported   int             `json:"imported"`
+	Existing   int             `json:"existing"`
+	Invalid    int             `json:"invalid"`
+	Failed     int             `json:"failed"`
+	Duration   float64         `json:"duration_seconds"`
+	Failures   []ImportFailure `json:"failures"`
+}
+
+func importDocuments(vdbName, collectionName, source string) error {
+	if datasetImportOutput != "text" && datasetImportOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", datasetImportOutput)
+	}
+	format, err := importFormatFor(source, datasetImportFormat)
+	if err != nil {
+		return err
+	}
+	fields := importFields{Text: datasetImportTextField, Name: datasetImportNameField, Metadata: datasetImportMetadataFields}
+	if fields.Text == "" {
+		return fmt.Errorf("--text-field cannot be empty")
+	}
+
+	rows, err := readImportSource(source, format)
+	if err != nil {
+		return err
+	}
+	records, invalid := buildImportRecords(rows, fields, importBaseName(source))
+	if len(invalid) > 0 {
+		if !datasetImportSkipInvalid {
+			return invalidImportError(invalid)
+		}
+		if !silent {
+			for _, message := range invalid {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s\n", message)
+			}
+		}
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no records to import from %s", source)
+	}
+
+	if verbose {
+		fmt.Printf("Importing %d record(s) into collection '%s' of vector database '%s'...\n", len(records), collectionName, vdbName)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would import %d record(s) from %s into collection '%s' of vector database '%s'\n", len(records), source, collectionName, vdbName)
+			for _, record := range records {
+				fmt.Printf("  line %d → %s (%d metadata field(s))\n", record.Line, record.Name, len(record.Metadata))
+			}
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return err
+	}
+	existing, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		present[name] = true
+	}
+	var pending []importRecord
+	for _, record := range records {
+		if !present[record.Name] {
+			pending = append(pending, record)
+		}
+	}
+
+	report := importRecords(client, serverURI, vdbName, collectionName, pending)
+	report.Source = source
+	report.Total = len(rows)
+	report.Existing = len(records) - len(pending)
+	report.Invalid = len(invalid)
+
+	if datasetImportOutput == "json" {
+		if err := printJSON(report); err != nil {
+			return fmt.Errorf("failed to encode import report: %w", err)
+		}
+	} else if !silent {
+		printImportReport(report)
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d record(s) failed to import; run the import again to retry them", report.Failed, len(pending))
+	}
+	return nil
+}
+
+// importRecords writes records concurrently and reports the failures
+func importRecords(client *MCPClient, serverURI, vdbName, collectionName string, records []importRecord) *ImportReport {
+	start := time.Now()
+	progress := newBulkProgress(fmt.Sprintf("Importing %d record(s) into '%s'...", len(records), collectionName), len(records))
+	progress.SetUnit("records")
+
+	report := &ImportReport{Database: vdbName, Collection: collectionName, Failures: []ImportFailure{}}
+	var mu sync.Mutex
+	runConcurrently(records, datasetImportConcurrency, func(record importRecord) {
+		defer progress.Increment()
+
+		err := safeCall(serverURI, func() error {
+			return client.WriteDocumentText(vdbName, collectionName, record.Name, record.Text, "", record.Metadata)
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Failures = append(report.Failures, ImportFailure{Line: record.Line, Document: record.Name, Error: err.Error()})
+			return
+		}
+		report.Imported++
+	})
+
+	report.Failed = len(report.Failures)
+	report.Duration = time.Since(start).Seconds()
+	sort.Slice(report.Failures, func(i, j int) bool { return report.Failures[i].Line < report.Failures[j].Line })
+	progress.Stop(fmt.Sprintf("Imported %d record(s)", report.Imported), report.Failed)
+	return report
+}
+
+func printImportReport(report *ImportReport) {
+	var notes []string
+	if report.Existing > 0 {
+		notes = append(notes, fmt.Sprintf("%d already present", report.Existing))
+	}
+	if report.Invalid > 0 {
+		notes = append(notes, fmt.Sprintf("%d invalid", report.Invalid))
+	}
+	suffix := ""
+	if len(notes) > 0 {
+		suffix = " (" + strings.Join(notes, ", ") + " skipped)"
+	}
+
+	if report.Failed == 0 {
+		fmt.Printf("✅ Imported %d of %d record(s) from %s into collection '%s' of vector database '%s' in %.2fs%s\n",
+			report.Imported, report.Total, report.Source, report.Collection, report.Database, report.Duration, suffix)
+		return
+	}
+
+	fmt.Printf("Imported %d of %d record(s) from %s into collection '%s' of vector database '%s' in %.2fs%s\n",
+		report.Imported, report.Total, report.Source, report.Collection, report.Database, report.Duration, suffix)
+	fmt.Printf("\n%d failure(s):\n", report.Failed)
+	for _, failure := range report.Failures {
+		fmt.Printf("  ❌ line %d (%s): %s\n", failure.Line, failure.Document, failure.Error)
+	}
+}
+
+// importFormatFor returns the dataset format from the flag or the file extension
+func importFormatFor(source, format string) (string, error) {
+	if format != "" {
+		format = strings.ToLower(format)
+		if format != importFormatJSONL && format != importFormatCSV {
+			return "", fmt.Errorf("unsupported format '%s' (use jsonl or csv)", format)
+		}
+		return format, nil
+	}
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".jsonl", ".ndjson":
+		return importFormatJSONL, nil
+	case ".csv":
+		return importFormatCSV, nil
+	}
+	return "", fmt.Errorf("cannot infer the format of '%s'; use --format jsonl or --format csv", source)
+}
+
+// importBaseName returns the prefix of generated document names
+func importBaseName(source string) string {
+	if source == stdinFileName {
+		return "stdin"
+	}
+	base := filepath.Base(source)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// readImportSource reads the rows of a dataset file, or of standard input for "-"
+func readImportSource(source, format string) ([]importRow, error) {
+	var r io.Reader = os.Stdin
+	if source != stdinFileName {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open '%s': %w", source, err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	rows, err := readImportRows(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return rows, nil
+}
+
+// readImportRows parses JSONL objects or CSV rows keyed by the header
+func readImportRows(r io.Reader, format string) ([]importRow, error) {
+	if format == importFormatCSV {
+		return readCSVRows(r)
+	}
+	return readJSONLRows(r)
+}
+
+func readJSONLRows(r io.Reader) ([]importRow, error) {
+	reader := bufio.NewReader(r)
+	var rows []importRow
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(trimmed))
+			decoder.UseNumber()
+			var fields map[string]interface{}
+			if decodeErr := decoder.Decode(&fields); decodeErr != nil || fields == nil {
+				return nil, fmt.Errorf("line %d: expected a JSON object", line)
+			}
+			rows = append(rows, importRow{Line: line, Fields: fields})
+		}
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+	}
+}
+
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+	seen := make(map[string]bool, len(header))
+	for _, column := range header {
+		if column == "" || seen[column] {
+			return nil, fmt.Errorf("header has an empty or duplicate column '%s'", column)
+		}
+		seen[column] = true
+	}
+
+	var rows []importRow
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		fields := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			fields[column] = values[i]
+		}
+		rows = append(rows, importRow{Line: line, Fields: fields})
+	}
+}
+
+// buildImportRecords converts rows to documents. Rows without text or name, or with a name already
+// used by an earlier row, are returned as messages instead.
+func buildImportRecords(rows []importRow, fields importFields, baseName string) ([]importRecord, []string) {
+	var records []importRecord
+	var invalid []string
+	names := make(map[string]int, len(rows))
+	for i, row := range rows {
+		text, _ := importFieldString(row.Fields[fields.Text])
+		if strings.TrimSpace(text) == "" {
+			invalid = append(invalid, fmt.Sprintf("line %d: missing text field '%s'", row.Line, fields.Text))
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%d", baseName, i+1)
+		if fields.Name != "" {
+			name, _ = importFieldString(row.Fields[fields.Name])
+			if name = strings.TrimSpace(name); name == "" {
+				invalid = append(invalid, fmt.Sprintf("line %d: missing name field '%s'", row.Line, fields.Name))
+				continue
+			}
+		}
+		if line, ok := names[name]; ok {
+			invalid = append(invalid, fmt.Sprintf("line %d: duplicate document name '%s' (first used on line %d)", row.Line, name, line))
+			continue
+		}
+		names[name] = row.Line
+
+		metadata := map[string]interface{}{}
+		keys := fields.Metadata
+		if len(keys) == 0 {
+			keys = sortedKeys(row.Fields)
+		}
+		for _, key := range keys {
+			if value, ok := row.Fields[key]; ok && value != nil && (len(fields.Metadata) > 0 || key != fields.Text) {
+				metadata[key] = flattenMetadataValue(value)
+			}
+		}
+		records = append(records, importRecord{Line: row.Line, Name: name, Text: text, Metadata: metadata})
+	}
+	return records, invalid
+}
+
+// invalidImportError lists the first invalid records of an aborted import
+func invalidImportError(invalid []string) error {
+	shown := invalid
+	if len(shown) > maxImportErrors {
+		shown = shown[:maxImportErrors]
+	}
+	message := fmt.Sprintf("%d invalid record(s); nothing was imported (use --skip-invalid to import the rest):\n  %s", len(invalid), strings.Join(shown, "\n  "))
+	if len(invalid) > len(shown) {
+		message += fmt.Sprintf("\n  ... and %d more", len(invalid)-len(shown))
+	}
+	return errors.New(message)
+}
+
+// importFieldString renders a field value as text; false means the field is missing or null
+func importFieldString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprintf("%t", v), true
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded), true
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadJSONLRows(t *testing.T) {
+	rows, err := readImportRows(strings.NewReader("{\"id\": \"T-1\", \"body\": \"Printer is down\", \"priority\": 2}\n\n{\"id\": \"T-2\", \"body\": \"Reset password\", \"tags\": [\"auth\"]}"), importFormatJSONL)
+	if err != nil {
+		t.Fatalf("readImportRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Line != 1 || rows[1].Line != 3 {
+		t.Fatalf("readImportRows() = %+v, expected rows on lines 1 and 3", rows)
+	}
+
+	if _, err := readImportRows(strings.NewReader("{\"id\": 1}\n[1, 2]\n"), importFormatJSONL); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("readImportRows() error = %v, expected an error on line 2", err)
+	}
+}
+
+func TestReadCSVRows(t *testing.T) {
+	rows, err := readImportRows(strings.NewReader("\uFEFFid,body,status\nT-1,\"Printer is down,\nagain\",open\nT-2,Reset password,closed\n"), importFormatCSV)
+	if err != nil {
+		t.Fatalf("readImportRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[1].Line != 4 {
+		t.Fatalf("readImportRows() = %+v, expected the second row on line 4", rows)
+	}
+	if rows[0].Fields["id"] != "T-1" || rows[0].Fields["body"] != "Printer is down,\nagain" {
+		t.Errorf("readImportRows() first row = %v", rows[0].Fields)
+	}
+
+	if _, err := readImportRows(strings.NewReader("id,id\n1,2\n"), importFormatCSV); err == nil {
+		t.Error("readImportRows() expected an error for a duplicate column")
+	}
+}
+
+func TestBuildImportRecords(t *testing.T) {
+	rows, err := readImportRows(strings.NewReader(`{"id": "T-1", "body": "Printer is down", "priority": 2, "meta": {"team": "it"}}
+{"id": "T-2", "body": ""}
+{"id": "T-1", "body": "Duplicate"}
+{"body": "No id"}
+`), importFormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, invalid := buildImportRecords(rows, importFields{Text: "body", Name: "id"}, "tickets")
+	if len(records) != 1 || records[0].Name != "T-1" || records[0].Text != "Printer is down" {
+		t.Fatalf("buildImportRecords() = %+v, expected only T-1", records)
+	}
+	expected := map[string]interface{}{"id": "T-1", "priority": int64(2), "meta": `{"team":"it"}`}
+	if !reflect.DeepEqual(records[0].Metadata, expected) {
+		t.Errorf("buildImportRecords() metadata = %v, expected %v", records[0].Metadata, expected)
+	}
+	if len(invalid) != 3 || !strings.Contains(invalid[1], "duplicate document name 'T-1'") {
+		t.Errorf("buildImportRecords() invalid = %v", invalid)
+	}
+
+	records, _ = buildImportRecords(rows, importFields{Text: "body", Metadata: []string{"priority", "missing"}}, "tickets")
+	if records[0].Name != "tickets-1" || !reflect.DeepEqual(records[0].Metadata, map[string]interface{}{"priority": int64(2)}) {
+		t.Errorf("buildImportRecords() with generated names = %+v", records[0])
+	}
+}
+
+func TestImportFormatFor(t *testing.T) {
+	tests := map[string]string{"tickets.jsonl": "jsonl", "data.NDJSON": "jsonl", "faq.csv": "csv"}
+	for source, expected := range tests {
+		if got, err := importFormatFor(source, ""); err != nil || got != expected {
+			t.Errorf("importFormatFor(%q) = %q, %v, expected %q", source, got, err, expected)
+		}
+	}
+	if got, err := importFormatFor("-", "CSV"); err != nil || got != "csv" {
+		t.Errorf("importFormatFor() with --format = %q, %v", got, err)
+	}
+	if _, err := importFormatFor("data.txt", ""); err == nil {
+		t.Error("importFormatFor() expected an error for an unknown extension")
+	}
+	if _, err := importFormatFor("data.jsonl", "xml"); err == nil {
+		t.Error("importFormatFor() expected an error for an unsupported format")
+	}
+}
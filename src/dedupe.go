@@ -0,0 +1,524 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Near-duplicate handling modes for --dedupe
+const (
+	dedupeModeWarn = "warn"
+	dedupeModeSkip = "skip"
+)
+
+// defaultDedupeThreshold is the estimated shingle similarity above which documents are near-duplicates
+const defaultDedupeThreshold = 0.9
+
+// Flags for near-duplicate detection during ingestion
+var (
+	dedupeMode      string
+	dedupeThreshold float64
+)
+
+// addDedupeFlags adds --dedupe and --dedupe-threshold to a command that ingests files
+func addDedupeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&dedupeMode, "dedupe", "", "Check files for near-duplicates in the batch and the collection (warn, skip)")
+	cmd.Flags().Float64Var(&dedupeThreshold, "dedupe-threshold", defaultDedupeThreshold, "Similarity (0-1] above which documents are near-duplicates")
+}
+
+// validateDedupe checks --dedupe and --dedupe-threshold
+func validateDedupe() error {
+	if dedupeMode != "" && dedupeMode != dedupeModeWarn && dedupeMode != dedupeModeSkip {
+		return fmt.Errorf("unsupported dedupe mode '%s' (use warn or skip)", dedupeMode)
+	}
+	return validateSimilarityThreshold(dedupeThreshold)
+}
+
+// validateSimilarityThreshold checks a near-duplicate similarity threshold
+func validateSimilarityThreshold(threshold float64) error {
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("similarity threshold must be greater than 0 and at most 1, got %g", threshold)
+	}
+	return nil
+}
+
+// DuplicateMatch records a file found to be a near-duplicate of a document in the batch or the collection
+type DuplicateMatch struct {
+	Document        string  `json:"document"`
+	Path            string  `json:"path"`
+	DuplicateOf     string  `json:"duplicate_of"`
+	Existing        bool    `json:"existing"`
+	Similarity      float64 `json:"similarity"`
+	SimHashDistance int     `json:"simhash_distance"`
+	Action          string  `json:"action"`
+}
+
+// DuplicateDocumentError reports a file that was not written because it is a near-duplicate
+type DuplicateDocumentError struct {
+	Match DuplicateMatch
+}
+
+func (e *DuplicateDocumentError) Error() string {
+	return fmt.Sprintf("skipped as a near-duplicate of '%s' (similarity %.2f)", e.Match.DuplicateOf, e.Match.Similarity)
+}
+
+// isDuplicateSkip reports whether err is a DuplicateDocumentError
+func isDuplicateSkip(err error) bool {
+	var dupErr *DuplicateDocumentError
+	return errors.As(err, &dupErr)
+}
+
+// duplicatePlan maps the document names of an ingestion run to the near-duplicates found for them
+// before any file is written; it is empty without --dedupe
+var duplicatePlan map[string]DuplicateMatch
+
+// planDuplicates fingerprints the files of an ingestion run and compares them, in order, against the
+// fingerprinted documents of the collection and the files before them. With --dedupe skip a
+// near-duplicate is not written, so later files are compared only against files that are.
+func planDuplicates(client *MCPClient, serverURI, vdbName, collectionName string, files []ingestFile, concurrency int) ([]DuplicateMatch, error) {
+	duplicatePlan = nil
+	if dedupeMode == "" {
+		return nil, nil
+	}
+
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	batch := make(map[string]bool, len(files))
+	for _, file := range files {
+		batch[file.Name] = true
+	}
+
+	index := newFingerprintIndex()
+	existing := make(map[string]bool)
+	unfingerprinted := 0
+	for _, entry := range documentListEntries(entries) {
+		name := entry.Name
+		if parent, _, ok := parseDocumentPartName(name); ok {
+			name = parent
+		}
+		if batch[name] || !isDedupeCandidate(entry) {
+			continue
+		}
+		fp := fingerprintFromMetadata(entry.Metadata)
+		if fp == nil {
+			unfingerprinted++
+			continue
+		}
+		index.Add(entry.Name, fp)
+		existing[entry.Name] = true
+	}
+	if verbose && unfingerprinted > 0 {
+		fmt.Printf("%d existing document(s) have no fingerprint and are not compared; 'maestro collection dedupe' checks them\n", unfingerprinted)
+	}
+
+	fingerprints := fingerprintFiles(files, concurrency)
+
+	duplicatePlan = make(map[string]DuplicateMatch)
+	var matches []DuplicateMatch
+	for i, file := range files {
+		fp := fingerprints[i]
+		if fp == nil {
+			continue
+		}
+		name, other, similarity, found := index.Nearest(fp, dedupeThreshold)
+		if !found {
+			index.Add(file.Name, fp)
+			continue
+		}
+		match := DuplicateMatch{
+			Document:        file.Name,
+			Path:            file.Path,
+			DuplicateOf:     name,
+			Existing:        existing[name],
+			Similarity:      similarity,
+			SimHashDistance: fp.SimHashDistance(other),
+			Action:          "warned",
+		}
+		if dedupeMode == dedupeModeSkip {
+			match.Action = "skipped"
+		} else {
+			index.Add(file.Name, fp)
+		}
+		duplicatePlan[file.Name] = match
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// fingerprintFiles reads, extracts and fingerprints files concurrently. Files that cannot be read or
+// extracted get no fingerprint; their ingestion reports the error.
+func fingerprintFiles(files []ingestFile, concurrency int) []*Fingerprint {
+	progress := newBulkProgress(fmt.Sprintf("Fingerprinting %d file(s)...", len(files)), len(files))
+	progress.SetUnit("files")
+
+	fingerprints := make([]*Fingerprint, len(files))
+	indexes := make([]int, len(files))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	runConcurrently(indexes, concurrency, func(i int) {
+		defer progress.Increment()
+		content, err := os.ReadFile(files[i].Path)
+		if err != nil {
+			return
+		}
+		extracted, err := extractText(files[i].Path, content, extractRaw)
+		if err != nil {
+			return
+		}
+		fingerprints[i] = computeFingerprint(extracted.Text)
+	})
+	progress.Stop(fmt.Sprintf("Fingerprinted %d file(s)", len(files)), 0)
+	return fingerprints
+}
+
+// checkDuplicate returns a DuplicateDocumentError for a document that --dedupe skip decided not to write
+func checkDuplicate(docName string) error {
+	if match, ok := duplicatePlan[docName]; ok && match.Action == "skipped" {
+		return &DuplicateDocumentError{Match: match}
+	}
+	return nil
+}
+
+// isDedupeCandidate reports whether a listed document takes part in near-duplicate detection;
+// saved versions and staged updates are copies of a document by design
+func isDedupeCandidate(entry DocumentListEntry) bool {
+	if strings.HasSuffix(entry.Name, documentStagingSuffix) {
+		return false
+	}
+	_, isVersion := entry.Metadata[versionOfMetadataKey]
+	return !isVersion
+}
+
+// printDuplicateMatches prints the near-duplicates found during an ingestion run as text
+func printDuplicateMatches(matches []DuplicateMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	fmt.Printf("\n%d near-duplicate(s) (threshold %.2f):\n", len(matches), dedupeThreshold)
+	for _, match := range matches {
+		source := "batch"
+		if match.Existing {
+			source = "collection"
+		}
+		fmt.Printf("  ⚠️  %s ≈ %s (%s, similarity %.2f): %s\n", match.Document, match.DuplicateOf, source, match.Similarity, match.Action)
+	}
+}
+
+// Flags for collection dedupe
+var (
+	collectionDedupeDelete      bool
+	collectionDedupeThreshold   float64
+	collectionDedupeOutput      string
+	collectionDedupeConcurrency int
+)
+
+var collectionDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find and remove near-duplicate documents",
+	Long: `Group the documents of a collection into clusters of near-duplicates and list them. With --delete,
+all but the first document (by name) of each cluster are deleted after a confirmation.
+
+Documents are compared by the estimated Jaccard similarity of their word shingles, computed from
+MinHash signatures. 'document ingest' stores the SimHash and MinHash fingerprints of every document
+in its metadata; documents without them are fetched and fingerprinted locally. Documents whose
+similarity reaches --threshold are linked, and linked documents form a cluster. The parts of a split
+document are joined and compared as one document. Saved versions and staged updates are ignored.`,
+	Example: `  maestro collection dedupe --vdb=my-vdb --name=my-collection
+  maestro collection dedupe --vdb=my-vdb --name=my-collection --threshold=0.8 -o json
+  maestro collection dedupe --vdb=my-vdb --name=my-collection --delete --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("name")
+
+		// Interactive selection if missing
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return dedupeCollection(vdbName, collectionName)
+	},
+}
+
+func init() {
+	collectionDedupeCmd.Flags().String("vdb", "", "Vector database name")
+	collectionDedupeCmd.Flags().String("name", "", "Collection name")
+	collectionDedupeCmd.Flags().BoolVar(&collectionDedupeDelete, "delete", false, "Delete all but the first document of each cluster")
+	collectionDedupeCmd.Flags().Float64Var(&collectionDedupeThreshold, "threshold", defaultDedupeThreshold, "Similarity (0-1] above which documents are near-duplicates")
+	collectionDedupeCmd.Flags().StringVarP(&collectionDedupeOutput, "output", "o", "text", "Output format (text, json)")
+	collectionDedupeCmd.Flags().IntVar(&collectionDedupeConcurrency, "concurrency", defaultConcurrency, "Number of documents to fetch or delete in parallel")
+}
+
+// DedupeReport lists the clusters of near-duplicates of a collection
+type DedupeReport struct {
+	Database      string             `json:"database"`
+	Collection    string             `json:"collection"`
+	Threshold     float64            `json:"threshold"`
+	Documents     int                `json:"documents"`
+	Fingerprinted int                `json:"fingerprinted"`
+	Clusters      []DuplicateCluster `json:"clusters"`
+	Deletion      *DeleteReport      `json:"deletion,omitempty"`
+}
+
+// DuplicateCluster is a group of near-duplicate documents; Keep is the document dedupe keeps
+type DuplicateCluster struct {
+	Keep    string          `json:"keep"`
+	Members []ClusterMember `json:"members"`
+}
+
+// ClusterMember is a document of a cluster with its highest similarity to another member
+type ClusterMember struct {
+	Document   string  `json:"document"`
+	Similarity float64 `json:"similarity"`
+}
+
+func dedupeCollection(vdbName, collectionName string) error {
+	if collectionDedupeOutput != "text" && collectionDedupeOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", collectionDedupeOutput)
+	}
+	if err := validateSimilarityThreshold(collectionDedupeThreshold); err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Finding near-duplicates in collection '%s' of vector database '%s'...\n", collectionName, vdbName)
+	}
+
+	if dryRun {
+		if !silent {
+			action := "report near-duplicate documents"
+			if collectionDedupeDelete {
+				action = "delete near-duplicate documents"
+			}
+			fmt.Printf("[DRY RUN] Would %s in collection '%s' of vector database '%s' (threshold %.2f)\n", action, collectionName, vdbName, collectionDedupeThreshold)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return err
+	}
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+
+	fingerprints, missing, parts := planDedupeScan(documentListEntries(entries))
+	fetched := 0
+	if len(missing) > 0 {
+		documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, missing, collectionDedupeConcurrency)
+		grouped, incomplete := groupDocumentParts(documents)
+		if failures = append(failures, incomplete...); len(failures) > 0 {
+			for _, failure := range failures {
+				fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+			}
+			return fmt.Errorf("failed to read %d of %d document(s) from collection '%s'", len(failures), len(missing), collectionName)
+		}
+		for _, doc := range grouped {
+			if fp := computeFingerprint(doc.Text); fp != nil {
+				fingerprints[doc.Name] = fp
+			}
+		}
+		fetched = len(grouped)
+	}
+
+	report := &DedupeReport{
+		Database:      vdbName,
+		Collection:    collectionName,
+		Threshold:     collectionDedupeThreshold,
+		Documents:     len(fingerprints),
+		Fingerprinted: fetched,
+		Clusters:      clusterDuplicates(fingerprints, collectionDedupeThreshold),
+	}
+
+	if collectionDedupeDelete && len(report.Clusters) > 0 {
+		var names []string
+		for _, cluster := range report.Clusters {
+			for _, member := range cluster.Members {
+				if member.Document == cluster.Keep {
+					continue
+				}
+				if partNames, ok := parts[member.Document]; ok {
+					names = append(names, partNames...)
+				} else {
+					names = append(names, member.Document)
+				}
+			}
+		}
+		if collectionDedupeOutput == "text" && !silent {
+			printDedupeReport(report)
+			fmt.Println()
+		}
+		if err := confirmDestructiveOperation(fmt.Sprintf("delete %d near-duplicate document(s) from collection", len(names)), collectionName); err != nil {
+			return err
+		}
+		report.Deletion = deleteDocumentNames(client, serverURI, vdbName, collectionName, names, collectionDedupeConcurrency)
+		if collectionDedupeOutput == "json" {
+			if err := printJSON(report); err != nil {
+				return fmt.Errorf("failed to encode dedupe report: %w", err)
+			}
+		} else if !silent {
+			printDeleteReport(report.Deletion, collectionName, vdbName)
+		}
+		if failed := len(report.Deletion.Failures); failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d near-duplicate document(s)", failed, len(names))
+		}
+		return nil
+	}
+
+	if collectionDedupeOutput == "json" {
+		if err := printJSON(report); err != nil {
+			return fmt.Errorf("failed to encode dedupe report: %w", err)
+		}
+		return nil
+	}
+	if !silent {
+		printDedupeReport(report)
+		if len(report.Clusters) > 0 {
+			fmt.Println("\nRun with --delete to delete all but the kept document of each cluster")
+		}
+	}
+	return nil
+}
+
+// planDedupeScan returns the stored fingerprints of the documents of a collection and the names to
+// fetch for the others. The parts of a split document are always fetched, to be joined and
+// fingerprinted as one document; parts maps each split document to its part names.
+func planDedupeScan(entries []DocumentListEntry) (map[string]*Fingerprint, []string, map[string][]string) {
+	fingerprints := make(map[string]*Fingerprint)
+	parts := make(map[string][]string)
+	var missing []string
+	for _, entry := range entries {
+		if !isDedupeCandidate(entry) {
+			continue
+		}
+		if parent, _, ok := parseDocumentPartName(entry.Name); ok {
+			parts[parent] = append(parts[parent], entry.Name)
+			missing = append(missing, entry.Name)
+			continue
+		}
+		if fp := fingerprintFromMetadata(entry.Metadata); fp != nil {
+			fingerprints[entry.Name] = fp
+		} else {
+			missing = append(missing, entry.Name)
+		}
+	}
+	return fingerprints, missing, parts
+}
+
+// clusterDuplicates links every pair of documents whose similarity reaches threshold and returns the
+// connected groups, sorted by their first document. Each cluster keeps its first document by name.
+func clusterDuplicates(fingerprints map[string]*Fingerprint, threshold float64) []DuplicateCluster {
+	names := sortedKeys(fingerprints)
+	parent := make([]int, len(names))
+	best := make([]float64, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	index := newFingerprintIndex()
+	for i, name := range names {
+		fp := fingerprints[name]
+		for _, j := range index.candidates(fp) {
+			similarity := fp.Similarity(index.fingerprints[j])
+			if similarity < threshold {
+				continue
+			}
+			if root, other := find(i), find(j); root != other {
+				// keep the smaller index as root so that the root is the first name of the cluster
+				if root < other {
+					parent[other] = root
+				} else {
+					parent[root] = other
+				}
+			}
+			best[i] = max(best[i], similarity)
+			best[j] = max(best[j], similarity)
+		}
+		index.Add(name, fp)
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range names {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	clusters := []DuplicateCluster{}
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+		cluster := DuplicateCluster{Keep: names[root]}
+		for _, i := range members {
+			cluster.Members = append(cluster.Members, ClusterMember{Document: names[i], Similarity: best[i]})
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Keep < clusters[j].Keep })
+	return clusters
+}
+
+// printDedupeReport prints the clusters of near-duplicates as text
+func printDedupeReport(report *DedupeReport) {
+	if len(report.Clusters) == 0 {
+		fmt.Printf("✅ No near-duplicates among %d document(s) in collection '%s' of vector database '%s' (threshold %.2f)\n",
+			report.Documents, report.Collection, report.Database, report.Threshold)
+		return
+	}
+
+	duplicates := 0
+	for _, cluster := range report.Clusters {
+		duplicates += len(cluster.Members) - 1
+	}
+	fmt.Printf("Found %d cluster(s) with %d near-duplicate(s) among %d document(s) in collection '%s' of vector database '%s' (threshold %.2f)\n",
+		len(report.Clusters), duplicates, report.Documents, report.Collection, report.Database, report.Threshold)
+	for i, cluster := range report.Clusters {
+		fmt.Printf("\nCluster %d (%d documents):\n", i+1, len(cluster.Members))
+		for _, member := range cluster.Members {
+			note := fmt.Sprintf("similarity %.2f", member.Similarity)
+			if member.Document == cluster.Keep {
+				note += ", keep"
+			}
+			fmt.Printf("  %s (%s)\n", member.Document, note)
+		}
+	}
+}
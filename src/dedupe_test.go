@@ -0,0 +1,118 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClusterDuplicates(t *testing.T) {
+	a := sampleText("alpha", 300)
+	b := sampleText("beta", 300)
+	fingerprints := map[string]*Fingerprint{
+		"a.md":      computeFingerprint(a),
+		"a-copy.md": computeFingerprint(a + " copied"),
+		"b.md":      computeFingerprint(b),
+		"0-b.md":    computeFingerprint(b),
+		"c.md":      computeFingerprint(sampleText("gamma", 300)),
+	}
+
+	clusters := clusterDuplicates(fingerprints, 0.9)
+	if len(clusters) != 2 {
+		t.Fatalf("clusterDuplicates() = %+v, expected 2 clusters", clusters)
+	}
+	if clusters[0].Keep != "0-b.md" || clusters[1].Keep != "a-copy.md" {
+		t.Errorf("kept documents = %s, %s; expected the first name of each cluster", clusters[0].Keep, clusters[1].Keep)
+	}
+	var members []string
+	for _, member := range clusters[1].Members {
+		members = append(members, member.Document)
+		if member.Similarity < 0.9 {
+			t.Errorf("%s: similarity = %.2f, expected at least 0.9", member.Document, member.Similarity)
+		}
+	}
+	if !reflect.DeepEqual(members, []string{"a-copy.md", "a.md"}) {
+		t.Errorf("cluster members = %v", members)
+	}
+
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	defer func() { duplicatePlan = nil }()
+	duplicatePlan = map[string]DuplicateMatch{
+		"copy.md": {Document: "copy.md", DuplicateOf: "page.md", Similarity: 0.95, Action: "skipped"},
+		"near.md": {Document: "near.md", DuplicateOf: "page.md", Similarity: 0.92, Action: "warned"},
+	}
+
+	err := checkDuplicate("copy.md")
+	if !isDuplicateSkip(err) || err.Error() != "skipped as a near-duplicate of 'page.md' (similarity 0.95)" {
+		t.Errorf("checkDuplicate(copy.md) = %v", err)
+	}
+	if err := checkDuplicate("near.md"); err != nil {
+		t.Errorf("a warned near-duplicate should be written, got %v", err)
+	}
+	if err := checkDuplicate("page.md"); err != nil {
+		t.Errorf("checkDuplicate(page.md) = %v", err)
+	}
+}
+
+func TestIngestManifestRecordsSkippedDuplicate(t *testing.T) {
+	ingestManifestDir = t.TempDir()
+	defer func() { ingestManifestDir = filepath.Join(".maestro", "ingest") }()
+
+	files := []ingestFile{{Path: "docs/a.md", Name: "a.md"}, {Path: "docs/b.md", Name: "b.md"}}
+	manifest, err := newIngestManifest("vdb", "coll", []string{"docs"}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dupErr := &DuplicateDocumentError{Match: DuplicateMatch{Document: "b.md", DuplicateOf: "a.md", Similarity: 1}}
+	if err := manifest.record("b.md", "def", dupErr); err != nil {
+		t.Fatal(err)
+	}
+	if b := manifest.Files[1]; b.Status != ingestStatusDropped || b.Error != "skipped as a near-duplicate of 'a.md' (similarity 1.00)" {
+		t.Errorf("skipped entry = %+v", b)
+	}
+	if remaining := manifest.remaining(); len(remaining) != 1 || remaining[0].Name != "a.md" {
+		t.Errorf("remaining() = %+v, expected only a.md", remaining)
+	}
+}
+
+func TestIsDedupeCandidate(t *testing.T) {
+	tests := []struct {
+		entry    DocumentListEntry
+		expected bool
+	}{
+		{DocumentListEntry{Name: "page.md"}, true},
+		{DocumentListEntry{Name: "page.md#part-2"}, true},
+		{DocumentListEntry{Name: "page.md" + documentStagingSuffix}, false},
+		{DocumentListEntry{Name: "page.md@v1", Metadata: map[string]interface{}{versionOfMetadataKey: "page.md"}}, false},
+	}
+	for _, test := range tests {
+		if got := isDedupeCandidate(test.entry); got != test.expected {
+			t.Errorf("isDedupeCandidate(%s) = %v, expected %v", test.entry.Name, got, test.expected)
+		}
+	}
+}
+
+func TestPlanDedupeScan(t *testing.T) {
+	stored := computeFingerprint(sampleText("alpha", 300))
+	entries := []DocumentListEntry{
+		{Name: "a.md", Metadata: stored.Metadata()},
+		{Name: "b.md"},
+		{Name: "big.md#part-1", Metadata: stored.Metadata()},
+		{Name: "big.md#part-2"},
+		{Name: "a.md" + documentStagingSuffix},
+	}
+	fingerprints, missing, parts := planDedupeScan(entries)
+	if len(fingerprints) != 1 || fingerprints["a.md"] == nil {
+		t.Errorf("fingerprints = %v, want only a.md", fingerprints)
+	}
+	// parts are fetched to be joined, even when a part has a fingerprint of its own
+	if expected := []string{"b.md", "big.md#part-1", "big.md#part-2"}; !reflect.DeepEqual(missing, expected) {
+		t.Errorf("missing = %v, expected %v", missing, expected)
+	}
+	if expected := map[string][]string{"big.md": {"big.md#part-1", "big.md#part-2"}}; !reflect.DeepEqual(parts, expected) {
+		t.Errorf("parts = %v, expected %v", parts, expected)
+	}
+}
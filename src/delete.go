@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -162,7 +163,22 @@ func deleteDocument(vdbName, collectionName, docName string) error {
 
 	// Perform the deletion
 	if err := performDocumentDeletion(vdbName, collectionName, docName); err != nil {
-		return fmt.Errorf("deletion failed: %w", err)
+		// A document written with --split-by is stored as parts
+		var notFound *DocumentNotFoundError
+		if !errors.As(err, &notFound) {
+			return fmt.Errorf("deletion failed: %w", err)
+		}
+		parts, partsErr := deleteDocumentParts(vdbName, collectionName, docName)
+		if partsErr != nil {
+			return fmt.Errorf("deletion failed: %w", partsErr)
+		}
+		if parts == 0 {
+			return fmt.Errorf("deletion failed: %w", err)
+		}
+		if !silent {
+			fmt.Printf("✅ Document '%s' (%d parts) deleted successfully from collection '%s' in vector database '%s'\n", docName, parts, collectionName, vdbName)
+		}
+		return nil
 	}
 
 	if !silent {
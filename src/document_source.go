@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+)
+
+// stdinFileName is the --file value that reads a document from standard input
+const stdinFileName = "-"
+
+// Limits for documents fetched with --url
+var (
+	urlFetchTimeout       = 30 * time.Second
+	maxURLDocumentBytes   = int64(64 << 20)
+	maxStdinDocumentBytes = int64(64 << 20)
+)
+
+// validateDocumentSource checks that exactly one of a file and a URL was given and that the file exists
+func validateDocumentSource(fileName, rawURL string) error {
+	if fileName != "" && rawURL != "" {
+		return fmt.Errorf("--url cannot be combined with a file; use one source per document")
+	}
+	if rawURL != "" {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid URL '%s' (use an http or https URL)", rawURL)
+		}
+		return nil
+	}
+	if fileName == stdinFileName {
+		return nil
+	}
+	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", fileName)
+	}
+	return nil
+}
+
+// describeDocumentSource names a document source in messages
+func describeDocumentSource(fileName, rawURL string) string {
+	switch {
+	case rawURL != "":
+		return fmt.Sprintf("URL '%s'", rawURL)
+	case fileName == stdinFileName:
+		return "standard input"
+	default:
+		return fmt.Sprintf("file '%s'", fileName)
+	}
+}
+
+// readDocumentSource reads a document from a URL, standard input or a file and extracts its text
+func readDocumentSource(fileName, rawURL string, raw bool) (*ExtractedText, error) {
+	switch {
+	case rawURL != "":
+		return fetchDocumentURL(rawURL, raw)
+	case fileName == stdinFileName:
+		content, err := readLimited(os.Stdin, maxStdinDocumentBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		extracted, err := extractText("", content, raw)
+		if err != nil {
+			return nil, err
+		}
+		extracted.Path = "stdin"
+		return extracted, nil
+	default:
+		return readDocumentFile(fileName, raw)
+	}
+}
+
+// fetchDocumentURL downloads a document over HTTP and extracts its text. The extractor is chosen by
+// the response's Content-Type, falling back to the URL's extension and the content itself.
+func fetchDocumentURL(rawURL string, raw bool) (*ExtractedText, error) {
+	client := &http.Client{Timeout: urlFetchTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch '%s': %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to fetch '%s': %s", rawURL, resp.Status)
+	}
+	content, err := readLimited(resp.Body, maxURLDocumentBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch '%s': %w", rawURL, err)
+	}
+
+	urlPath := path.Base(resp.Request.URL.Path)
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || contentType == "application/octet-stream" {
+		contentType = detectContentType(urlPath, content)
+	}
+
+	extracted, err := extractContent(urlPath, contentType, content, raw)
+	if err != nil {
+		return nil, err
+	}
+	extracted.Path = ""
+	extracted.URL = rawURL
+	return extracted, nil
+}
+
+// readLimited reads all of r, failing when it holds more than limit bytes
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("document is larger than %s", formatBytes(limit))
+	}
+	return content, nil
+}
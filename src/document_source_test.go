@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchDocumentURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><body><h1>Title</h1><p>Body text</p></body></html>"))
+		case "/guide.md":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("# Guide\n\n**bold**"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	extracted, err := fetchDocumentURL(server.URL+"/page", false)
+	if err != nil {
+		t.Fatalf("fetchDocumentURL() error = %v", err)
+	}
+	if extracted.Extractor != "html" || !strings.Contains(extracted.Text, "# Title") || extracted.URL != server.URL+"/page" {
+		t.Errorf("fetchDocumentURL() = %+v, expected HTML text", extracted)
+	}
+
+	extracted, err = fetchDocumentURL(server.URL+"/guide.md", false)
+	if err != nil || extracted.Extractor != "markdown" || extracted.Text != "# Guide\n\nbold" {
+		t.Errorf("fetchDocumentURL() = %+v, %v, expected Markdown text", extracted, err)
+	}
+
+	if _, err := fetchDocumentURL(server.URL+"/missing", false); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("fetchDocumentURL() error = %v, expected a 404 error", err)
+	}
+}
+
+func TestValidateDocumentSource(t *testing.T) {
+	if err := validateDocumentSource("-", ""); err != nil {
+		t.Errorf("validateDocumentSource() for stdin error = %v", err)
+	}
+	if err := validateDocumentSource("", "https://example.com/a.html"); err != nil {
+		t.Errorf("validateDocumentSource() for a URL error = %v", err)
+	}
+	if err := validateDocumentSource("", "ftp://example.com/a"); err == nil {
+		t.Error("validateDocumentSource() expected an error for a non-HTTP URL")
+	}
+	if err := validateDocumentSource("a.txt", "https://example.com"); err == nil {
+		t.Error("validateDocumentSource() expected an error for a file and a URL")
+	}
+	if err := validateDocumentSource("does-not-exist.txt", ""); err == nil {
+		t.Error("validateDocumentSource() expected an error for a missing file")
+	}
+}
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for eval retrieval
+var (
+	evalDataset     string
+	evalK           []int
+	evalConcurrency int
+	evalOutput      string
+	evalBaseline    string
+	evalTolerance   float64
+)
+
+// evalOverfetch is the number of chunks fetched per document rank, so that enough distinct
+// documents remain when several chunks of one document are retrieved
+const evalOverfetch = 4
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Evaluate retrieval quality",
+	Long:  `Measure the quality of retrieval against a dataset of queries with known answers.`,
+	Example: `  maestro eval retrieval --vdb=my-vdb --collection=docs --dataset=golden.jsonl
+  maestro eval retrieval --vdb=my-vdb --collection=docs --dataset=golden.jsonl --baseline=previous.json`,
+}
+
+var evalRetrievalCmd = &cobra.Command{
+	Use:   "retrieval",
+	Short: "Measure recall, precision, MRR and nDCG of search",
+	Long: `Run every query of a golden dataset against a collection and score the retrieved documents.
+
+The dataset is a JSONL file with one query per line and the names of the documents expected to
+answer it:
+
+  {"query": "How do I rotate API keys?", "expected": ["security.md", "api-keys.md"]}
+
+Queries are searched concurrently. Retrieved chunks are collapsed to distinct documents, in rank
+order, and compared with the expected documents by name. For each --k the command reports
+recall@k, precision@k and nDCG@k (binary relevance), plus the mean reciprocal rank (MRR) of the
+first expected document within the largest k, all averaged over the queries.
+
+Save a run with -o json and pass it to --baseline on a later run to flag metrics that dropped
+by more than --tolerance; the command fails when any metric regressed.`,
+	Example: `  maestro eval retrieval --vdb=my-vdb --collection=docs --dataset=golden.jsonl
+  maestro eval retrieval --vdb=my-vdb --collection=docs --dataset=golden.jsonl --k 1,3,10 -o json > baseline.json
+  maestro eval retrieval --vdb=my-vdb --collection=docs-v2 --dataset=golden.jsonl --baseline=baseline.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Interactive selection if missing
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" && !dryRun {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return runRetrievalEval(vdbName, collectionName)
+	},
+}
+
+func init() {
+	evalRetrievalCmd.Flags().String("vdb", "", "Vector database name")
+	evalRetrievalCmd.Flags().String("collection", "", "Collection name to search in")
+	evalRetrievalCmd.Flags().StringVar(&evalDataset, "dataset", "", "JSONL file of queries and their expected document names")
+	evalRetrievalCmd.Flags().IntSliceVar(&evalK, "k", []int{1, 5, 10}, "Cutoffs to compute the metrics at")
+	evalRetrievalCmd.Flags().IntVar(&evalConcurrency, "concurrency", defaultConcurrency, "Number of queries to search in parallel")
+	evalRetrievalCmd.Flags().StringVarP(&evalOutput, "output", "o", "text", "Output format (text, json)")
+	evalRetrievalCmd.Flags().StringVar(&evalBaseline, "baseline", "", "JSON report of a previous run to compare against")
+	evalRetrievalCmd.Flags().Float64Var(&evalTolerance, "tolerance", 0, "Drop in a metric allowed before it counts as a regression")
+}
+
+// evalCase is one query of a golden dataset
+type evalCase struct {
+	Line     int
+	Query    string
+	Expected []string
+}
+
+// EvalQueryResult is the outcome of one query of the dataset
+type EvalQueryResult struct {
+	Line      int                `json:"line"`
+	Query     string             `json:"query"`
+	Expected  []string           `json:"expected"`
+	Retrieved []string           `json:"retrieved"`
+	Metrics   map[string]float64 `json:"metrics,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// EvalRegression is a metric that dropped below its baseline
+type EvalRegression struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+}
+
+// EvalReport is the result of evaluating retrieval against a dataset
+type EvalReport struct {
+	Dataset     string             `json:"dataset"`
+	Database    string             `json:"database"`
+	Collection  string             `json:"collection"`
+	K           []int              `json:"k"`
+	Queries     int                `json:"queries"`
+	Failed      int                `json:"failed"`
+	Duration    float64            `json:"duration_seconds"`
+	Metrics     map[string]float64 `json:"metrics"`
+	Baseline    string             `json:"baseline,omitempty"`
+	Regressions []EvalRegression   `json:"regressions,omitempty"`
+	Results     []EvalQueryResult  `json:"results"`
+}
+
+func runRetrievalEval(vdbName, collectionName string) error {
+	if evalOutput != "text" && evalOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", evalOutput)
+	}
+	if evalDataset == "" {
+		return fmt.Errorf("--dataset is required")
+	}
+	ks, err := normalizeCutoffs(evalK)
+	if err != nil {
+		return err
+	}
+	if evalConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if evalTolerance < 0 {
+		return fmt.Errorf("--tolerance cannot be negative")
+	}
+
+	cases, err := readEvalDatasetFile(evalDataset)
+	if err != nil {
+		return err
+	}
+	var baseline *EvalReport
+	if evalBaseline != "" {
+		if baseline, err = loadEvalReport(evalBaseline); err != nil {
+			return err
+		}
+	}
+
+	if verbose {
+		fmt.Printf("Evaluating %d queries against collection '%s' of vector database '%s'...\n", len(cases), collectionName, vdbName)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would evaluate %d queries from %s against vector database '%s' at k = %s\n", len(cases), evalDataset, vdbName, joinInts(ks))
+			if baseline != nil {
+				fmt.Printf("[DRY RUN] Would compare the results with %s\n", evalBaseline)
+			}
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var progress *ProgressIndicator
+	if ShouldShowProgress() {
+		progress = NewProgressIndicator(fmt.Sprintf("Evaluating %d queries...", len(cases)))
+		progress.Start()
+	}
+	start := time.Now()
+	report := evaluateRetrieval(cases, ks, evalConcurrency, func(query string, limit int) ([]SearchHit, error) {
+		var hits []SearchHit
+		err := safeCall(serverURI, func() error {
+			var searchErr error
+			hits, searchErr = client.SearchHits(vdbName, query, limit, collectionName, nil)
+			return searchErr
+		})
+		return hits, err
+	})
+	report.Dataset = evalDataset
+	report.Database = vdbName
+	report.Collection = collectionName
+	report.Duration = time.Since(start).Seconds()
+	if baseline != nil {
+		report.Baseline = evalBaseline
+		report.Regressions = compareEvalReports(baseline, report, evalTolerance)
+	}
+
+	if progress != nil {
+		if report.Failed == report.Queries {
+			progress.StopWithError("Evaluation failed")
+		} else {
+			progress.Stop("Evaluation completed")
+		}
+	}
+
+	if evalOutput == "json" {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else if !silent {
+		if err := printEvalReport(report, baseline); err != nil {
+			return err
+		}
+	}
+
+	if report.Failed == report.Queries {
+		return fmt.Errorf("search failed for all %d queries", report.Queries)
+	}
+	if report.Failed > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: search failed for %d of %d queries; they count as zero in the metrics\n", report.Failed, report.Queries)
+	}
+	if len(report.Regressions) > 0 {
+		return fmt.Errorf("%d metric(s) regressed against baseline %s", len(report.Regressions), evalBaseline)
+	}
+	return nil
+}
+
+// normalizeCutoffs validates the --k values and returns them sorted without duplicates
+func normalizeCutoffs(values []int) ([]int, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("--k requires at least one cutoff")
+	}
+	seen := make(map[int]bool)
+	var ks []int
+	for _, k := range values {
+		if k < 1 {
+			return nil, fmt.Errorf("--k values must be positive, got %d", k)
+		}
+		if !seen[k] {
+			seen[k] = true
+			ks = append(ks, k)
+		}
+	}
+	sort.Ints(ks)
+	return ks, nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+func readEvalDatasetFile(path string) ([]evalCase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	cases, err := readEvalDataset(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dataset %s: %w", path, err)
+	}
+	return cases, nil
+}
+
+// readEvalDataset parses JSONL lines of {"query": "...", "expected": ["doc.md", ...]}; blank
+// lines are skipped
+func readEvalDataset(r io.Reader) ([]evalCase, error) {
+	reader := bufio.NewReader(r)
+	var cases []evalCase
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			var record struct {
+				Query    string   `json:"query"`
+				Expected []string `json:"expected"`
+			}
+			if decodeErr := json.Unmarshal(trimmed, &record); decodeErr != nil {
+				return nil, fmt.Errorf("line %d: expected a JSON object with a query and a list of expected documents", line)
+			}
+			if strings.TrimSpace(record.Query) == "" {
+				return nil, fmt.Errorf("line %d: missing query", line)
+			}
+			var expected []string
+			seen := make(map[string]bool)
+			for _, name := range record.Expected {
+				if name = strings.TrimSpace(name); name != "" && !seen[name] {
+					seen[name] = true
+					expected = append(expected, name)
+				}
+			}
+			if len(expected) == 0 {
+				return nil, fmt.Errorf("line %d: no expected documents", line)
+			}
+			cases = append(cases, evalCase{Line: line, Query: record.Query, Expected: expected})
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no queries found")
+	}
+	return cases, nil
+}
+
+// evaluateRetrieval searches every case with at most concurrency searches in flight and scores
+// the distinct documents retrieved at each cutoff of ks, which must be sorted. Metrics are
+// averaged over all cases; a failed search scores zero so that failures cannot hide a regression.
+func evaluateRetrieval(cases []evalCase, ks []int, concurrency int, search func(query string, limit int) ([]SearchHit, error)) EvalReport {
+	maxK := ks[len(ks)-1]
+	results := make([]EvalQueryResult, len(cases))
+	var mu sync.Mutex
+
+	indices := make([]int, len(cases))
+	for i := range indices {
+		indices[i] = i
+	}
+	runConcurrently(indices, concurrency, func(i int) {
+		result := EvalQueryResult{Line: cases[i].Line, Query: cases[i].Query, Expected: cases[i].Expected, Retrieved: []string{}}
+		hits, err := search(cases[i].Query, maxK*evalOverfetch)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Retrieved = distinctDocuments(hits, maxK)
+			result.Metrics = scoreRetrieval(result.Retrieved, result.Expected, ks)
+		}
+		mu.Lock()
+		results[i] = result
+		mu.Unlock()
+	})
+
+	report := EvalReport{K: ks, Queries: len(cases), Metrics: make(map[string]float64), Results: results}
+	for _, name := range evalMetricNames(ks) {
+		report.Metrics[name] = 0
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			report.Failed++
+			continue
+		}
+		for name, value := range result.Metrics {
+			report.Metrics[name] += value
+		}
+	}
+	for name := range report.Metrics {
+		report.Metrics[name] /= float64(len(results))
+	}
+	return report
+}
+
+// distinctDocuments returns the names of the first limit documents of hits, in rank order. Hits
+// from the parts of a split document count as that document.
+func distinctDocuments(hits []SearchHit, limit int) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, hit := range hits {
+		if len(names) == limit {
+			break
+		}
+		name := hit.DocName()
+		if parent, _, ok := parseDocumentPartName(name); ok {
+			name = parent
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// scoreRetrieval computes recall@k, precision@k and nDCG@k for each cutoff and the reciprocal
+// rank of the first expected document, with binary relevance
+func scoreRetrieval(retrieved, expected []string, ks []int) map[string]float64 {
+	relevant := make(map[string]bool, len(expected))
+	for _, name := range expected {
+		relevant[name] = true
+	}
+
+	metrics := map[string]float64{"mrr": 0}
+	for i, name := range retrieved {
+		if relevant[name] {
+			metrics["mrr"] = 1 / float64(i+1)
+			break
+		}
+	}
+	for _, k := range ks {
+		hits, dcg, idcg := 0, 0.0, 0.0
+		for i := 0; i < k; i++ {
+			gain := 1 / math.Log2(float64(i+2))
+			if i < len(retrieved) && relevant[retrieved[i]] {
+				hits++
+				dcg += gain
+			}
+			if i < len(expected) {
+				idcg += gain
+			}
+		}
+		metrics[fmt.Sprintf("recall@%d", k)] = float64(hits) / float64(len(expected))
+		metrics[fmt.Sprintf("precision@%d", k)] = float64(hits) / float64(k)
+		metrics[fmt.Sprintf("ndcg@%d", k)] = dcg / idcg
+	}
+	return metrics
+}
+
+// evalMetricNames lists the metrics of a report in display order
+func evalMetricNames(ks []int) []string {
+	var names []string
+	for _, prefix := range []string{"recall", "precision", "ndcg"} {
+		for _, k := range ks {
+			names = append(names, fmt.Sprintf("%s@%d", prefix, k))
+		}
+	}
+	return append(names, "mrr")
+}
+
+func loadEvalReport(path string) (*EvalReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var report EvalReport
+	if err := json.Unmarshal(data, &report); err != nil || report.Metrics == nil {
+		return nil, fmt.Errorf("baseline %s is not a report of 'maestro eval retrieval -o json'", path)
+	}
+	return &report, nil
+}
+
+// compareEvalReports returns the metrics of current that dropped more than tolerance below
+// baseline. Metrics missing from either report, such as a cutoff added since, are skipped.
+func compareEvalReports(baseline *EvalReport, current EvalReport, tolerance float64) []EvalRegression {
+	var regressions []EvalRegression
+	for _, name := range evalMetricNames(current.K) {
+		before, ok := baseline.Metrics[name]
+		after, found := current.Metrics[name]
+		if !ok || !found {
+			continue
+		}
+		// round away floating-point noise so that identical runs never regress
+		if delta := math.Round((after-before)*1e9) / 1e9; delta < -tolerance {
+			regressions = append(regressions, EvalRegression{Metric: name, Baseline: before, Current: after, Delta: delta})
+		}
+	}
+	return regressions
+}
+
+func printEvalReport(report EvalReport, baseline *EvalReport) error {
+	fmt.Printf("Evaluated %d queries against collection '%s' of vector database '%s' in %.1fs\n\n", report.Queries, report.Collection, report.Database, report.Duration)
+
+	regressed := make(map[string]bool)
+	for _, regression := range report.Regressions {
+		regressed[regression.Metric] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if baseline != nil {
+		fmt.Fprintln(w, "METRIC\tVALUE\tBASELINE\tDELTA\t")
+	} else {
+		fmt.Fprintln(w, "METRIC\tVALUE")
+	}
+	for _, name := range evalMetricNames(report.K) {
+		value, ok := report.Metrics[name]
+		if !ok {
+			continue
+		}
+		if baseline == nil {
+			fmt.Fprintf(w, "%s\t%.3f\n", name, value)
+			continue
+		}
+		before, found := baseline.Metrics[name]
+		if !found {
+			fmt.Fprintf(w, "%s\t%.3f\t-\t-\t\n", name, value)
+			continue
+		}
+		flag := ""
+		if regressed[name] {
+			flag = "❌ regression"
+		}
+		fmt.Fprintf(w, "%s\t%.3f\t%.3f\t%+.3f\t%s\n", name, value, before, value-before, flag)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var misses, failures []EvalQueryResult
+	for _, result := range report.Results {
+		switch {
+		case result.Error != "":
+			failures = append(failures, result)
+		case result.Metrics["mrr"] == 0:
+			misses = append(misses, result)
+		}
+	}
+	if len(failures) > 0 {
+		fmt.Printf("\nFailed queries:\n")
+		for _, result := range failures {
+			fmt.Printf("  ❌ line %d (%s): %s\n", result.Line, result.Query, result.Error)
+		}
+	}
+	if len(misses) > 0 {
+		fmt.Printf("\nQueries with no expected document in the top %d:\n", report.K[len(report.K)-1])
+		for _, result := range misses {
+			fmt.Printf("  line %d: %s\n", result.Line, result.Query)
+			if verbose {
+				fmt.Printf("    expected:  %s\n    retrieved: %s\n", strings.Join(result.Expected, ", "), strings.Join(result.Retrieved, ", "))
+			}
+		}
+	}
+	return nil
+}
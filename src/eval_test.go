@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReadEvalDataset(t *testing.T) {
+	input := `{"query": "rotate keys", "expected": ["security.md", "keys.md", "security.md"]}
+
+{"query": "install", "expected": [" install.md "]}
+`
+	cases, err := readEvalDataset(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readEvalDataset() error = %v", err)
+	}
+	want := []evalCase{
+		{Line: 1, Query: "rotate keys", Expected: []string{"security.md", "keys.md"}},
+		{Line: 3, Query: "install", Expected: []string{"install.md"}},
+	}
+	if !reflect.DeepEqual(cases, want) {
+		t.Errorf("cases = %+v, want %+v", cases, want)
+	}
+
+	invalid := map[string]string{
+		`{"query": "a", "expected": ["a.md"]}` + "\nnot json": "line 2: expected a JSON object",
+		`{"expected": ["a.md"]}`:                              "line 1: missing query",
+		`{"query": "a", "expected": []}`:                      "line 1: no expected documents",
+		`{"query": "a", "expected": "a.md"}`:                  "line 1: expected a JSON object",
+		"\n\n":                                                "no queries found",
+	}
+	for input, message := range invalid {
+		if _, err := readEvalDataset(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("readEvalDataset(%q) error = %v, want %q", input, err, message)
+		}
+	}
+}
+
+func TestNormalizeCutoffs(t *testing.T) {
+	ks, err := normalizeCutoffs([]int{10, 1, 5, 1})
+	if err != nil || !reflect.DeepEqual(ks, []int{1, 5, 10}) {
+		t.Errorf("normalizeCutoffs() = %v, %v", ks, err)
+	}
+	for _, values := range [][]int{nil, {5, 0}, {-1}} {
+		if _, err := normalizeCutoffs(values); err == nil {
+			t.Errorf("normalizeCutoffs(%v) should fail", values)
+		}
+	}
+}
+
+func TestDistinctDocuments(t *testing.T) {
+	hits := []SearchHit{
+		{"metadata": map[string]interface{}{"doc_name": "a.md"}},
+		{"metadata": map[string]interface{}{"doc_name": "a.md"}},
+		{"text": "no name"},
+		{"url": "b.md"},
+		{"metadata": map[string]interface{}{"doc_name": "c.md"}},
+		{"metadata": map[string]interface{}{"doc_name": "d.md"}},
+	}
+	if got := distinctDocuments(hits, 3); !reflect.DeepEqual(got, []string{"a.md", "b.md", "c.md"}) {
+		t.Errorf("distinctDocuments() = %v", got)
+	}
+
+	// chunks of the parts of a split document count as the document
+	hits = []SearchHit{
+		{"metadata": map[string]interface{}{"doc_name": "big.md#part-2"}},
+		{"metadata": map[string]interface{}{"doc_name": "a.md"}},
+		{"metadata": map[string]interface{}{"doc_name": "big.md#part-1"}},
+		{"metadata": map[string]interface{}{"doc_name": "c.md"}},
+	}
+	if got := distinctDocuments(hits, 3); !reflect.DeepEqual(got, []string{"big.md", "a.md", "c.md"}) {
+		t.Errorf("distinctDocuments() with parts = %v", got)
+	}
+}
+
+func TestScoreRetrieval(t *testing.T) {
+	metrics := scoreRetrieval([]string{"a", "x", "b", "y"}, []string{"b", "a", "c"}, []int{1, 3})
+	want := map[string]float64{
+		"mrr":         1,
+		"recall@1":    1.0 / 3,
+		"precision@1": 1,
+		"ndcg@1":      1,
+		"recall@3":    2.0 / 3,
+		"precision@3": 2.0 / 3,
+		// DCG 1 + 1/log2(4) over the ideal 1 + 1/log2(3) + 1/log2(4)
+		"ndcg@3": 1.5 / (1.5 + 1/math.Log2(3)),
+	}
+	if len(metrics) != len(want) {
+		t.Fatalf("metrics = %v, want %v", metrics, want)
+	}
+	for name, value := range want {
+		if math.Abs(metrics[name]-value) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, metrics[name], value)
+		}
+	}
+
+	// the first relevant document at rank 3 gives a reciprocal rank of 1/3
+	metrics = scoreRetrieval([]string{"x", "y", "a"}, []string{"a"}, []int{1, 5})
+	if metrics["mrr"] != 1.0/3 || metrics["recall@1"] != 0 || metrics["recall@5"] != 1 || metrics["precision@5"] != 0.2 {
+		t.Errorf("metrics = %v", metrics)
+	}
+	// the ideal ranking of a single expected document only counts its first position
+	if math.Abs(metrics["ndcg@5"]-0.5) > 1e-9 {
+		t.Errorf("ndcg@5 = %v, want 0.5", metrics["ndcg@5"])
+	}
+
+	metrics = scoreRetrieval(nil, []string{"a"}, []int{5})
+	if metrics["mrr"] != 0 || metrics["recall@5"] != 0 || metrics["ndcg@5"] != 0 {
+		t.Errorf("metrics without results = %v", metrics)
+	}
+}
+
+func TestEvaluateRetrieval(t *testing.T) {
+	cases := []evalCase{
+		{Line: 1, Query: "q1", Expected: []string{"doc0.md"}},
+		{Line: 2, Query: "q2", Expected: []string{"doc2.md"}},
+		{Line: 3, Query: "q3", Expected: []string{"doc0.md"}},
+	}
+	var limits int64
+	report := evaluateRetrieval(cases, []int{1, 5}, 2, func(query string, limit int) ([]SearchHit, error) {
+		atomic.StoreInt64(&limits, int64(limit))
+		if query == "q3" {
+			return nil, fmt.Errorf("timeout")
+		}
+		return fakeHits(limit), nil
+	})
+
+	if limits != 5*evalOverfetch {
+		t.Errorf("search limit = %d, want %d", limits, 5*evalOverfetch)
+	}
+	if report.Queries != 3 || report.Failed != 1 {
+		t.Errorf("queries = %d, failed = %d", report.Queries, report.Failed)
+	}
+	if got := report.Results[0].Retrieved; !reflect.DeepEqual(got, []string{"doc0.md", "doc1.md", "doc2.md", "doc3.md", "doc4.md"}) {
+		t.Errorf("retrieved = %v", got)
+	}
+	if report.Results[2].Error != "timeout" || len(report.Results[2].Retrieved) != 0 {
+		t.Errorf("failed result = %+v", report.Results[2])
+	}
+	// averaged over all three queries, the failed one scoring zero: reciprocal ranks 1, 1/3 and 0
+	if math.Abs(report.Metrics["mrr"]-4.0/9) > 1e-9 || math.Abs(report.Metrics["recall@1"]-1.0/3) > 1e-9 || math.Abs(report.Metrics["recall@5"]-2.0/3) > 1e-9 {
+		t.Errorf("metrics = %v", report.Metrics)
+	}
+
+	// a failed query lowers the metrics enough to regress against a run where it succeeded
+	baseline := evaluateRetrieval(cases, []int{1, 5}, 2, func(query string, limit int) ([]SearchHit, error) {
+		return fakeHits(limit), nil
+	})
+	if regressions := compareEvalReports(&baseline, report, 0); len(regressions) == 0 {
+		t.Error("a failed query should regress against the baseline")
+	}
+
+	// every metric is reported even when all queries fail
+	report = evaluateRetrieval(cases[:1], []int{1}, 1, func(query string, limit int) ([]SearchHit, error) {
+		return nil, fmt.Errorf("timeout")
+	})
+	if want := map[string]float64{"recall@1": 0, "precision@1": 0, "ndcg@1": 0, "mrr": 0}; !reflect.DeepEqual(report.Metrics, want) {
+		t.Errorf("metrics of failed queries = %v, want %v", report.Metrics, want)
+	}
+}
+
+func TestEvaluateRetrievalWithClient(t *testing.T) {
+	client, fake := newFakeRetrievalClient(t, func(tool string, input map[string]interface{}) (string, error) {
+		hits := fakeHits(6)
+		// a second chunk of doc0.md must not take a rank of its own
+		hits = append([]SearchHit{hits[0]}, hits...)
+		return encodeHits(t, hits), nil
+	})
+
+	cases := []evalCase{{Line: 1, Query: "q", Expected: []string{"doc1.md", "doc9.md"}}}
+	report := evaluateRetrieval(cases, []int{2}, 1, func(query string, limit int) ([]SearchHit, error) {
+		return client.SearchHits("vdb", query, limit, "docs", nil)
+	})
+	if report.Failed != 0 {
+		t.Fatalf("evaluation failed: %+v", report.Results)
+	}
+	if got := report.Results[0].Retrieved; !reflect.DeepEqual(got, []string{"doc0.md", "doc1.md"}) {
+		t.Errorf("retrieved = %v", got)
+	}
+	if report.Metrics["mrr"] != 0.5 || report.Metrics["recall@2"] != 0.5 || report.Metrics["precision@2"] != 0.5 {
+		t.Errorf("metrics = %v", report.Metrics)
+	}
+	if len(fake.inputs) != 1 || fake.inputs[0]["limit"] != float64(2*evalOverfetch) {
+		t.Errorf("search inputs = %v", fake.inputs)
+	}
+}
+
+func TestCompareEvalReports(t *testing.T) {
+	baseline := &EvalReport{Metrics: map[string]float64{"recall@5": 0.8, "precision@5": 0.4, "ndcg@5": 0.7, "mrr": 0.6, "recall@1": 0.5}}
+	current := EvalReport{K: []int{5, 10}, Metrics: map[string]float64{"recall@5": 0.7, "precision@5": 0.4, "ndcg@5": 0.68, "mrr": 0.65, "recall@10": 0.9}}
+
+	regressions := compareEvalReports(baseline, current, 0)
+	var names []string
+	for _, regression := range regressions {
+		names = append(names, regression.Metric)
+	}
+	if !reflect.DeepEqual(names, []string{"recall@5", "ndcg@5"}) {
+		t.Errorf("regressions = %+v", regressions)
+	}
+	if math.Abs(regressions[0].Delta+0.1) > 1e-9 {
+		t.Errorf("delta = %v, want -0.1", regressions[0].Delta)
+	}
+
+	regressions = compareEvalReports(baseline, current, 0.05)
+	if len(regressions) != 1 || regressions[0].Metric != "recall@5" {
+		t.Errorf("regressions with tolerance = %+v", regressions)
+	}
+
+	if regressions := compareEvalReports(&EvalReport{Metrics: current.Metrics}, current, 0); len(regressions) != 0 {
+		t.Errorf("identical reports should not regress, got %+v", regressions)
+	}
+}
@@ -0,0 +1,731 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// exportFormatVersion is the version of the knowledge-base archive layout
+const exportFormatVersion = 1
+
+// exportManifestName is the archive entry holding the ExportManifest
+const exportManifestName = "manifest.json"
+
+// ExportManifest describes the contents of a knowledge-base archive
+type ExportManifest struct {
+	FormatVersion int                `json:"format_version"`
+	CLIVersion    string             `json:"cli_version"`
+	ExportedAt    time.Time          `json:"exported_at"`
+	Database      ExportDatabase     `json:"database"`
+	Collections   []ExportCollection `json:"collections"`
+}
+
+// ExportDatabase records the vector database an archive was exported from
+type ExportDatabase struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Collection string `json:"collection"`
+}
+
+// ExportCollection records a collection's configuration and the documents it contains
+type ExportCollection struct {
+	Name          string                 `json:"name"`
+	Embedding     string                 `json:"embedding"`
+	Chunking      map[string]interface{} `json:"chunking,omitempty"`
+	DocumentCount int                    `json:"document_count"`
+	Documents     []ExportDocument       `json:"documents"`
+}
+
+// ExportDocument maps a document name to its file inside the archive
+type ExportDocument struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// Flags for export and import
+var (
+	exportOutput         string
+	exportConcurrency    int
+	importName           string
+	importType           string
+	importConcurrency    int
+	importResume         bool
+	importCheckpointPath string
+)
+
+var vdbExportCmd = &cobra.Command{
+	Use:   "export VDB_NAME",
+	Short: "Export a vector database to an archive",
+	Long: `Export every collection and document of a vector database to a portable archive.
+
+The archive is a gzipped tar file containing a manifest.json that records the
+embedding and chunking configuration of each collection, and one JSON file per document.`,
+	Example: `  maestro vectordb export my-vdb -o kb.tar.gz
+  maestro vectordb export my-vdb -o kb.tar.gz --concurrency=8`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName := args[0]
+		output := exportOutput
+		if output == "" {
+			output = vdbName + ".tar.gz"
+		}
+		return exportVectorDatabase(vdbName, output)
+	},
+}
+
+var vdbImportCmd = &cobra.Command{
+	Use:   "import ARCHIVE",
+	Short: "Import a vector database from an archive",
+	Long: `Recreate a vector database, its collections and documents from an archive created by 'vectordb export'.
+
+Imported documents are recorded in a checkpoint file next to the archive. If the import
+fails part way, rerun it with --resume to continue where it stopped.`,
+	Example: `  maestro vectordb import kb.tar.gz
+  maestro vectordb import kb.tar.gz --name=my-vdb-copy --concurrency=8
+  maestro vectordb import kb.tar.gz --resume`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return importVectorDatabase(args[0])
+	},
+}
+
+func init() {
+	vdbExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Archive file to write (default VDB_NAME.tar.gz)")
+	vdbExportCmd.Flags().IntVar(&exportConcurrency, "concurrency", defaultConcurrency, "Number of documents to fetch in parallel")
+
+	vdbImportCmd.Flags().StringVar(&importName, "name", "", "Name of the vector database to create (default: name recorded in the archive)")
+	vdbImportCmd.Flags().StringVar(&importType, "type", "", "Override the database type (milvus, weaviate)")
+	vdbImportCmd.Flags().IntVar(&importConcurrency, "concurrency", defaultConcurrency, "Number of documents to write in parallel")
+	vdbImportCmd.Flags().BoolVar(&importResume, "resume", false, "Resume a previous import using its checkpoint")
+	vdbImportCmd.Flags().StringVar(&importCheckpointPath, "checkpoint", "", "Checkpoint file (default ARCHIVE.checkpoint)")
+}
+
+// documentArchivePath returns the archive entry for a document
+func documentArchivePath(collectionName, docName string) string {
+	return path.Join("collections", url.PathEscape(collectionName), url.PathEscape(docName)+".json")
+}
+
+func exportVectorDatabase(vdbName, output string) error {
+	if verbose {
+		fmt.Printf("Exporting vector database '%s' to '%s'...\n", vdbName, output)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would export vector database '%s' to '%s'\n", vdbName, output)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	database, err := fetchDatabase(client, serverURI, vdbName)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := buildExportManifest(client, serverURI, *database)
+	if err != nil {
+		return err
+	}
+
+	if err := writeExportArchive(client, serverURI, manifest, output); err != nil {
+		return err
+	}
+
+	if !silent {
+		total := 0
+		for _, collection := range manifest.Collections {
+			total += len(collection.Documents)
+		}
+		fmt.Printf("✅ Exported %d collection(s) and %d document(s) from vector database '%s' to '%s'\n", len(manifest.Collections), total, vdbName, output)
+	}
+
+	return nil
+}
+
+// buildExportManifest walks the collections of a database and records their configuration and documents
+func buildExportManifest(client *MCPClient, serverURI string, database DatabaseInfo) (*ExportManifest, error) {
+	collectionNames, err := fetchCollectionNames(client, serverURI, database.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &ExportManifest{
+		FormatVersion: exportFormatVersion,
+		CLIVersion:    version,
+		ExportedAt:    time.Now().UTC(),
+		Database: ExportDatabase{
+			Name:       database.Name,
+			Type:       database.Type,
+			Collection: database.Collection,
+		},
+	}
+
+	for _, collectionName := range collectionNames {
+		if verbose {
+			fmt.Printf("Reading collection '%s'...\n", collectionName)
+		}
+
+		info, err := fetchCollectionInfo(client, serverURI, database.Name, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		documents, err := fetchDocumentNames(client, serverURI, database.Name, collectionName)
+		if err != nil {
+			return nil, err
+		}
+
+		collection := ExportCollection{
+			Name:      collectionName,
+			Embedding: info.Embedding,
+			Chunking:  info.ChunkingConfig(),
+		}
+		for _, docName := range documents {
+			collection.Documents = append(collection.Documents, ExportDocument{
+				Name: docName,
+				File: documentArchivePath(collectionName, docName),
+			})
+		}
+		collection.DocumentCount = len(collection.Documents)
+		manifest.Collections = append(manifest.Collections, collection)
+	}
+
+	return manifest, nil
+}
+
+// writeExportArchive writes the manifest followed by every document, fetching documents concurrently
+func writeExportArchive(client *MCPClient, serverURI string, manifest *ExportManifest, output string) error {
+	archive, err := createExportArchive(output)
+	if err != nil {
+		return err
+	}
+
+	type exportJob struct {
+		collection string
+		document   ExportDocument
+	}
+	var jobs []exportJob
+	for _, collection := range manifest.Collections {
+		for _, document := range collection.Documents {
+			jobs = append(jobs, exportJob{collection: collection.Name, document: document})
+		}
+	}
+
+	if err := archive.writeJSON(exportManifestName, manifest); err != nil {
+		archive.discard()
+		return err
+	}
+
+	progress := newBulkProgress(fmt.Sprintf("Exporting %d document(s)...", len(jobs)), len(jobs))
+
+	var mu sync.Mutex
+	var exportErr error
+	runConcurrently(jobs, exportConcurrency, func(job exportJob) {
+		mu.Lock()
+		failed := exportErr != nil
+		mu.Unlock()
+		if failed {
+			return
+		}
+
+		doc, err := fetchDocument(client, serverURI, manifest.Database.Name, job.collection, job.document.Name)
+		if err == nil {
+			err = archive.writeJSON(job.document.File, doc)
+		}
+
+		if err != nil {
+			mu.Lock()
+			if exportErr == nil {
+				exportErr = fmt.Errorf("failed to export document '%s' from collection '%s': %w", job.document.Name, job.collection, err)
+			}
+			mu.Unlock()
+			return
+		}
+		progress.Increment()
+	})
+
+	if exportErr != nil {
+		progress.Stop("Export failed", 1)
+		archive.discard()
+		return exportErr
+	}
+	progress.Stop("Export completed", 0)
+
+	return archive.Close()
+}
+
+// exportArchive writes JSON entries to a gzipped tar file. It is safe for concurrent use.
+type exportArchive struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+}
+
+func createExportArchive(archivePath string) (*exportArchive, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive '%s': %w", archivePath, err)
+	}
+	gz := gzip.NewWriter(file)
+	return &exportArchive{path: archivePath, file: file, gz: gz, tw: tar.NewWriter(gz)}, nil
+}
+
+// writeJSON adds an indented JSON entry to the archive
+func (a *exportArchive) writeJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode '%s': %w", name, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write '%s' to archive: %w", name, err)
+	}
+	if _, err := a.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write '%s' to archive: %w", name, err)
+	}
+	return nil
+}
+
+// Close flushes and closes the archive
+func (a *exportArchive) Close() error {
+	if err := a.tw.Close(); err != nil {
+		a.file.Close()
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if err := a.gz.Close(); err != nil {
+		a.file.Close()
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return a.file.Close()
+}
+
+// discard closes and removes a partially written archive
+func (a *exportArchive) discard() {
+	a.file.Close()
+	os.Remove(a.path)
+}
+
+// walkExportArchive calls fn for every entry of an archive until fn returns errStopWalk or an error
+func walkExportArchive(archivePath string, fn func(header *tar.Header, r io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read archive '%s': %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive '%s': %w", archivePath, err)
+		}
+		if err := fn(header, tr); err != nil {
+			if errors.Is(err, errStopWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+var errStopWalk = errors.New("stop walking archive")
+
+// readExportManifest reads the manifest of a knowledge-base archive
+func readExportManifest(archivePath string) (*ExportManifest, error) {
+	var manifest *ExportManifest
+	err := walkExportArchive(archivePath, func(header *tar.Header, r io.Reader) error {
+		if header.Name != exportManifestName {
+			return nil
+		}
+		manifest = &ExportManifest{}
+		if err := json.NewDecoder(r).Decode(manifest); err != nil {
+			return fmt.Errorf("failed to parse archive manifest: %w", err)
+		}
+		return errStopWalk
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("archive '%s' has no %s; was it created by 'maestro vectordb export'?", archivePath, exportManifestName)
+	}
+	if manifest.FormatVersion > exportFormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported version %d; please upgrade maestro", manifest.FormatVersion, exportFormatVersion)
+	}
+	return manifest, nil
+}
+
+// importCheckpoint records imported documents so an interrupted import can be resumed.
+// Each line of the checkpoint file is a JSON object naming one imported document.
+type importCheckpoint struct {
+	mu   sync.Mutex
+	path string
+	done map[string]bool
+	file *os.File
+}
+
+type checkpointEntry struct {
+	Collection string `json:"collection"`
+	Document   string `json:"document"`
+}
+
+// openImportCheckpoint loads an existing checkpoint file (if any) and opens it for appending
+func openImportCheckpoint(checkpointPath string) (*importCheckpoint, error) {
+	checkpoint := &importCheckpoint{path: checkpointPath, done: make(map[string]bool)}
+
+	if data, err := os.ReadFile(checkpointPath); err == nil {
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			var entry checkpointEntry
+			// A torn last line from an interrupted write is simply redone
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+				checkpoint.done[checkpointKey(entry.Collection, entry.Document)] = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read checkpoint '%s': %w", checkpointPath, err)
+	}
+
+	file, err := os.OpenFile(checkpointPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint '%s': %w", checkpointPath, err)
+	}
+	checkpoint.file = file
+	return checkpoint, nil
+}
+
+func checkpointKey(collectionName, docName string) string {
+	return collectionName + "\x00" + docName
+}
+
+// isDone reports whether a document was imported by a previous run
+func (c *importCheckpoint) isDone(collectionName, docName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[checkpointKey(collectionName, docName)]
+}
+
+// markDone records a successfully imported document
+func (c *importCheckpoint) markDone(collectionName, docName string) error {
+	data, err := json.Marshal(checkpointEntry{Collection: collectionName, Document: docName})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[checkpointKey(collectionName, docName)] = true
+	_, err = c.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the checkpoint file, keeping it on disk
+func (c *importCheckpoint) Close() error {
+	return c.file.Close()
+}
+
+// Remove closes and deletes the checkpoint file after a successful import
+func (c *importCheckpoint) Remove() error {
+	c.file.Close()
+	return os.Remove(c.path)
+}
+
+func importVectorDatabase(archivePath string) error {
+	if verbose {
+		fmt.Printf("Importing vector database from '%s'...\n", archivePath)
+	}
+
+	manifest, err := readExportManifest(archivePath)
+	if err != nil {
+		return err
+	}
+
+	dbName := manifest.Database.Name
+	if importName != "" {
+		dbName = importName
+	}
+	dbType := manifest.Database.Type
+	if importType != "" {
+		dbType = importType
+	}
+
+	checkpointPath := importCheckpointPath
+	if checkpointPath == "" {
+		checkpointPath = archivePath + ".checkpoint"
+	}
+	if _, err := os.Stat(checkpointPath); err == nil && !importResume {
+		return fmt.Errorf("a checkpoint from a previous import exists at '%s'; rerun with --resume to continue or delete it to start over", checkpointPath)
+	}
+
+	total := 0
+	for _, collection := range manifest.Collections {
+		total += len(collection.Documents)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would import vector database '%s' (%s) from '%s'\n", dbName, dbType, archivePath)
+			for _, collection := range manifest.Collections {
+				strategy := "None"
+				if collection.Chunking != nil {
+					strategy, _ = collection.Chunking["strategy"].(string)
+				}
+				fmt.Printf("  • %s: %d document(s), embedding '%s', chunking '%s'\n", collection.Name, len(collection.Documents), collection.Embedding, strategy)
+			}
+		}
+		return nil
+	}
+
+	checkpoint, err := openImportCheckpoint(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		checkpoint.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := prepareImportTarget(client, serverURI, manifest, dbName, dbType); err != nil {
+		checkpoint.Close()
+		return err
+	}
+
+	// Map archive entries to their collection and skip documents imported by a previous run
+	entries := make(map[string]string)
+	remaining := 0
+	for _, collection := range manifest.Collections {
+		for _, document := range collection.Documents {
+			if checkpoint.isDone(collection.Name, document.Name) {
+				continue
+			}
+			entries[document.File] = collection.Name
+			remaining++
+		}
+	}
+	if skipped := total - remaining; skipped > 0 && !silent {
+		fmt.Printf("Resuming import: %d of %d document(s) already imported\n", skipped, total)
+	}
+
+	type importJob struct {
+		collection string
+		doc        DocumentRecord
+	}
+	jobs := make(chan importJob)
+	progress := newBulkProgress(fmt.Sprintf("Importing %d document(s) into '%s'...", remaining, dbName), remaining)
+
+	// failures lists every problem for the report; failedDocs counts only manifest documents
+	var mu sync.Mutex
+	var failures []string
+	failedDocs := 0
+	recordFailure := func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, message)
+		failedDocs++
+	}
+
+	var readErr error
+	found := 0
+	go func() {
+		defer close(jobs)
+		readErr = walkExportArchive(archivePath, func(header *tar.Header, r io.Reader) error {
+			collectionName, ok := entries[header.Name]
+			if !ok {
+				return nil
+			}
+			// Count each manifest document once, even if the archive repeats its entry
+			delete(entries, header.Name)
+			found++
+			var doc DocumentRecord
+			if err := json.NewDecoder(r).Decode(&doc); err != nil {
+				recordFailure(fmt.Sprintf("%s: invalid archive entry: %v", header.Name, err))
+				return nil
+			}
+			jobs <- importJob{collection: collectionName, doc: doc}
+			return nil
+		})
+	}()
+
+	runWorkers(jobs, importConcurrency, func(job importJob) {
+		err := safeCall(serverURI, func() error {
+			return client.WriteDocumentText(dbName, job.collection, job.doc.Name, job.doc.Text, job.doc.URL, job.doc.Metadata)
+		})
+		if err == nil {
+			err = checkpoint.markDone(job.collection, job.doc.Name)
+		}
+		if err != nil {
+			recordFailure(fmt.Sprintf("%s/%s: %v", job.collection, job.doc.Name, err))
+		}
+		progress.Increment()
+	})
+
+	// Documents listed in the manifest but missing from the archive are failures too
+	missing := remaining - found
+	if readErr != nil {
+		failures = append(failures, readErr.Error())
+	}
+	if missing > 0 {
+		failures = append(failures, fmt.Sprintf("%d document(s) listed in the manifest were not found in the archive", missing))
+	}
+
+	if len(failures) > 0 {
+		progress.Stop("Import incomplete", failedDocs+missing)
+		checkpoint.Close()
+		sort.Strings(failures)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+		}
+		return importFailureError(dbName, failedDocs+missing, readErr)
+	}
+	progress.Stop("Import completed", 0)
+
+	if err := checkpoint.Remove(); err != nil && verbose {
+		fmt.Printf("Warning: failed to remove checkpoint '%s': %v\n", checkpointPath, err)
+	}
+
+	if !silent {
+		fmt.Printf("✅ Imported %d collection(s) and %d document(s) into vector database '%s'\n", len(manifest.Collections), total, dbName)
+	}
+	return nil
+}
+
+// importFailureError summarizes an incomplete import, reporting an archive read error
+// separately from the number of manifest documents that were not imported
+func importFailureError(dbName string, failedDocs int, readErr error) error {
+	if readErr != nil && failedDocs == 0 {
+		return fmt.Errorf("failed to read the archive while importing into vector database '%s': %w", dbName, readErr)
+	}
+	if readErr != nil {
+		return fmt.Errorf("%d document(s) failed to import into vector database '%s' after an archive read error; rerun with --resume to retry them", failedDocs, dbName)
+	}
+	return fmt.Errorf("%d document(s) failed to import into vector database '%s'; rerun with --resume to retry them", failedDocs, dbName)
+}
+
+// prepareImportTarget creates the database and any collections that do not exist yet
+func prepareImportTarget(client *MCPClient, serverURI string, manifest *ExportManifest, dbName, dbType string) error {
+	var exists bool
+	if err := safeCall(serverURI, func() error {
+		var existsErr error
+		exists, existsErr = client.DatabaseExists(dbName)
+		return existsErr
+	}); err != nil {
+		return fmt.Errorf("failed to check if database exists: %w", err)
+	}
+	if exists && !importResume {
+		return fmt.Errorf("vector database '%s' already exists; use --name to import under a different name", dbName)
+	}
+
+	collectionsByName := make(map[string]ExportCollection)
+	for _, collection := range manifest.Collections {
+		collectionsByName[collection.Name] = collection
+	}
+
+	if !exists {
+		if verbose {
+			fmt.Printf("Creating vector database '%s' of type '%s'\n", dbName, dbType)
+		}
+
+		defaultCollection := manifest.Database.Collection
+		embedding := "default"
+		if collection, ok := collectionsByName[defaultCollection]; ok && collection.Embedding != "" {
+			embedding = collection.Embedding
+		}
+
+		if err := safeCall(serverURI, func() error {
+			return client.CreateVectorDatabase(dbName, dbType, defaultCollection)
+		}); err != nil {
+			return fmt.Errorf("failed to create vector database: %w", err)
+		}
+		if err := safeCall(serverURI, func() error {
+			return client.SetupDatabase(dbName, embedding)
+		}); err != nil {
+			return fmt.Errorf("failed to setup vector database: %w", err)
+		}
+
+		// Setup creates the default collection without chunking; recreate it if the archive has chunking for it
+		if collection, ok := collectionsByName[defaultCollection]; ok && collection.Chunking != nil {
+			if err := safeCall(serverURI, func() error {
+				if err := client.DeleteCollection(dbName, defaultCollection); err != nil {
+					return err
+				}
+				return client.CreateCollectionWithChunking(dbName, defaultCollection, embedding, collection.Chunking)
+			}); err != nil {
+				return fmt.Errorf("failed to configure chunking for collection '%s': %w", defaultCollection, err)
+			}
+		}
+	}
+
+	collectionNames, err := fetchCollectionNames(client, serverURI, dbName)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for _, name := range collectionNames {
+		existing[name] = true
+	}
+
+	for _, collection := range manifest.Collections {
+		if existing[collection.Name] {
+			continue
+		}
+		if verbose {
+			fmt.Printf("Creating collection '%s' with embedding '%s'\n", collection.Name, collection.Embedding)
+		}
+		embedding := collection.Embedding
+		if embedding == "" {
+			embedding = "default"
+		}
+		if err := safeCall(serverURI, func() error {
+			return client.CreateCollectionWithChunking(dbName, collection.Name, embedding, collection.Chunking)
+		}); err != nil {
+			return fmt.Errorf("failed to create collection '%s': %w", collection.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestDocumentArchivePath(t *testing.T) {
+	result := documentArchivePath("my docs", "guides/intro.md")
+	expected := "collections/my%20docs/guides%2Fintro.md.json"
+	if result != expected {
+		t.Errorf("documentArchivePath() = %s, expected %s", result, expected)
+	}
+}
+
+func TestExportArchiveRoundTrip(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "kb.tar.gz")
+
+	manifest := &ExportManifest{
+		FormatVersion: exportFormatVersion,
+		Database:      ExportDatabase{Name: "my-vdb", Type: "milvus", Collection: "docs"},
+		Collections: []ExportCollection{{
+			Name:          "docs",
+			Embedding:     "default",
+			Chunking:      map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 256.0}},
+			DocumentCount: 1,
+			Documents:     []ExportDocument{{Name: "intro", File: documentArchivePath("docs", "intro")}},
+		}},
+	}
+
+	archive, err := createExportArchive(archivePath)
+	if err != nil {
+		t.Fatalf("createExportArchive() returned error: %v", err)
+	}
+	if err := archive.writeJSON(exportManifestName, manifest); err != nil {
+		t.Fatalf("writeJSON(manifest) returned error: %v", err)
+	}
+	doc := DocumentRecord{Name: "intro", Text: "hello world", Metadata: map[string]interface{}{"owner": "docs-team"}}
+	if err := archive.writeJSON(manifest.Collections[0].Documents[0].File, doc); err != nil {
+		t.Fatalf("writeJSON(document) returned error: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	read, err := readExportManifest(archivePath)
+	if err != nil {
+		t.Fatalf("readExportManifest() returned error: %v", err)
+	}
+	if read.Database.Name != "my-vdb" || len(read.Collections) != 1 || read.Collections[0].Chunking["strategy"] != "Fixed" {
+		t.Errorf("readExportManifest() = %+v", read)
+	}
+
+	var docs []DocumentRecord
+	err = walkExportArchive(archivePath, func(header *tar.Header, r io.Reader) error {
+		if header.Name == exportManifestName {
+			return nil
+		}
+		var d DocumentRecord
+		if err := json.NewDecoder(r).Decode(&d); err != nil {
+			return err
+		}
+		docs = append(docs, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkExportArchive() returned error: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Text != "hello world" || docs[0].Metadata["owner"] != "docs-team" {
+		t.Errorf("Unexpected documents in archive: %+v", docs)
+	}
+}
+
+func TestReadExportManifestMissing(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "empty.tar.gz")
+	archive, err := createExportArchive(archivePath)
+	if err != nil {
+		t.Fatalf("createExportArchive() returned error: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if _, err := readExportManifest(archivePath); err == nil {
+		t.Error("readExportManifest() should fail for an archive without a manifest")
+	}
+}
+
+func TestImportCheckpoint(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "kb.tar.gz.checkpoint")
+
+	checkpoint, err := openImportCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatalf("openImportCheckpoint() returned error: %v", err)
+	}
+	if err := checkpoint.markDone("docs", "intro"); err != nil {
+		t.Fatalf("markDone() returned error: %v", err)
+	}
+	checkpoint.Close()
+
+	resumed, err := openImportCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatalf("openImportCheckpoint() returned error on resume: %v", err)
+	}
+	defer resumed.Remove()
+
+	if !resumed.isDone("docs", "intro") {
+		t.Error("Document recorded in the checkpoint should be done after resume")
+	}
+	if resumed.isDone("docs", "other") {
+		t.Error("Document not recorded in the checkpoint should not be done")
+	}
+}
+
+func TestImportFailureError(t *testing.T) {
+	readErr := errors.New("unexpected EOF")
+	tests := []struct {
+		name       string
+		failedDocs int
+		readErr    error
+		expected   string
+	}{
+		{"documents only", 3, nil, "3 document(s) failed to import into vector database 'vdb'; rerun with --resume to retry them"},
+		{"read error only", 0, readErr, "failed to read the archive while importing into vector database 'vdb': unexpected EOF"},
+		{"read error and documents", 2, readErr, "2 document(s) failed to import into vector database 'vdb' after an archive read error; rerun with --resume to retry them"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := importFailureError("vdb", tt.failedDocs, tt.readErr)
+			if err.Error() != tt.expected {
+				t.Errorf("importFailureError() = %q, expected %q", err.Error(), tt.expected)
+			}
+		})
+	}
+}
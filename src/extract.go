@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/html"
+)
+
+// textExtractor converts the content of one kind of file into plain text
+type textExtractor struct {
+	Name       string
+	Extensions []string
+	MIMETypes  []string
+	Extract    func(content []byte) (string, error)
+	// Metadata optionally reads metadata embedded in the content, such as front matter
+	Metadata func(content []byte) map[string]interface{}
+}
+
+// textExtractors is the extractor registry, consulted in order
+var textExtractors []*textExtractor
+
+// registerExtractor adds an extractor to the registry
+func registerExtractor(extractor *textExtractor) {
+	textExtractors = append(textExtractors, extractor)
+}
+
+func init() {
+	registerExtractor(&textExtractor{
+		Name:       "markdown",
+		Extensions: []string{".md", ".markdown", ".mdown", ".mkd"},
+		MIMETypes:  []string{"text/markdown", "text/x-markdown"},
+		Extract:    func(content []byte) (string, error) { return extractMarkdown(string(content)), nil },
+		Metadata:   frontMatterMetadata,
+	})
+	registerExtractor(&textExtractor{
+		Name:       "html",
+		Extensions: []string{".html", ".htm", ".xhtml"},
+		MIMETypes:  []string{"text/html", "application/xhtml+xml"},
+		Extract:    extractHTML,
+	})
+	registerExtractor(&textExtractor{
+		Name:       "pdf",
+		Extensions: []string{".pdf"},
+		MIMETypes:  []string{"application/pdf"},
+		Extract:    extractPDF,
+	})
+}
+
+// ExtractedText is the result of extracting a file
+type ExtractedText struct {
+	Path        string                 `json:"path"`
+	URL         string                 `json:"url,omitempty"`
+	ContentType string                 `json:"content_type"`
+	Extractor   string                 `json:"extractor"`
+	Text        string                 `json:"text"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// extractText converts file content to the text sent to the server. An extractor is chosen by file
+// extension, then by detected MIME type; other content that is valid UTF-8 without NUL bytes is
+// passed through unchanged and binary files are rejected. With raw, the content is sent as-is if it is valid UTF-8.
+func extractText(filePath string, content []byte, raw bool) (*ExtractedText, error) {
+	return extractContent(filePath, detectContentType(filePath, content), content, raw)
+}
+
+// extractContent is extractText for content whose MIME type is already known, e.g. from an HTTP response
+func extractContent(filePath, contentType string, content []byte, raw bool) (*ExtractedText, error) {
+	result := &ExtractedText{Path: filePath, ContentType: contentType, Extractor: "plain"}
+
+	if raw {
+		if !utf8.Valid(content) {
+			return nil, fmt.Errorf("%s is not UTF-8 text and cannot be sent with --raw", contentType)
+		}
+		result.Extractor = "raw"
+		result.Text = string(content)
+		return result, nil
+	}
+
+	if extractor := findExtractor(filePath, contentType); extractor != nil {
+		text, err := extractor.Extract(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s text: %w", extractor.Name, err)
+		}
+		result.Extractor = extractor.Name
+		result.Text = text
+		if extractor.Metadata != nil {
+			result.Metadata = extractor.Metadata(content)
+		}
+		return result, nil
+	}
+
+	// Whatever its MIME type, e.g. application/json or application/x-sh, UTF-8 content without NUL
+	// bytes is text
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return nil, fmt.Errorf("binary file (%s) has no text extractor", contentType)
+	}
+	result.Text = string(content)
+	return result, nil
+}
+
+// detectContentType returns the MIME type of a file from its extension or, failing that, its content
+func detectContentType(filePath string, content []byte) string {
+	if byExtension := mime.TypeByExtension(strings.ToLower(filepath.Ext(filePath))); byExtension != "" {
+		mediaType, _, err := mime.ParseMediaType(byExtension)
+		if err == nil {
+			return mediaType
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	return mediaType
+}
+
+// findExtractor returns the registered extractor for a file, or nil
+func findExtractor(filePath, contentType string) *textExtractor {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, extractor := range textExtractors {
+		for _, candidate := range extractor.Extensions {
+			if ext == candidate {
+				return extractor
+			}
+		}
+	}
+	for _, extractor := range textExtractors {
+		for _, candidate := range extractor.MIMETypes {
+			if contentType == candidate {
+				return extractor
+			}
+		}
+	}
+	return nil
+}
+
+// Markdown patterns removed or rewritten by extractMarkdown
+var (
+	mdFence        = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeading      = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdSetext       = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+	mdRule         = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_]))+\s*$`)
+	mdBlockquote   = regexp.MustCompile(`^\s{0,3}(>\s?)+`)
+	mdListItem     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(\[[ xX]\]\s+)?`)
+	mdReference    = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S+`)
+	mdTableDivider = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdImage        = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink         = regexp.MustCompile(`\[([^\]]+)\](\([^)]*\)|\[[^\]]*\])`)
+	mdAutolink     = regexp.MustCompile(`<((https?|mailto):[^>]+)>`)
+	mdHTMLTag      = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdCode         = regexp.MustCompile("`+([^`]+)`+")
+	mdStrong       = regexp.MustCompile(`(\*\*|__)(\S(.*?\S)?)(\*\*|__)`)
+	mdEmphasis     = regexp.MustCompile(`(^|[^\w*])[*_](\S(.*?\S)?)[*_]([^\w*]|$)`)
+	mdStrike       = regexp.MustCompile(`~~(.+?)~~`)
+)
+
+// extractMarkdown strips Markdown formatting and YAML front matter, keeping headings as '#'
+// lines and the text of links, images, emphasis, lists, tables and code blocks
+func extractMarkdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	_, lines = parseFrontMatter(lines)
+	var out []string
+	inFence := false
+	for i, line := range lines {
+		if mdFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		// A setext underline turns the previous line into a heading
+		if mdSetext.MatchString(line) && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			level := "#"
+			if strings.Contains(line, "-") {
+				level = "##"
+			}
+			out[len(out)-1] = level + " " + out[len(out)-1]
+			continue
+		}
+		if mdRule.MatchString(line) || mdReference.MatchString(line) || mdTableDivider.MatchString(line) && strings.Contains(line, "-") && strings.Contains(line, "|") {
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			out = append(out, m[1]+" "+stripInlineMarkdown(m[2]))
+			continue
+		}
+
+		line = mdBlockquote.ReplaceAllString(line, "")
+		line = mdListItem.ReplaceAllString(line, "$1- ")
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") {
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			for j := range cells {
+				cells[j] = strings.TrimSpace(cells[j])
+			}
+			line = strings.Join(cells, "\t")
+		}
+		out = append(out, stripInlineMarkdown(line))
+	}
+	return collapseBlankLines(strings.Join(out, "\n"))
+}
+
+// stripInlineMarkdown removes inline Markdown markup from one line
+func stripInlineMarkdown(line string) string {
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllString(line, "$1")
+	line = mdAutolink.ReplaceAllString(line, "$1")
+	line = mdHTMLTag.ReplaceAllString(line, "")
+	line = mdCode.ReplaceAllString(line, "$1")
+	line = mdStrong.ReplaceAllString(line, "$2")
+	line = mdEmphasis.ReplaceAllString(line, "$1$2$4")
+	line = mdStrike.ReplaceAllString(line, "$1")
+	return strings.TrimRight(line, " \t")
+}
+
+// collapseBlankLines trims the text and reduces runs of blank lines to one
+func collapseBlankLines(text string) string {
+	var out []string
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// HTML elements whose content is not readable text
+var htmlSkipped = map[string]bool{"script": true, "style": true, "noscript": true, "template": true, "head": true, "svg": true, "iframe": true}
+
+// HTML elements that start a new paragraph
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true, "main": true, "nav": true,
+	"aside": true, "blockquote": true, "ul": true, "ol": true, "li": true, "table": true, "tr": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "dl": true, "dt": true, "dd": true,
+	"figure": true, "figcaption": true, "form": true, "hr": true, "address": true, "details": true, "summary": true,
+}
+
+// extractHTML converts HTML to readable text: headings become '#' lines, list items '- ' lines,
+// table cells are separated by tabs and scripts, styles and the head are dropped
+func extractHTML(content []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	title := ""
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(htmlSpace.ReplaceAllString(n.Data, " "))
+			return
+		case html.ElementNode:
+			if n.Data == "title" && n.FirstChild != nil {
+				title = strings.TrimSpace(n.FirstChild.Data)
+			}
+			if htmlSkipped[n.Data] {
+				return
+			}
+			switch {
+			case n.Data == "br":
+				b.WriteString("\n")
+				return
+			case (n.Data == "td" || n.Data == "th") && n.PrevSibling != nil:
+				b.WriteString("\t")
+			case htmlBlocks[n.Data]:
+				b.WriteString("\n\n")
+			}
+			if len(n.Data) == 2 && n.Data[0] == 'h' && n.Data[1] >= '1' && n.Data[1] <= '6' {
+				b.WriteString(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+			}
+			if n.Data == "li" {
+				b.WriteString("- ")
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type == html.ElementNode && htmlBlocks[n.Data] {
+			b.WriteString("\n\n")
+		}
+	}
+	walk(doc)
+
+	var lines []string
+	bullet := false
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.TrimSpace(htmlSpaces.ReplaceAllString(line, " "))
+		// A list item whose content is a block leaves its marker on a line of its own
+		if line == "-" {
+			bullet = true
+			continue
+		}
+		if bullet && line != "" {
+			line, bullet = "- "+line, false
+		}
+		lines = append(lines, line)
+	}
+	text := collapseBlankLines(strings.Join(lines, "\n"))
+	// Without a heading in the body, the title is the best heading available
+	if title != "" && !strings.HasPrefix(text, "#") {
+		text = strings.TrimSpace("# " + title + "\n\n" + text)
+	}
+	return text, nil
+}
+
+// Whitespace handling of extractHTML
+var (
+	htmlSpace  = regexp.MustCompile(`[ \t\n\r\f]+`)
+	htmlSpaces = regexp.MustCompile(` {2,}`)
+)
+
+// extractPDF extracts the text of every page of a PDF, separating pages with blank lines
+func extractPDF(content []byte) (text string, err error) {
+	// The PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", err
+	}
+
+	var pages []string
+	fonts := make(map[string]*pdf.Font)
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+		pageText, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", fmt.Errorf("page %d: %w", i, err)
+		}
+		if pageText = strings.TrimSpace(pageText); pageText != "" {
+			pages = append(pages, pageText)
+		}
+	}
+	if len(pages) == 0 {
+		return "", fmt.Errorf("no extractable text (the PDF may be scanned images)")
+	}
+	return strings.Join(pages, "\n\n"), nil
+}
+
+// readDocumentFile reads a file and extracts the text to store for it
+func readDocumentFile(filePath string, raw bool) (*ExtractedText, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+	return extractText(filePath, content, raw)
+}
+
+// Flags for text extraction
+var (
+	extractRaw    bool
+	extractOutput string
+)
+
+var documentExtractCmd = &cobra.Command{
+	Use:   "extract FILE",
+	Short: "Preview the text extracted from a file",
+	Long: `Show the text that document create, ingest, sync and watch would store for a file.
+
+Markdown is stripped of formatting with headings kept as '#' lines, HTML is converted to readable
+text and PDFs are extracted page by page. Other UTF-8 text, such as JSON, YAML or shell scripts, is
+stored unchanged; binary files without an extractor are rejected. --raw bypasses extraction.`,
+	Example: `  maestro document extract README.md
+  maestro document extract manual.pdf -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if extractOutput != "text" && extractOutput != "json" {
+			return fmt.Errorf("unsupported output format '%s' (use text or json)", extractOutput)
+		}
+
+		extracted, err := readDocumentFile(args[0], extractRaw)
+		if err != nil {
+			return err
+		}
+
+		if extractOutput == "json" {
+			return printJSON(extracted)
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "%s (%s) extracted with %s: %d characters\n", extracted.Path, extracted.ContentType, extracted.Extractor, utf8.RuneCountInString(extracted.Text))
+		}
+		fmt.Println(extracted.Text)
+		return nil
+	},
+}
+
+func init() {
+	documentExtractCmd.Flags().BoolVar(&extractRaw, "raw", false, "Show the file content without extraction")
+	documentExtractCmd.Flags().StringVarP(&extractOutput, "output", "o", "text", "Output format (text, json)")
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExtractMarkdown(t *testing.T) {
+	source := "---\ntitle: Guide\n---\n# Guide\n\nSome **bold** and _italic_ text with a [link](https://example.com) and `code`.\n\n\n\n- item one\n> quoted\n\n```go\nfmt.Println(\"hi\")\n```\n![logo](logo.png)\n"
+
+	got := extractMarkdown(source)
+	for _, expected := range []string{"# Guide", "Some bold and italic text with a link and code.", "item one", "quoted", "fmt.Println(\"hi\")"} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("extractMarkdown() = %q, expected it to contain %q", got, expected)
+		}
+	}
+	for _, unexpected := range []string{"**", "](", "```", "\n\n\n", "title: Guide"} {
+		if strings.Contains(got, unexpected) {
+			t.Errorf("extractMarkdown() = %q, expected no %q", got, unexpected)
+		}
+	}
+}
+
+func TestExtractHTML(t *testing.T) {
+	source := `<html><head><title>Page</title><style>p { color: red }</style></head>
+<body><h1>Welcome</h1><p>Hello <b>world</b>.</p><script>alert(1)</script><ul><li>First</li><li>Second</li></ul></body></html>`
+
+	got, err := extractHTML([]byte(source))
+	if err != nil {
+		t.Fatalf("extractHTML() error = %v", err)
+	}
+	for _, expected := range []string{"# Welcome", "Hello world.", "First", "Second"} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("extractHTML() = %q, expected it to contain %q", got, expected)
+		}
+	}
+	for _, unexpected := range []string{"alert", "color", "<"} {
+		if strings.Contains(got, unexpected) {
+			t.Errorf("extractHTML() = %q, expected no %q", got, unexpected)
+		}
+	}
+}
+
+func TestExtractPDF(t *testing.T) {
+	got, err := extractPDF(buildTestPDF("Hello PDF"))
+	if err != nil {
+		t.Fatalf("extractPDF() error = %v", err)
+	}
+	if !strings.Contains(got, "Hello PDF") {
+		t.Errorf("extractPDF() = %q, expected it to contain %q", got, "Hello PDF")
+	}
+
+	if _, err := extractPDF([]byte("%PDF-1.4\nnot really a pdf")); err == nil {
+		t.Error("extractPDF() expected an error for a malformed PDF")
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	extracted, err := extractText("notes.md", []byte("# Notes\n\n**done**"), false)
+	if err != nil {
+		t.Fatalf("extractText() error = %v", err)
+	}
+	if extracted.Extractor != "markdown" || extracted.Text != "# Notes\n\ndone" {
+		t.Errorf("extractText() = %+v, expected markdown text", extracted)
+	}
+
+	extracted, err = extractText("notes.md", []byte("# Notes\n\n**done**"), true)
+	if err != nil || extracted.Extractor != "raw" || extracted.Text != "# Notes\n\n**done**" {
+		t.Errorf("extractText() with raw = %+v, %v, expected the content unchanged", extracted, err)
+	}
+
+	extracted, err = extractText("notes.txt", []byte("plain text"), false)
+	if err != nil || extracted.Extractor != "plain" || extracted.Text != "plain text" {
+		t.Errorf("extractText() for text = %+v, %v, expected plain text", extracted, err)
+	}
+
+	// structured text and scripts have non-text MIME types but no extractor of their own
+	for name, content := range map[string]string{
+		"config.json": `{"name": "maestro"}`,
+		"config.yaml": "name: maestro\n",
+		"config.toml": "name = \"maestro\"\n",
+		"install.sh":  "#!/bin/sh\necho ok\n",
+	} {
+		extracted, err := extractText(name, []byte(content), false)
+		if err != nil || extracted.Extractor != "plain" || extracted.Text != content {
+			t.Errorf("extractText(%s) = %+v, %v, expected the content unchanged", name, extracted, err)
+		}
+	}
+	if _, err := extractText("data.json", []byte("{\"a\": \"\x00\"}"), false); err == nil {
+		t.Error("extractText() expected an error for content with NUL bytes")
+	}
+
+	binary := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d}
+	if _, err := extractText("image.png", binary, false); err == nil || !strings.Contains(err.Error(), "has no text extractor") {
+		t.Errorf("extractText() for binary = %v, expected a missing extractor error", err)
+	}
+	if _, err := extractText("image.png", binary, true); err == nil {
+		t.Error("extractText() with raw expected an error for binary content")
+	}
+}
+
+// buildTestPDF returns a single-page PDF showing text in Helvetica
+func buildTestPDF(text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Flags for federated search
+var (
+	searchTargets     []string
+	searchConcurrency int
+)
+
+// rrfK is the rank constant of reciprocal rank fusion; larger values flatten the advantage of
+// the first ranks
+const rrfK = 60
+
+// searchTarget is one collection searched by a federated search
+type searchTarget struct {
+	VDB        string
+	Collection string
+}
+
+func (t searchTarget) String() string {
+	return t.VDB + "/" + t.Collection
+}
+
+// TargetSummary reports how the search of one target went
+type TargetSummary struct {
+	Target     string `json:"target"`
+	Hits       int    `json:"hits"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// FederatedSearchResult is the output of a federated search
+type FederatedSearchResult struct {
+	Results []SearchHit     `json:"results"`
+	Targets []TargetSummary `json:"targets"`
+}
+
+// parseTargetPattern splits a --target value of the form VDB/COLLECTION, where either part may
+// be a wildcard pattern such as 'vdbC/*'
+func parseTargetPattern(target string) (string, string, error) {
+	vdbPattern, collectionPattern, ok := strings.Cut(target, "/")
+	if !ok || vdbPattern == "" || collectionPattern == "" || strings.Contains(collectionPattern, "/") {
+		return "", "", fmt.Errorf("invalid target '%s' (use VDB/COLLECTION, e.g. my-vdb/docs or 'my-vdb/*')", target)
+	}
+	for _, pattern := range []string{vdbPattern, collectionPattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return "", "", fmt.Errorf("invalid target '%s': %w", target, err)
+		}
+	}
+	return vdbPattern, collectionPattern, nil
+}
+
+func isWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// resolveTargets expands the --target patterns into collections, in the order given and without
+// duplicates. Wildcards are matched against the databases and collections the server lists;
+// targets without wildcards are used as given.
+func resolveTargets(patterns []string, listDatabases func() ([]string, error), listCollections func(vdb string) ([]string, error)) ([]searchTarget, error) {
+	var databases []string
+	collections := make(map[string][]string)
+	seen := make(map[searchTarget]bool)
+	var targets []searchTarget
+
+	for _, pattern := range patterns {
+		vdbPattern, collectionPattern, err := parseTargetPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		vdbs := []string{vdbPattern}
+		if isWildcard(vdbPattern) {
+			if databases == nil {
+				if databases, err = listDatabases(); err != nil {
+					return nil, err
+				}
+			}
+			vdbs = nil
+			for _, name := range databases {
+				if ok, _ := path.Match(vdbPattern, name); ok {
+					vdbs = append(vdbs, name)
+				}
+			}
+		}
+
+		matched := 0
+		for _, vdb := range vdbs {
+			names := []string{collectionPattern}
+			// collections are listed for wildcards, and to skip databases without the collection
+			if isWildcard(collectionPattern) || isWildcard(vdbPattern) {
+				if _, ok := collections[vdb]; !ok {
+					if collections[vdb], err = listCollections(vdb); err != nil {
+						return nil, err
+					}
+				}
+				names = nil
+				for _, name := range collections[vdb] {
+					if ok, _ := path.Match(collectionPattern, name); ok {
+						names = append(names, name)
+					}
+				}
+			}
+			for _, name := range names {
+				matched++
+				target := searchTarget{VDB: vdb, Collection: name}
+				if !seen[target] {
+					seen[target] = true
+					targets = append(targets, target)
+				}
+			}
+		}
+		if matched == 0 {
+			return nil, fmt.Errorf("target '%s' matches no collections", pattern)
+		}
+	}
+	return targets, nil
+}
+
+// runFederatedSearch searches every target concurrently and prints the fused results with a
+// summary per target
+func runFederatedSearch(query string, opts retrievalOptions) error {
+	for _, pattern := range searchTargets {
+		if _, _, err := parseTargetPattern(pattern); err != nil {
+			return err
+		}
+	}
+	if searchConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	if dryRun {
+		fmt.Printf("[DRY RUN] Would search %d target(s): %s\n", len(searchTargets), strings.Join(searchTargets, ", "))
+		if !opts.IsZero() {
+			fmt.Printf("[DRY RUN] Would narrow results with %s\n", opts)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	targets, err := resolveTargets(searchTargets,
+		func() ([]string, error) {
+			var databases []DatabaseInfo
+			if err := safeCall(serverURI, func() error {
+				var listErr error
+				databases, listErr = client.ListDatabases()
+				return listErr
+			}); err != nil {
+				return nil, fmt.Errorf("failed to list vector databases: %w", err)
+			}
+			names := make([]string, len(databases))
+			for i, db := range databases {
+				names[i] = db.Name
+			}
+			return names, nil
+		},
+		func(vdb string) ([]string, error) {
+			return fetchCollectionNames(client, serverURI, vdb)
+		})
+	if err != nil {
+		return err
+	}
+
+	var progress *ProgressIndicator
+	if ShouldShowProgress() {
+		progress = NewProgressIndicator(fmt.Sprintf("Searching %d targets...", len(targets)))
+		progress.Start()
+	}
+	result := federatedSearch(targets, docLimit, searchConcurrency, func(target searchTarget) ([]SearchHit, error) {
+		var hits []SearchHit
+		err := safeCall(serverURI, func() error {
+			var searchErr error
+			hits, searchErr = searchWithOptions(client, target.VDB, query, docLimit, target.Collection, opts)
+			return searchErr
+		})
+		return hits, err
+	})
+
+	failed := 0
+	for _, summary := range result.Targets {
+		if summary.Error != "" {
+			failed++
+		}
+	}
+	if progress != nil {
+		if failed == len(targets) {
+			progress.StopWithError("Search failed")
+		} else {
+			progress.Stop("Search completed successfully")
+		}
+	}
+
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	if failed == len(targets) {
+		return fmt.Errorf("search failed for all %d targets", len(targets))
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: search failed for %d of %d targets\n", failed, len(targets))
+	}
+	return nil
+}
+
+// federatedSearch runs search on every target with at most concurrency searches in flight and
+// fuses the results, keeping the best limit hits
+func federatedSearch(targets []searchTarget, limit, concurrency int, search func(searchTarget) ([]SearchHit, error)) FederatedSearchResult {
+	lists := make([][]SearchHit, len(targets))
+	summaries := make([]TargetSummary, len(targets))
+	var mu sync.Mutex
+
+	indices := make([]int, len(targets))
+	for i := range indices {
+		indices[i] = i
+	}
+	runConcurrently(indices, concurrency, func(i int) {
+		start := time.Now()
+		hits, err := search(targets[i])
+		summary := TargetSummary{Target: targets[i].String(), Hits: len(hits), DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		mu.Lock()
+		lists[i], summaries[i] = hits, summary
+		mu.Unlock()
+	})
+
+	return FederatedSearchResult{Results: fuseResults(targets, lists, limit), Targets: summaries}
+}
+
+// fusedHit is a hit being merged across the result lists of several targets
+type fusedHit struct {
+	hit   SearchHit
+	score float64
+	norm  float64
+	order int
+}
+
+// fuseResults merges per-target result lists with reciprocal rank fusion: a hit scores the sum
+// of 1/(rrfK+rank) over the lists it appears in. Identical passages found in several targets are
+// merged. Similarities are min-max normalized per target, since backends score differently, and
+// break ties between hits of equal rank. Each hit is tagged with its source and source_rank,
+// normalized_score and rrf_score, and renumbered by rank.
+func fuseResults(targets []searchTarget, lists [][]SearchHit, limit int) []SearchHit {
+	for i, hits := range lists {
+		normalizeScores(hits)
+		for rank, hit := range hits {
+			hit["source"] = targets[i].String()
+			hit["source_rank"] = rank + 1
+		}
+	}
+
+	byKey := make(map[string]*fusedHit)
+	var fused []*fusedHit
+	// visit rank by rank so that first-seen order interleaves the targets
+	for rank := 0; ; rank++ {
+		visited := false
+		for i, hits := range lists {
+			if rank >= len(hits) {
+				continue
+			}
+			visited = true
+			hit := hits[rank]
+			key := hit.Text()
+			if key == "" {
+				key = fmt.Sprintf("%s#%v#%d", targets[i], hit["id"], rank)
+			}
+			entry, ok := byKey[key]
+			if !ok {
+				entry = &fusedHit{hit: hit, order: len(fused)}
+				byKey[key] = entry
+				fused = append(fused, entry)
+			} else {
+				also, _ := entry.hit["also_in"].([]string)
+				entry.hit["also_in"] = append(also, targets[i].String())
+			}
+			entry.score += 1 / float64(rrfK+rank+1)
+			if norm := hit["normalized_score"].(float64); norm > entry.norm {
+				entry.norm = norm
+			}
+		}
+		if !visited {
+			break
+		}
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		if fused[i].score != fused[j].score {
+			return fused[i].score > fused[j].score
+		}
+		if fused[i].norm != fused[j].norm {
+			return fused[i].norm > fused[j].norm
+		}
+		return fused[i].order < fused[j].order
+	})
+
+	results := []SearchHit{}
+	for i, entry := range fused {
+		if i == limit {
+			break
+		}
+		entry.hit["rrf_score"] = entry.score
+		entry.hit["rank"] = i + 1
+		results = append(results, entry.hit)
+	}
+	return results
+}
+
+// normalizeScores sets normalized_score on each hit by min-max scaling the similarities of one
+// result list to [0, 1]. Hits without a similarity are scored by their rank instead.
+func normalizeScores(hits []SearchHit) {
+	low, high := 0.0, 0.0
+	scored := 0
+	for _, hit := range hits {
+		if score, ok := hit.Similarity(); ok {
+			if scored == 0 || score < low {
+				low = score
+			}
+			if scored == 0 || score > high {
+				high = score
+			}
+			scored++
+		}
+	}
+	for rank, hit := range hits {
+		score, ok := hit.Similarity()
+		switch {
+		case !ok:
+			hit["normalized_score"] = 1 - float64(rank)/float64(len(hits))
+		case high == low:
+			hit["normalized_score"] = 1.0
+		default:
+			hit["normalized_score"] = (score - low) / (high - low)
+		}
+	}
+}
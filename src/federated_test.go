@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseTargetPattern(t *testing.T) {
+	valid := map[string][2]string{
+		"vdbA/docs":   {"vdbA", "docs"},
+		"vdbC/*":      {"vdbC", "*"},
+		"*/wiki-[ab]": {"*", "wiki-[ab]"},
+	}
+	for target, want := range valid {
+		vdb, collection, err := parseTargetPattern(target)
+		if err != nil || vdb != want[0] || collection != want[1] {
+			t.Errorf("parseTargetPattern(%q) = %q, %q, %v", target, vdb, collection, err)
+		}
+	}
+	for _, target := range []string{"vdbA", "/docs", "vdbA/", "vdbA/docs/extra", "vdbA/[docs"} {
+		if _, _, err := parseTargetPattern(target); err == nil {
+			t.Errorf("parseTargetPattern(%q) should fail", target)
+		}
+	}
+}
+
+func TestResolveTargets(t *testing.T) {
+	var listed []string
+	listDatabases := func() ([]string, error) {
+		listed = append(listed, "databases")
+		return []string{"vdbA", "vdbB", "vdbC"}, nil
+	}
+	listCollections := func(vdb string) ([]string, error) {
+		listed = append(listed, vdb)
+		switch vdb {
+		case "vdbA":
+			return []string{"docs", "wiki"}, nil
+		case "vdbC":
+			return []string{"notes", "wiki", "archive"}, nil
+		}
+		return nil, nil
+	}
+
+	targets, err := resolveTargets([]string{"vdbA/docs", "vdbB/wiki", "vdbC/*", "*/wiki", "vdbA/docs"}, listDatabases, listCollections)
+	if err != nil {
+		t.Fatalf("resolveTargets() error = %v", err)
+	}
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.String())
+	}
+	want := []string{"vdbA/docs", "vdbB/wiki", "vdbC/notes", "vdbC/wiki", "vdbC/archive", "vdbA/wiki"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("targets = %v, want %v", got, want)
+	}
+	// each listing happens once; literal targets are not looked up
+	if wantListed := []string{"vdbC", "databases", "vdbA", "vdbB"}; !reflect.DeepEqual(listed, wantListed) {
+		t.Errorf("listings = %v, want %v", listed, wantListed)
+	}
+
+	if _, err := resolveTargets([]string{"vdbA/zz*"}, listDatabases, listCollections); err == nil || !strings.Contains(err.Error(), "target 'vdbA/zz*' matches no collections") {
+		t.Errorf("resolveTargets() of unmatched pattern error = %v", err)
+	}
+	failing := func(vdb string) ([]string, error) { return nil, fmt.Errorf("failed to list collections: boom") }
+	if _, err := resolveTargets([]string{"vdbA/*"}, listDatabases, failing); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("resolveTargets() listing error = %v", err)
+	}
+}
+
+func scoredHits(prefix string, scores ...float64) []SearchHit {
+	hits := make([]SearchHit, len(scores))
+	for i, score := range scores {
+		hits[i] = SearchHit{
+			"id":         fmt.Sprintf("%s%d", prefix, i),
+			"text":       fmt.Sprintf("%s passage %d", prefix, i),
+			"metadata":   map[string]interface{}{"doc_name": fmt.Sprintf("%s%d.md", prefix, i)},
+			"similarity": score,
+			"rank":       i + 1,
+		}
+	}
+	return hits
+}
+
+func TestFuseResults(t *testing.T) {
+	targets := []searchTarget{{"vdbA", "docs"}, {"vdbB", "wiki"}}
+	a := scoredHits("a", 0.9, 0.8, 0.7)
+	b := scoredHits("b", 0.5, 0.45, 0.2)
+	// the same passage ranked third by A and first by B
+	b[0]["text"] = a[2].Text()
+
+	results := fuseResults(targets, [][]SearchHit{a, b}, 4)
+	var names []string
+	for _, hit := range results {
+		names = append(names, hit.DocName())
+	}
+	// b0 (also a2) appears in both lists and wins; a1 and b1 tie on rank and b1 has the higher
+	// normalized score
+	if want := []string{"b0.md", "a0.md", "b1.md", "a1.md"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("fused order = %v, want %v", names, want)
+	}
+
+	top := results[0]
+	if top["source"] != "vdbB/wiki" || top["source_rank"] != 1 || !reflect.DeepEqual(top["also_in"], []string{"vdbA/docs"}) {
+		t.Errorf("merged hit tags = source %v, source_rank %v, also_in %v", top["source"], top["source_rank"], top["also_in"])
+	}
+	if want := 1.0/63 + 1.0/61; math.Abs(top["rrf_score"].(float64)-want) > 1e-12 {
+		t.Errorf("rrf_score = %v, want %v", top["rrf_score"], want)
+	}
+	for i, hit := range results {
+		if hit["rank"] != i+1 {
+			t.Errorf("hit %d rank = %v", i, hit["rank"])
+		}
+	}
+	if b1 := results[2]; b1["source"] != "vdbB/wiki" || b1["source_rank"] != 2 || math.Abs(b1["normalized_score"].(float64)-0.25/0.3) > 1e-9 {
+		t.Errorf("b1 tags = %v, %v, normalized %v", b1["source"], b1["source_rank"], b1["normalized_score"])
+	}
+}
+
+func TestFuseResultsTieBreak(t *testing.T) {
+	// at equal ranks the hit with the higher normalized score goes first, whatever the raw score
+	targets := []searchTarget{{"vdbA", "docs"}, {"vdbB", "wiki"}}
+	a := scoredHits("a", 0.9, 0.1)
+	b := scoredHits("b", 0.3, 0.25, 0.1)
+	results := fuseResults(targets, [][]SearchHit{a, b}, 10)
+	var names []string
+	for _, hit := range results {
+		names = append(names, hit.DocName())
+	}
+	if want := []string{"a0.md", "b0.md", "b1.md", "a1.md", "b2.md"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("fused order = %v, want %v", names, want)
+	}
+}
+
+func TestNormalizeScores(t *testing.T) {
+	hits := scoredHits("a", 0.8, 0.6, 0.4)
+	normalizeScores(hits)
+	for i, want := range []float64{1, 0.5, 0} {
+		if got := hits[i]["normalized_score"].(float64); math.Abs(got-want) > 1e-9 {
+			t.Errorf("hit %d normalized = %v, want %v", i, got, want)
+		}
+	}
+
+	same := scoredHits("s", 0.7)
+	normalizeScores(same)
+	if same[0]["normalized_score"] != 1.0 {
+		t.Errorf("single hit normalized = %v, want 1", same[0]["normalized_score"])
+	}
+
+	unscored := []SearchHit{{"text": "x"}, {"text": "y"}}
+	normalizeScores(unscored)
+	if unscored[0]["normalized_score"] != 1.0 || unscored[1]["normalized_score"] != 0.5 {
+		t.Errorf("unscored normalized = %v, %v", unscored[0]["normalized_score"], unscored[1]["normalized_score"])
+	}
+}
+
+func TestFederatedSearch(t *testing.T) {
+	targets := []searchTarget{{"vdbA", "docs"}, {"vdbB", "wiki"}, {"vdbC", "notes"}}
+	var inFlight, maxInFlight int32
+	result := federatedSearch(targets, 2, 2, func(target searchTarget) ([]SearchHit, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		if target.VDB == "vdbB" {
+			return nil, fmt.Errorf("MCP server error: collection 'wiki' not found")
+		}
+		return scoredHits(target.VDB, 0.9, 0.5), nil
+	})
+
+	if maxInFlight > 2 {
+		t.Errorf("%d searches ran at once, want at most 2", maxInFlight)
+	}
+	if len(result.Results) != 2 || result.Results[0].DocName() != "vdbA0.md" || result.Results[1].DocName() != "vdbC0.md" {
+		t.Errorf("results = %v", result.Results)
+	}
+	want := []TargetSummary{
+		{Target: "vdbA/docs", Hits: 2},
+		{Target: "vdbB/wiki", Error: "MCP server error: collection 'wiki' not found"},
+		{Target: "vdbC/notes", Hits: 2},
+	}
+	for i := range want {
+		got := result.Targets[i]
+		if got.DurationMs < 10 {
+			t.Errorf("%s duration = %dms, want at least 10ms", got.Target, got.DurationMs)
+		}
+		got.DurationMs = 0
+		if got != want[i] {
+			t.Errorf("summary %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
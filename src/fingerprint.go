@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Document metadata keys holding the near-duplicate fingerprints of ingested documents
+const (
+	simhashMetadataKey = "simhash"
+	minhashMetadataKey = "minhash"
+)
+
+// Fingerprint parameters. Texts are compared as sets of word shingles; the MinHash signature is
+// split into minhashBands bands of minhashRows values for locality-sensitive lookup.
+const (
+	shingleSize  = 3
+	minhashSize  = 64
+	minhashBands = 16
+	minhashRows  = minhashSize / minhashBands
+)
+
+// minhashSeeds are the seeds of the MinHash functions, fixed so that signatures stored in a
+// collection stay comparable across runs
+var minhashSeeds = func() [minhashSize]uint64 {
+	var seeds [minhashSize]uint64
+	state := uint64(0x6d61657374726f)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+	return seeds
+}()
+
+// Fingerprint is the SimHash and MinHash signature of a text
+type Fingerprint struct {
+	SimHash uint64
+	MinHash [minhashSize]uint32
+}
+
+// computeFingerprint fingerprints the word shingles of text, ignoring case and punctuation.
+// It returns nil for a text without words.
+func computeFingerprint(text string) *Fingerprint {
+	hashes := shingleHashes(text)
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	fp := &Fingerprint{}
+	for i := range fp.MinHash {
+		fp.MinHash[i] = ^uint32(0)
+	}
+	var votes [64]int
+	for _, h := range hashes {
+		for bit := 0; bit < 64; bit++ {
+			if h&(1<<bit) != 0 {
+				votes[bit]++
+			} else {
+				votes[bit]--
+			}
+		}
+		for i, seed := range minhashSeeds {
+			if v := uint32(mix64(h ^ seed)); v < fp.MinHash[i] {
+				fp.MinHash[i] = v
+			}
+		}
+	}
+	for bit, vote := range votes {
+		if vote > 0 {
+			fp.SimHash |= 1 << bit
+		}
+	}
+	return fp
+}
+
+// shingleHashes returns the distinct hashes of the overlapping word shingles of text.
+// Texts shorter than a shingle are hashed as a single shingle.
+func shingleHashes(text string) []uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	seen := make(map[uint64]bool)
+	var hashes []uint64
+	for i := 0; i == 0 || i+shingleSize <= len(words); i++ {
+		end := i + shingleSize
+		if end > len(words) {
+			end = len(words)
+		}
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		if sum := h.Sum64(); !seen[sum] {
+			seen[sum] = true
+			hashes = append(hashes, sum)
+		}
+	}
+	return hashes
+}
+
+// mix64 is the splitmix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets of two texts
+func (fp *Fingerprint) Similarity(other *Fingerprint) float64 {
+	equal := 0
+	for i := range fp.MinHash {
+		if fp.MinHash[i] == other.MinHash[i] {
+			equal++
+		}
+	}
+	return float64(equal) / minhashSize
+}
+
+// SimHashDistance returns the number of differing SimHash bits of two fingerprints
+func (fp *Fingerprint) SimHashDistance(other *Fingerprint) int {
+	return bits.OnesCount64(fp.SimHash ^ other.SimHash)
+}
+
+// Metadata returns the fingerprint as document metadata values
+func (fp *Fingerprint) Metadata() map[string]interface{} {
+	signature := make([]byte, 4*minhashSize)
+	for i, v := range fp.MinHash {
+		binary.BigEndian.PutUint32(signature[4*i:], v)
+	}
+	return map[string]interface{}{
+		simhashMetadataKey: fmt.Sprintf("%016x", fp.SimHash),
+		minhashMetadataKey: base64.RawStdEncoding.EncodeToString(signature),
+	}
+}
+
+// fingerprintFromMetadata reads a fingerprint stored by Metadata, returning nil when the metadata
+// has none or it is malformed
+func fingerprintFromMetadata(metadata map[string]interface{}) *Fingerprint {
+	simhash, ok := metadata[simhashMetadataKey].(string)
+	if !ok {
+		return nil
+	}
+	minhash, ok := metadata[minhashMetadataKey].(string)
+	if !ok {
+		return nil
+	}
+	sim, err := strconv.ParseUint(simhash, 16, 64)
+	if err != nil {
+		return nil
+	}
+	signature, err := base64.RawStdEncoding.DecodeString(minhash)
+	if err != nil || len(signature) != 4*minhashSize {
+		return nil
+	}
+
+	fp := &Fingerprint{SimHash: sim}
+	for i := range fp.MinHash {
+		fp.MinHash[i] = binary.BigEndian.Uint32(signature[4*i:])
+	}
+	return fp
+}
+
+// fingerprintIndex finds near-duplicate fingerprints by banding their MinHash signatures: two
+// fingerprints become candidates when all values of any band are equal
+type fingerprintIndex struct {
+	names        []string
+	fingerprints []*Fingerprint
+	buckets      [minhashBands]map[string][]int
+}
+
+func newFingerprintIndex() *fingerprintIndex {
+	idx := &fingerprintIndex{}
+	for i := range idx.buckets {
+		idx.buckets[i] = make(map[string][]int)
+	}
+	return idx
+}
+
+// Add indexes a named fingerprint
+func (idx *fingerprintIndex) Add(name string, fp *Fingerprint) {
+	id := len(idx.names)
+	idx.names = append(idx.names, name)
+	idx.fingerprints = append(idx.fingerprints, fp)
+	for band := range idx.buckets {
+		key := bandKey(fp, band)
+		idx.buckets[band][key] = append(idx.buckets[band][key], id)
+	}
+}
+
+// candidates returns the ids of indexed fingerprints sharing a band with fp, in insertion order
+func (idx *fingerprintIndex) candidates(fp *Fingerprint) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for band := range idx.buckets {
+		for _, id := range idx.buckets[band][bandKey(fp, band)] {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Nearest returns the indexed fingerprint most similar to fp with a similarity of at least
+// threshold; ties go to the one indexed first
+func (idx *fingerprintIndex) Nearest(fp *Fingerprint, threshold float64) (string, *Fingerprint, float64, bool) {
+	best := -1
+	bestSimilarity := 0.0
+	for _, id := range idx.candidates(fp) {
+		if similarity := fp.Similarity(idx.fingerprints[id]); similarity >= threshold && similarity > bestSimilarity {
+			best, bestSimilarity = id, similarity
+		}
+	}
+	if best < 0 {
+		return "", nil, 0, false
+	}
+	return idx.names[best], idx.fingerprints[best], bestSimilarity, true
+}
+
+// bandKey renders the values of one band of a signature as a map key
+func bandKey(fp *Fingerprint, band int) string {
+	key := make([]byte, 4*minhashRows)
+	for i := 0; i < minhashRows; i++ {
+		binary.BigEndian.PutUint32(key[4*i:], fp.MinHash[band*minhashRows+i])
+	}
+	return string(key)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// sampleText returns n pseudo-random words determined by seed, so that texts with different seeds
+// share almost no shingles
+func sampleText(seed string, n int) string {
+	words := []string{"river", "stone", "cloud", "amber", "signal", "harbor", "lantern", "meadow", "copper", "orbit", "thistle", "engine"}
+	state := uint64(len(seed))
+	for _, r := range seed {
+		state = state*31 + uint64(r)
+	}
+	text := make([]string, n)
+	for i := range text {
+		state = mix64(state + uint64(i))
+		text[i] = fmt.Sprintf("%s%d", words[state%uint64(len(words))], state%5)
+	}
+	return strings.Join(text, " ")
+}
+
+func TestComputeFingerprintSimilarity(t *testing.T) {
+	base := sampleText("base", 300)
+	words := strings.Fields(base)
+	words[150] = "changed"
+	edited := strings.Join(words, " ")
+
+	tests := []struct {
+		name     string
+		other    string
+		min, max float64
+	}{
+		{"identical", base, 1, 1},
+		{"case and punctuation", strings.ToUpper(strings.ReplaceAll(base, " ", ", ")), 1, 1},
+		{"small edit", edited, 0.85, 1},
+		{"unrelated", sampleText("another", 300), 0, 0.2},
+	}
+	fp := computeFingerprint(base)
+	for _, test := range tests {
+		similarity := fp.Similarity(computeFingerprint(test.other))
+		if similarity < test.min || similarity > test.max {
+			t.Errorf("%s: similarity = %.2f, expected between %.2f and %.2f", test.name, similarity, test.min, test.max)
+		}
+	}
+
+	if fp.SimHashDistance(computeFingerprint(base)) != 0 {
+		t.Error("identical texts should have the same SimHash")
+	}
+	if computeFingerprint(" \n-- ") != nil {
+		t.Error("a text without words should have no fingerprint")
+	}
+	if short := computeFingerprint("hello"); short == nil || short.Similarity(computeFingerprint("Hello!")) != 1 {
+		t.Error("texts shorter than a shingle should be fingerprinted")
+	}
+}
+
+func TestFingerprintMetadataRoundTrip(t *testing.T) {
+	fp := computeFingerprint(sampleText("round trip", 20))
+	metadata := fp.Metadata()
+	if len(metadata[simhashMetadataKey].(string)) != 16 {
+		t.Errorf("simhash = %v, expected 16 hex digits", metadata[simhashMetadataKey])
+	}
+
+	decoded := fingerprintFromMetadata(metadata)
+	if decoded == nil || *decoded != *fp {
+		t.Fatalf("fingerprintFromMetadata() = %+v, expected %+v", decoded, fp)
+	}
+
+	for _, metadata := range []map[string]interface{}{
+		nil,
+		{simhashMetadataKey: "zz", minhashMetadataKey: metadata[minhashMetadataKey]},
+		{simhashMetadataKey: metadata[simhashMetadataKey], minhashMetadataKey: "AAAA"},
+		{simhashMetadataKey: metadata[simhashMetadataKey]},
+	} {
+		if fingerprintFromMetadata(metadata) != nil {
+			t.Errorf("fingerprintFromMetadata(%v) should be nil", metadata)
+		}
+	}
+}
+
+func TestFingerprintIndexNearest(t *testing.T) {
+	base := sampleText("indexed", 300)
+	index := newFingerprintIndex()
+	index.Add("base.md", computeFingerprint(base))
+	index.Add("other.md", computeFingerprint(sampleText("other", 300)))
+
+	name, _, similarity, found := index.Nearest(computeFingerprint(base+" one more closing remark"), 0.9)
+	if !found || name != "base.md" || similarity < 0.9 {
+		t.Errorf("Nearest() = %q, %.2f, %v; expected base.md", name, similarity, found)
+	}
+	if name, _, _, found := index.Nearest(computeFingerprint(sampleText("unrelated", 300)), 0.9); found {
+		t.Errorf("Nearest() found %q for an unrelated text", name)
+	}
+}
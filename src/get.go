@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for document retrieval
+var (
+	getOutput      string
+	getOut         string
+	getAll         bool
+	getConcurrency int
+)
+
+var documentGetCmd = &cobra.Command{
+	Use:   "get [NAME]",
+	Short: "Retrieve a document with its metadata",
+	Long: `Print a stored document's content and metadata, or write it to a file.
+
+With --out, the document's text (or, with -o json, the document as JSON) is written to the file
+instead of being printed. Without a NAME, the document is selected interactively.
+
+--all writes every document of the collection to the --out directory, one file per document named
+after the document. Names containing '/' become subdirectories, so a directory ingested with
+'document ingest' is recreated; with -o json each file holds the document and its metadata.`,
+	Example: `  maestro document get guide.md --vdb=my-vdb --collection=my-collection
+  maestro document get guide.md --vdb=my-vdb --collection=my-collection -o json
+  maestro document get guide.md --vdb=my-vdb --collection=my-collection --out=guide.txt
+  maestro document get --all --vdb=my-vdb --collection=my-collection --out=./dump`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if getAll && len(args) > 0 {
+			return fmt.Errorf("a document name cannot be combined with --all")
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if getOutput != "text" && getOutput != "json" {
+			return fmt.Errorf("unsupported output format '%s' (use text or json)", getOutput)
+		}
+		if getAll && getOut == "" {
+			return fmt.Errorf("--all requires --out DIR")
+		}
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		if getAll {
+			return getAllDocuments(vdbName, collectionName, getOut)
+		}
+
+		var documentName string
+		if len(args) > 0 {
+			documentName = args[0]
+		}
+		documentName, err := PromptForDocument(vdbName, collectionName, documentName)
+		if err != nil {
+			return fmt.Errorf("failed to select document: %w", err)
+		}
+		return getDocument(vdbName, collectionName, documentName)
+	},
+}
+
+func init() {
+	documentGetCmd.Flags().String("vdb", "", "Vector database name")
+	documentGetCmd.Flags().String("collection", "", "Collection name")
+	documentGetCmd.Flags().StringVarP(&getOutput, "output", "o", "text", "Output format (text, json)")
+	documentGetCmd.Flags().StringVar(&getOut, "out", "", "Write the document to this file (a directory with --all)")
+	documentGetCmd.Flags().BoolVar(&getAll, "all", false, "Write every document of the collection to the --out directory")
+	documentGetCmd.Flags().IntVar(&getConcurrency, "concurrency", defaultConcurrency, "Number of documents to fetch in parallel with --all")
+}
+
+func getDocument(vdbName, collectionName, documentName string) error {
+	if dryRun {
+		if !silent {
+			target := ""
+			if getOut != "" {
+				target = fmt.Sprintf(" and write it to '%s'", getOut)
+			}
+			fmt.Printf("[DRY RUN] Would get document '%s' from collection '%s' of vector database '%s'%s\n", documentName, collectionName, vdbName, target)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	doc, err := fetchDocument(client, serverURI, vdbName, collectionName, documentName)
+	if err != nil {
+		// A document written with --split-by is stored as parts
+		if joined, splitErr := fetchSplitDocument(client, serverURI, vdbName, collectionName, documentName); splitErr != nil {
+			err = splitErr
+		} else if joined != nil {
+			doc, err = joined, nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get document '%s' from collection '%s' of vector database '%s': %w", documentName, collectionName, vdbName, err)
+	}
+
+	if getOut != "" {
+		content, err := documentFileContent(doc, getOutput)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(getOut, content, 0644); err != nil {
+			return fmt.Errorf("failed to write '%s': %w", getOut, err)
+		}
+		if !silent {
+			fmt.Printf("✅ Document '%s' written to '%s' (%s)\n", documentName, getOut, formatBytes(int64(len(content))))
+		}
+		return nil
+	}
+
+	if getOutput == "json" {
+		return printJSON(doc)
+	}
+	printDocument(doc)
+	return nil
+}
+
+// getAllDocuments writes every document of a collection to a directory
+func getAllDocuments(vdbName, collectionName, dir string) error {
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would write every document of collection '%s' of vector database '%s' to '%s'\n", collectionName, vdbName, dir)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		if !silent {
+			fmt.Printf("Collection '%s' of vector database '%s' has no documents\n", collectionName, vdbName)
+		}
+		return nil
+	}
+
+	documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, names, getConcurrency)
+	documents, incomplete := groupDocumentParts(documents)
+	failures = append(failures, incomplete...)
+	total := len(logicalDocumentNames(names))
+	var size int64
+	written := 0
+	for i := range documents {
+		doc := &documents[i]
+		file := documentFilePath(dir, doc.Name, getOutput)
+		content, err := documentFileContent(doc, getOutput)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(file), 0755)
+		}
+		if err == nil {
+			err = os.WriteFile(file, content, 0644)
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", doc.Name, err))
+			continue
+		}
+		written++
+		size += int64(len(content))
+	}
+
+	if len(failures) > 0 {
+		if !silent {
+			fmt.Printf("Wrote %d of %d document(s) (%s) to '%s'\n", written, total, formatBytes(size), dir)
+			fmt.Printf("\n%d failure(s):\n", len(failures))
+			for _, failure := range failures {
+				fmt.Printf("  ❌ %s\n", failure)
+			}
+		}
+		return fmt.Errorf("%d of %d document(s) could not be written", total-written, total)
+	}
+	if !silent {
+		fmt.Printf("✅ Wrote %d document(s) (%s) from collection '%s' of vector database '%s' to '%s'\n", written, formatBytes(size), collectionName, vdbName, dir)
+	}
+	return nil
+}
+
+// printDocument prints a document's name, source and metadata followed by its text
+func printDocument(doc *DocumentRecord) {
+	fmt.Printf("Document: %s\n", doc.Name)
+	if doc.URL != "" {
+		fmt.Printf("URL: %s\n", doc.URL)
+	}
+	if len(doc.Metadata) > 0 {
+		fmt.Println("Metadata:")
+		for _, key := range sortedKeys(doc.Metadata) {
+			fmt.Printf("  %s: %v\n", key, doc.Metadata[key])
+		}
+	}
+	fmt.Println()
+	fmt.Println(doc.Text)
+}
+
+// documentFileContent returns what is written to a file for a document: its text, or JSON
+func documentFileContent(doc *DocumentRecord, format string) ([]byte, error) {
+	if format != "json" {
+		return []byte(doc.Text), nil
+	}
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document '%s': %w", doc.Name, err)
+	}
+	return append(content, '\n'), nil
+}
+
+// documentFilePath returns the file a document is written to under dir. Names are used as relative
+// paths; a name that would escape dir is escaped into a single file name instead.
+func documentFilePath(dir, name, format string) string {
+	rel := path.Clean(name)
+	if path.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") || strings.Contains(name, "\\") {
+		rel = url.PathEscape(name)
+	}
+	if format == "json" {
+		rel += ".json"
+	}
+	return filepath.Join(dir, filepath.FromSlash(rel))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocumentFilePath(t *testing.T) {
+	dir := filepath.Join("out", "dump")
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{"guide.md", "text", filepath.Join(dir, "guide.md")},
+		{"api/reference.md", "text", filepath.Join(dir, "api", "reference.md")},
+		{"api/reference.md", "json", filepath.Join(dir, "api", "reference.md.json")},
+		{"../escape", "text", filepath.Join(dir, "..%2Fescape")},
+		{"/etc/passwd", "text", filepath.Join(dir, "%2Fetc%2Fpasswd")},
+	}
+	for _, test := range tests {
+		if got := documentFilePath(dir, test.name, test.format); got != test.expected {
+			t.Errorf("documentFilePath(%q, %q) = %q, expected %q", test.name, test.format, got, test.expected)
+		}
+	}
+}
+
+func TestDocumentFileContent(t *testing.T) {
+	doc := &DocumentRecord{Name: "guide.md", Text: "# Guide", Metadata: map[string]interface{}{"owner": "docs"}}
+
+	content, err := documentFileContent(doc, "text")
+	if err != nil || string(content) != "# Guide" {
+		t.Errorf("documentFileContent() text = %q, %v", content, err)
+	}
+
+	content, err = documentFileContent(doc, "json")
+	if err != nil || !strings.Contains(string(content), `"owner": "docs"`) || !strings.Contains(string(content), `"text": "# Guide"`) {
+		t.Errorf("documentFileContent() json = %s, %v", content, err)
+	}
+}
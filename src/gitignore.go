@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ignoreFileNames are the per-directory ignore files honored by document ingest-repo, in order of
+// precedence: .maestroignore rules override .gitignore rules of the same directory
+var ignoreFileNames = []string{".gitignore", ".maestroignore"}
+
+// ignoreRule is one pattern of an ignore file, relative to the directory of that file
+type ignoreRule struct {
+	base     string // slash-separated directory of the ignore file, "" for the root
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool // the pattern contains a slash and matches from base
+}
+
+// ignoreMatcher applies ignore files with the semantics of .gitignore: the last matching rule
+// wins, '!' re-includes, a trailing slash matches only directories and a file cannot be re-included
+// when one of its parent directories is excluded
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadIgnoreMatcher reads .git/info/exclude and the ignore files of the given directories, parents
+// before children so that deeper files take precedence
+func loadIgnoreMatcher(repo *gitRepository, dirs []string) *ignoreMatcher {
+	m := &ignoreMatcher{}
+	if data, err := os.ReadFile(filepath.Join(repo.commonDir, "info", "exclude")); err == nil {
+		m.add("", string(data))
+	}
+
+	depth := func(dir string) int {
+		if dir == "" {
+			return -1
+		}
+		return strings.Count(dir, "/")
+	}
+	sorted := append([]string{}, dirs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if di, dj := depth(sorted[i]), depth(sorted[j]); di != dj {
+			return di < dj
+		}
+		return sorted[i] < sorted[j]
+	})
+	for _, dir := range sorted {
+		for _, name := range ignoreFileNames {
+			if data, err := os.ReadFile(filepath.Join(repo.Root, filepath.FromSlash(dir), name)); err == nil {
+				m.add(dir, string(data))
+			}
+		}
+	}
+	return m
+}
+
+// add parses the patterns of an ignore file found in dir
+func (m *ignoreMatcher) add(dir, content string) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line[:len(line)-2], " ") + " "
+		} else {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: dir}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		// fnmatch negates character classes with '!', path.Match with '^'
+		line = strings.ReplaceAll(line, "[!", "[^")
+		rule.segments = strings.Split(line, "/")
+		m.rules = append(m.rules, rule)
+	}
+}
+
+// Ignored reports whether a slash-separated file path is excluded by the rules
+func (m *ignoreMatcher) Ignored(rel string) bool {
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		if m.match(strings.Join(parts[:i+1], "/"), i < len(parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// match applies the rules to one path, returning the outcome of the last matching rule
+func (m *ignoreMatcher) match(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		local := rel
+		if rule.base != "" {
+			var ok bool
+			if local, ok = strings.CutPrefix(rel, rule.base+"/"); !ok {
+				continue
+			}
+		}
+		var matched bool
+		if rule.anchored {
+			matched = matchSegments(rule.segments, strings.Split(local, "/"))
+		} else {
+			matched, _ = path.Match(rule.segments[0], path.Base(local))
+		}
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,809 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitRepository reads a git repository directly from its .git directory: the index, refs, and loose
+// and packed objects. It supports SHA-1 repositories and is not safe for concurrent use.
+type gitRepository struct {
+	Root      string // the working tree
+	gitDir    string // HEAD and the index
+	commonDir string // objects, refs and config; differs from gitDir in linked worktrees
+	packs     []*gitPack
+	shallow   map[string]bool
+	trees     map[string][]gitTreeEntry
+}
+
+// gitCommit is a parsed commit object
+type gitCommit struct {
+	SHA        string
+	Tree       string
+	Parents    []string
+	Author     string // "Name <email>"
+	AuthorDate time.Time
+	Summary    string
+}
+
+// gitTreeEntry is an entry of a tree object
+type gitTreeEntry struct {
+	Mode string
+	Name string
+	SHA  string
+}
+
+// IsTree reports whether the entry is a subdirectory
+func (e gitTreeEntry) IsTree() bool {
+	return e.Mode == "40000"
+}
+
+// gitIndexEntry is a file staged in the index
+type gitIndexEntry struct {
+	Path string
+	SHA  string
+	Mode uint32
+}
+
+// Index entry modes of files maestro can read; symlinks and submodules are skipped
+const (
+	gitModeFile       = 0100644
+	gitModeExecutable = 0100755
+)
+
+// openGitRepository finds the repository containing dir, looking in dir and its parents for a .git
+// directory or a .git file pointing to one, as used by worktrees and submodules
+func openGitRepository(dir string) (*gitRepository, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for current := abs; ; current = filepath.Dir(current) {
+		dotGit := filepath.Join(current, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = readGitFile(dotGit); err != nil {
+					return nil, err
+				}
+			}
+			return newGitRepository(current, gitDir)
+		}
+		if parent := filepath.Dir(current); parent == current {
+			return nil, fmt.Errorf("'%s' is not inside a git repository", dir)
+		}
+	}
+}
+
+// readGitFile resolves a .git file of the form "gitdir: PATH"
+func readGitFile(dotGit string) (string, error) {
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s': %w", dotGit, err)
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("'%s' is not a valid .git file", dotGit)
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(dotGit), target)
+	}
+	return target, nil
+}
+
+func newGitRepository(root, gitDir string) (*gitRepository, error) {
+	repo := &gitRepository{Root: root, gitDir: gitDir, commonDir: gitDir, shallow: map[string]bool{}, trees: map[string][]gitTreeEntry{}}
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		repo.commonDir = common
+	}
+	if format := repo.configValue("extensions", "", "objectformat"); format != "" && format != "sha1" {
+		return nil, fmt.Errorf("repository '%s' uses %s object names, which are not supported", root, format)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(repo.commonDir, "shallow")); err == nil {
+		for _, sha := range strings.Fields(string(data)) {
+			repo.shallow[sha] = true
+		}
+	}
+
+	indexes, err := filepath.Glob(filepath.Join(repo.commonDir, "objects", "pack", "*.idx"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(indexes)
+	for _, idx := range indexes {
+		pack, err := openGitPack(idx)
+		if err != nil {
+			return nil, err
+		}
+		repo.packs = append(repo.packs, pack)
+	}
+	return repo, nil
+}
+
+// Close releases the pack files of the repository
+func (r *gitRepository) Close() {
+	for _, pack := range r.packs {
+		pack.file.Close()
+	}
+}
+
+// Head returns the commit HEAD points to
+func (r *gitRepository) Head() (string, error) {
+	data, err := os.ReadFile(filepath.Join(r.gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		return r.resolveRef(strings.TrimSpace(ref))
+	}
+	return head, nil
+}
+
+// resolveRef returns the commit a ref such as refs/heads/main points to
+func (r *gitRepository) resolveRef(ref string) (string, error) {
+	for _, dir := range []string{r.gitDir, r.commonDir} {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			value := strings.TrimSpace(string(data))
+			if target, ok := strings.CutPrefix(value, "ref:"); ok {
+				return r.resolveRef(strings.TrimSpace(target))
+			}
+			return value, nil
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.commonDir, "packed-refs"))
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if sha, name, ok := strings.Cut(strings.TrimSpace(line), " "); ok && name == ref {
+				return sha, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("'%s' has no commits yet", ref)
+}
+
+// configValue returns a value of the repository config, e.g. section "remote", subsection
+// "origin", key "url". Only the simple subset of the config syntax written by git is understood.
+func (r *gitRepository) configValue(section, subsection, key string) string {
+	data, err := os.ReadFile(filepath.Join(r.commonDir, "config"))
+	if err != nil {
+		return ""
+	}
+	want := strings.ToLower(section)
+	if subsection != "" {
+		want += " " + strconv.Quote(subsection)
+	}
+	inSection := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			header := strings.TrimSpace(line[1 : len(line)-1])
+			name, sub, hasSub := strings.Cut(header, " ")
+			header = strings.ToLower(name)
+			if hasSub {
+				header += " " + strings.TrimSpace(sub)
+			}
+			inSection = header == want
+			continue
+		}
+		if name, value, ok := strings.Cut(line, "="); inSection && ok && strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// Identity returns the URL of the origin remote without credentials, or the path of the working
+// tree when there is no origin
+func (r *gitRepository) Identity() string {
+	remote := r.configValue("remote", "origin", "url")
+	if remote == "" {
+		return filepath.ToSlash(r.Root)
+	}
+	if u, err := url.Parse(remote); err == nil && u.User != nil {
+		u.User = nil
+		return u.String()
+	}
+	return remote
+}
+
+// ReadObject returns the type and content of an object
+func (r *gitRepository) ReadObject(sha string) (string, []byte, error) {
+	if len(sha) != 40 {
+		return "", nil, fmt.Errorf("invalid object name '%s'", sha)
+	}
+	if data, err := os.ReadFile(filepath.Join(r.commonDir, "objects", sha[:2], sha[2:])); err == nil {
+		return parseLooseObject(sha, data)
+	}
+	raw, err := hex.DecodeString(sha)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid object name '%s'", sha)
+	}
+	for _, pack := range r.packs {
+		if offset, ok := pack.find(raw); ok {
+			return pack.read(r, offset)
+		}
+	}
+	return "", nil, fmt.Errorf("object %s not found", sha)
+}
+
+// parseLooseObject inflates a loose object and splits its "TYPE SIZE\0" header from the content
+func parseLooseObject(sha string, data []byte) (string, []byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read object %s: %w", sha, err)
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read object %s: %w", sha, err)
+	}
+	header, body, ok := bytes.Cut(content, []byte{0})
+	if !ok {
+		return "", nil, fmt.Errorf("object %s is corrupt", sha)
+	}
+	kind, _, _ := strings.Cut(string(header), " ")
+	return kind, body, nil
+}
+
+// ReadCommit reads and parses a commit object
+func (r *gitRepository) ReadCommit(sha string) (*gitCommit, error) {
+	kind, data, err := r.ReadObject(sha)
+	if err != nil {
+		return nil, err
+	}
+	if kind != "commit" {
+		return nil, fmt.Errorf("object %s is a %s, not a commit", sha, kind)
+	}
+	return parseCommit(sha, data), nil
+}
+
+// parseCommit parses the headers and the first message line of a commit object
+func parseCommit(sha string, data []byte) *gitCommit {
+	commit := &gitCommit{SHA: sha}
+	headers, message, _ := strings.Cut(string(data), "\n\n")
+	for _, line := range strings.Split(headers, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			commit.Tree = value
+		case "parent":
+			commit.Parents = append(commit.Parents, value)
+		case "author":
+			commit.Author, commit.AuthorDate = parseSignature(value)
+		}
+	}
+	commit.Summary, _, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	return commit
+}
+
+// parseSignature splits "Name <email> 1700000000 +0100" into the identity and its time
+func parseSignature(value string) (string, time.Time) {
+	end := strings.LastIndex(value, ">")
+	if end < 0 {
+		return value, time.Time{}
+	}
+	identity := value[:end+1]
+	fields := strings.Fields(value[end+1:])
+	if len(fields) < 1 {
+		return identity, time.Time{}
+	}
+	seconds, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return identity, time.Time{}
+	}
+	when := time.Unix(seconds, 0).UTC()
+	if len(fields) > 1 && len(fields[1]) == 5 {
+		hours, _ := strconv.Atoi(fields[1][1:3])
+		minutes, _ := strconv.Atoi(fields[1][3:5])
+		offset := hours*3600 + minutes*60
+		if fields[1][0] == '-' {
+			offset = -offset
+		}
+		when = when.In(time.FixedZone(fields[1], offset))
+	}
+	return identity, when
+}
+
+// ReadTree reads and parses a tree object; trees are cached because history walks revisit them
+func (r *gitRepository) ReadTree(sha string) ([]gitTreeEntry, error) {
+	if entries, ok := r.trees[sha]; ok {
+		return entries, nil
+	}
+	kind, data, err := r.ReadObject(sha)
+	if err != nil {
+		return nil, err
+	}
+	if kind != "tree" {
+		return nil, fmt.Errorf("object %s is a %s, not a tree", sha, kind)
+	}
+
+	var entries []gitTreeEntry
+	for len(data) > 0 {
+		header, rest, ok := bytes.Cut(data, []byte{0})
+		if !ok || len(rest) < 20 {
+			return nil, fmt.Errorf("tree %s is corrupt", sha)
+		}
+		mode, name, _ := strings.Cut(string(header), " ")
+		entries = append(entries, gitTreeEntry{Mode: mode, Name: name, SHA: hex.EncodeToString(rest[:20])})
+		data = rest[20:]
+	}
+	if len(r.trees) >= 100000 {
+		r.trees = map[string][]gitTreeEntry{}
+	}
+	r.trees[sha] = entries
+	return entries, nil
+}
+
+// TreeFiles returns the blob of every file under a tree, keyed by slash-separated path
+func (r *gitRepository) TreeFiles(tree string) (map[string]string, error) {
+	files := make(map[string]string)
+	var walk func(sha, prefix string) error
+	walk = func(sha, prefix string) error {
+		entries, err := r.ReadTree(sha)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsTree() {
+				if err := walk(entry.SHA, prefix+entry.Name+"/"); err != nil {
+					return err
+				}
+				continue
+			}
+			files[prefix+entry.Name] = entry.SHA
+		}
+		return nil
+	}
+	return files, walk(tree, "")
+}
+
+// diffTrees calls changed for every file path whose blob differs between two trees. An empty tree
+// name stands for an empty tree. Subtrees that contain none of the wanted directories are skipped.
+func (r *gitRepository) diffTrees(a, b, prefix string, wanted map[string]bool, changed func(string)) error {
+	read := func(sha string) (map[string]gitTreeEntry, error) {
+		entries := make(map[string]gitTreeEntry)
+		if sha == "" {
+			return entries, nil
+		}
+		list, err := r.ReadTree(sha)
+		for _, entry := range list {
+			entries[entry.Name] = entry
+		}
+		return entries, err
+	}
+	left, err := read(a)
+	if err != nil {
+		return err
+	}
+	right, err := read(b)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for name := range left {
+		names[name] = true
+	}
+	for name := range right {
+		names[name] = true
+	}
+	for name := range names {
+		l, inLeft := left[name]
+		rt, inRight := right[name]
+		if inLeft && inRight && l.SHA == rt.SHA && l.Mode == rt.Mode {
+			continue
+		}
+		full := prefix + name
+		var leftTree, rightTree string
+		if inLeft && l.IsTree() {
+			leftTree = l.SHA
+		}
+		if inRight && rt.IsTree() {
+			rightTree = rt.SHA
+		}
+		if (inLeft && !l.IsTree()) || (inRight && !rt.IsTree()) {
+			changed(full)
+		}
+		if (leftTree != "" || rightTree != "") && wanted[full+"/"] {
+			if err := r.diffTrees(leftTree, rightTree, full+"/", wanted, changed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LastCommits finds the commit that last changed each of the given files of HEAD, following the
+// first-parent history. Files are attributed to the oldest commit reachable when the history is
+// shallow or incomplete.
+func (r *gitRepository) LastCommits(head string, paths []string) (map[string]*gitCommit, error) {
+	remaining := make(map[string]bool, len(paths))
+	wanted := map[string]bool{}
+	for _, p := range paths {
+		remaining[p] = true
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			wanted[dir+"/"] = true
+		}
+	}
+
+	result := make(map[string]*gitCommit, len(paths))
+	commit, err := r.ReadCommit(head)
+	if err != nil {
+		return nil, err
+	}
+	for len(remaining) > 0 {
+		var parent *gitCommit
+		if len(commit.Parents) > 0 && !r.shallow[commit.SHA] {
+			// A missing parent ends the walk as if the commit were the root
+			parent, _ = r.ReadCommit(commit.Parents[0])
+		}
+
+		parentTree := ""
+		if parent != nil {
+			parentTree = parent.Tree
+		}
+		current := commit
+		if err := r.diffTrees(commit.Tree, parentTree, "", wanted, func(p string) {
+			if remaining[p] {
+				result[p] = current
+				delete(remaining, p)
+			}
+		}); err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+		commit = parent
+	}
+	return result, nil
+}
+
+// ReadIndex returns the files staged at stage 0 of the index, sorted by path
+func (r *gitRepository) ReadIndex() ([]gitIndexEntry, error) {
+	data, err := os.ReadFile(filepath.Join(r.gitDir, "index"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read the git index: %w", err)
+	}
+	return parseGitIndex(data)
+}
+
+// parseGitIndex parses index file versions 2, 3 and 4
+func parseGitIndex(data []byte) ([]gitIndexEntry, error) {
+	corrupt := fmt.Errorf("the git index is corrupt")
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, corrupt
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("git index version %d is not supported", version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+
+	var entries []gitIndexEntry
+	pos := 12
+	previous := ""
+	for i := 0; i < count; i++ {
+		start := pos
+		if pos+62 > len(data) {
+			return nil, corrupt
+		}
+		mode := binary.BigEndian.Uint32(data[pos+24:])
+		sha := hex.EncodeToString(data[pos+40 : pos+60])
+		flags := binary.BigEndian.Uint16(data[pos+60:])
+		pos += 62
+		if version >= 3 && flags&0x4000 != 0 {
+			pos += 2
+		}
+
+		var name string
+		if version == 4 {
+			strip, n := readOffsetVarint(data[pos:])
+			if n == 0 || strip > uint64(len(previous)) {
+				return nil, corrupt
+			}
+			pos += n
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, corrupt
+			}
+			name = previous[:len(previous)-int(strip)] + string(data[pos:pos+end])
+			pos += end + 1
+		} else {
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, corrupt
+			}
+			name = string(data[pos : pos+end])
+			// Entries are padded with 1 to 8 NUL bytes to a multiple of 8 bytes
+			pos = start + (pos+end-start+8)/8*8
+		}
+		previous = name
+
+		if stage := (flags >> 12) & 3; stage == 0 {
+			entries = append(entries, gitIndexEntry{Path: name, SHA: sha, Mode: mode})
+		}
+	}
+	return entries, nil
+}
+
+// readOffsetVarint decodes git's offset varint, used for OFS_DELTA offsets and index v4 paths
+func readOffsetVarint(data []byte) (uint64, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	value := uint64(c & 0x7f)
+	n := 1
+	for c&0x80 != 0 {
+		if n >= len(data) {
+			return 0, 0
+		}
+		c = data[n]
+		n++
+		value = ((value + 1) << 7) | uint64(c&0x7f)
+	}
+	return value, n
+}
+
+// gitBlobHash returns the object name git gives to a file's content
+func gitBlobHash(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gitPack is a pack file and its version 2 index
+type gitPack struct {
+	file    *os.File
+	names   []byte // sorted 20-byte object names
+	offsets []uint32
+	large   []byte // 8-byte offsets of objects beyond 2 GiB
+	cache   map[int64]gitPackObject
+}
+
+// gitPackObject is an object read from a pack, cached because deltas share their bases
+type gitPackObject struct {
+	kind string
+	data []byte
+}
+
+// Limits of the per-pack object cache
+const (
+	gitPackCacheEntries = 4096
+	gitPackCacheMaxSize = 1 << 20
+)
+
+func openGitPack(idxPath string) (*gitPack, error) {
+	data, err := os.ReadFile(idxPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack index '%s': %w", idxPath, err)
+	}
+	if len(data) < 8+256*4 || !bytes.Equal(data[:4], []byte{0xff, 't', 'O', 'c'}) || binary.BigEndian.Uint32(data[4:8]) != 2 {
+		return nil, fmt.Errorf("pack index '%s' is not a version 2 index", idxPath)
+	}
+	count := int(binary.BigEndian.Uint32(data[8+255*4:]))
+	namesStart := 8 + 256*4
+	offsetsStart := namesStart + count*20 + count*4
+	if len(data) < offsetsStart+count*4 {
+		return nil, fmt.Errorf("pack index '%s' is corrupt", idxPath)
+	}
+
+	pack := &gitPack{names: data[namesStart : namesStart+count*20], offsets: make([]uint32, count), large: data[offsetsStart+count*4:], cache: map[int64]gitPackObject{}}
+	for i := range pack.offsets {
+		pack.offsets[i] = binary.BigEndian.Uint32(data[offsetsStart+i*4:])
+	}
+	pack.file, err = os.Open(strings.TrimSuffix(idxPath, ".idx") + ".pack")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pack: %w", err)
+	}
+	return pack, nil
+}
+
+// find returns the offset of an object in the pack
+func (p *gitPack) find(name []byte) (int64, bool) {
+	count := len(p.offsets)
+	i := sort.Search(count, func(i int) bool { return bytes.Compare(p.names[i*20:i*20+20], name) >= 0 })
+	if i == count || !bytes.Equal(p.names[i*20:i*20+20], name) {
+		return 0, false
+	}
+	offset := p.offsets[i]
+	if offset&0x80000000 == 0 {
+		return int64(offset), true
+	}
+	j := int(offset & 0x7fffffff)
+	if len(p.large) < j*8+8 {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(p.large[j*8:])), true
+}
+
+// Pack object types
+const (
+	gitPackCommit   = 1
+	gitPackTree     = 2
+	gitPackBlob     = 3
+	gitPackTag      = 4
+	gitPackOfsDelta = 6
+	gitPackRefDelta = 7
+)
+
+// read returns the type and content of the object at offset, resolving deltas
+func (p *gitPack) read(repo *gitRepository, offset int64) (string, []byte, error) {
+	if object, ok := p.cache[offset]; ok {
+		return object.kind, object.data, nil
+	}
+	kind, data, err := p.readUncached(repo, offset)
+	if err == nil && len(data) <= gitPackCacheMaxSize {
+		if len(p.cache) >= gitPackCacheEntries {
+			p.cache = map[int64]gitPackObject{}
+		}
+		p.cache[offset] = gitPackObject{kind: kind, data: data}
+	}
+	return kind, data, err
+}
+
+func (p *gitPack) readUncached(repo *gitRepository, offset int64) (string, []byte, error) {
+	reader := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+	c, err := reader.ReadByte()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read pack: %w", err)
+	}
+	kind := (c >> 4) & 7
+	size := uint64(c & 0x0f)
+	for shift := 4; c&0x80 != 0; shift += 7 {
+		if c, err = reader.ReadByte(); err != nil {
+			return "", nil, fmt.Errorf("failed to read pack: %w", err)
+		}
+		size |= uint64(c&0x7f) << shift
+	}
+
+	var baseKind string
+	var base []byte
+	switch kind {
+	case gitPackOfsDelta:
+		header := make([]byte, 0, 10)
+		for {
+			c, err := reader.ReadByte()
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to read pack: %w", err)
+			}
+			header = append(header, c)
+			if c&0x80 == 0 {
+				break
+			}
+		}
+		distance, _ := readOffsetVarint(header)
+		if baseKind, base, err = p.read(repo, offset-int64(distance)); err != nil {
+			return "", nil, err
+		}
+	case gitPackRefDelta:
+		name := make([]byte, 20)
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return "", nil, fmt.Errorf("failed to read pack: %w", err)
+		}
+		if baseKind, base, err = repo.ReadObject(hex.EncodeToString(name)); err != nil {
+			return "", nil, err
+		}
+	}
+
+	zr, err := zlib.NewReader(reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read pack: %w", err)
+	}
+	defer zr.Close()
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return "", nil, fmt.Errorf("failed to read pack: %w", err)
+	}
+
+	switch kind {
+	case gitPackCommit:
+		return "commit", data, nil
+	case gitPackTree:
+		return "tree", data, nil
+	case gitPackBlob:
+		return "blob", data, nil
+	case gitPackTag:
+		return "tag", data, nil
+	case gitPackOfsDelta, gitPackRefDelta:
+		result, err := applyGitDelta(base, data)
+		return baseKind, result, err
+	}
+	return "", nil, fmt.Errorf("unknown pack object type %d", kind)
+}
+
+// applyGitDelta rebuilds an object from its base and a delta of copy and insert instructions
+func applyGitDelta(base, delta []byte) ([]byte, error) {
+	corrupt := fmt.Errorf("pack delta is corrupt")
+	readSize := func() (uint64, bool) {
+		var size uint64
+		for shift := 0; len(delta) > 0; shift += 7 {
+			c := delta[0]
+			delta = delta[1:]
+			size |= uint64(c&0x7f) << shift
+			if c&0x80 == 0 {
+				return size, true
+			}
+		}
+		return 0, false
+	}
+	baseSize, ok := readSize()
+	if !ok || baseSize != uint64(len(base)) {
+		return nil, corrupt
+	}
+	targetSize, ok := readSize()
+	if !ok {
+		return nil, corrupt
+	}
+
+	result := make([]byte, 0, targetSize)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		switch {
+		case op&0x80 != 0:
+			var offset, size uint64
+			for i := 0; i < 7; i++ {
+				if op&(1<<i) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, corrupt
+				}
+				if i < 4 {
+					offset |= uint64(delta[0]) << (8 * i)
+				} else {
+					size |= uint64(delta[0]) << (8 * (i - 4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > uint64(len(base)) {
+				return nil, corrupt
+			}
+			result = append(result, base[offset:offset+size]...)
+		case op != 0:
+			if int(op) > len(delta) {
+				return nil, corrupt
+			}
+			result = append(result, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, corrupt
+		}
+	}
+	if uint64(len(result)) != targetSize {
+		return nil, corrupt
+	}
+	return result, nil
+}
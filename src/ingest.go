@@ -0,0 +1,602 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// contentHashMetadataKey is the document metadata key holding the SHA-256 of the ingested file
+const contentHashMetadataKey = "content_sha256"
+
+// Flags for document ingestion
+var (
+	ingestInclude     []string
+	ingestExclude     []string
+	ingestHidden      bool
+	ingestConcurrency int
+	ingestOutput      string
+	ingestResume      string
+)
+
+var documentIngestCmd = &cobra.Command{
+	Use:   "ingest PATH...",
+	Short: "Ingest files and directories into a collection",
+	Long: `Write every file under the given paths to a collection as one document per file.
+
+Document names are derived from file paths relative to the directory argument they were found in
+(a file argument is named after its base name), using forward slashes on every platform.
+Use --include and --exclude to filter files by glob: a pattern without a slash matches the file
+name, a pattern with a slash matches the relative path, and '**' matches any number of directories.
+Hidden files and directories are skipped unless --hidden is set. Text is extracted from Markdown,
+HTML and PDF files as shown by 'document extract'; --raw sends file contents unchanged. YAML front
+matter of Markdown files becomes document metadata, and --meta and --meta-file add metadata to every
+document.
+
+--redact scans each document for emails, phone numbers, credit cards, cloud keys, JWTs, private keys
+and high-entropy tokens before it is written: 'mask' replaces them, 'drop' skips the document and
+'report' only lists them. --redact-config adds regex rules, disables detectors and allow-lists values.
+
+Each document stores SimHash and MinHash fingerprints of its text in its metadata. --dedupe compares
+the files with each other and with the fingerprinted documents of the collection before writing:
+'warn' lists near-duplicates in the summary and 'skip' does not write them. --dedupe-threshold sets
+the estimated share of common word shingles at which documents are near-duplicates.
+
+Failures do not stop the ingestion; they are collected into a summary at the end. Every run keeps a
+manifest under .maestro/ingest/RUN_ID.json recording each file's path, content hash, document name,
+status and error. Use --resume RUN_ID to retry the failed and unprocessed files of a previous run, and
+'maestro ingest runs list' to find past runs.`,
+	Example: `  maestro document ingest ./docs --vdb=my-vdb --collection=my-collection
+  maestro document ingest ./docs ./notes --vdb=my-vdb --collection=my-collection --include='*.md' --exclude='drafts/**'
+  maestro document ingest ./corpus --vdb=my-vdb --collection=my-collection --concurrency=8 -o json
+  maestro document ingest ./wiki --vdb=my-vdb --collection=wiki --redact=mask --redact-config=redact.yaml
+  maestro document ingest ./wiki --vdb=my-vdb --collection=wiki --dedupe=skip --dedupe-threshold=0.85
+  maestro document ingest --resume 20240102-150405-a1b2`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if ingestResume != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("paths cannot be combined with --resume; the run's manifest lists its files")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		if ingestResume != "" {
+			return resumeIngestRun(ingestResume, vdbName, collectionName)
+		}
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return ingestDocuments(vdbName, collectionName, args)
+	},
+}
+
+func init() {
+	documentIngestCmd.Flags().String("vdb", "", "Vector database name")
+	documentIngestCmd.Flags().String("collection", "", "Collection name")
+	documentIngestCmd.Flags().StringSliceVar(&ingestInclude, "include", nil, "Only ingest files matching these globs (repeatable)")
+	documentIngestCmd.Flags().StringSliceVar(&ingestExclude, "exclude", nil, "Skip files matching these globs (repeatable)")
+	documentIngestCmd.Flags().BoolVar(&ingestHidden, "hidden", false, "Include hidden files and directories")
+	documentIngestCmd.Flags().IntVar(&ingestConcurrency, "concurrency", defaultConcurrency, "Number of files to ingest in parallel")
+	documentIngestCmd.Flags().StringVarP(&ingestOutput, "output", "o", "text", "Summary format (text, json)")
+	documentIngestCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send file contents as-is instead of extracting their text")
+	addMetadataFlags(documentIngestCmd)
+	addMaxSizeFlag(documentIngestCmd)
+	addRedactFlags(documentIngestCmd)
+	addDedupeFlags(documentIngestCmd)
+	documentIngestCmd.Flags().StringVar(&ingestResume, "resume", "", "Resume a previous run, retrying its failed and unprocessed files")
+}
+
+// ingestFile is a file selected for ingestion and the document name derived from it
+type ingestFile struct {
+	Path     string
+	Name     string
+	Size     int64
+	Metadata map[string]interface{} // recorded by maestro in addition to the user metadata
+}
+
+// IngestFailure records a file that could not be ingested
+type IngestFailure struct {
+	Path     string `json:"path"`
+	Document string `json:"document"`
+	Error    string `json:"error"`
+}
+
+// IngestReport summarizes an ingestion run
+type IngestReport struct {
+	RunID      string           `json:"run_id,omitempty"`
+	Database   string           `json:"database"`
+	Collection string           `json:"collection"`
+	Total      int              `json:"total"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Dropped    int              `json:"dropped"`
+	Bytes      int64            `json:"bytes"`
+	Duration   float64          `json:"duration_seconds"`
+	Failures   []IngestFailure  `json:"failures"`
+	Redaction  *RedactionReport `json:"redaction,omitempty"`
+	Duplicates []DuplicateMatch `json:"duplicates,omitempty"`
+}
+
+func ingestDocuments(vdbName, collectionName string, paths []string) error {
+	if err := validateIngestOutput(); err != nil {
+		return err
+	}
+	for _, pattern := range append(append([]string{}, ingestInclude...), ingestExclude...) {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+	if err := validateDedupe(); err != nil {
+		return err
+	}
+
+	files, err := collectIngestFiles(paths, ingestInclude, ingestExclude, ingestHidden)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files to ingest under %s", strings.Join(paths, ", "))
+	}
+
+	if verbose {
+		fmt.Printf("Ingesting %d file(s) into collection '%s' of vector database '%s'...\n", len(files), collectionName, vdbName)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would ingest %d file(s) into collection '%s' of vector database '%s'\n", len(files), collectionName, vdbName)
+			for _, file := range files {
+				fmt.Printf("  %s → %s\n", file.Path, file.Name)
+			}
+		}
+		return nil
+	}
+
+	manifest, err := newIngestManifest(vdbName, collectionName, paths, files)
+	if err != nil {
+		return err
+	}
+	return runIngest(manifest, files, false)
+}
+
+// resumeIngestRun continues a previous run with its failed and unprocessed files
+func resumeIngestRun(id, vdbName, collectionName string) error {
+	if err := validateIngestOutput(); err != nil {
+		return err
+	}
+	if len(ingestInclude) > 0 || len(ingestExclude) > 0 {
+		return fmt.Errorf("--include and --exclude cannot be combined with --resume; the run's manifest lists its files")
+	}
+	if len(metaPairs) > 0 || metaFile != "" {
+		return fmt.Errorf("--meta and --meta-file cannot be combined with --resume; the run's manifest records its metadata")
+	}
+	if redactMode != "" || redactConfigFile != "" {
+		return fmt.Errorf("--redact and --redact-config cannot be combined with --resume; the run's manifest records its redaction")
+	}
+	if dedupeMode != "" {
+		return fmt.Errorf("--dedupe cannot be combined with --resume; near-duplicates skipped by the run are not retried")
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+
+	manifest, err := loadIngestManifest(id)
+	if err != nil {
+		return err
+	}
+	userMetadata = manifest.Metadata
+	redactMode, redactConfigFile = manifest.Redact, manifest.RedactConfig
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+	if vdbName != "" && vdbName != manifest.Database {
+		return fmt.Errorf("run '%s' ingested into vector database '%s', not '%s'", id, manifest.Database, vdbName)
+	}
+	if collectionName != "" && collectionName != manifest.Collection {
+		return fmt.Errorf("run '%s' ingested into collection '%s', not '%s'", id, manifest.Collection, collectionName)
+	}
+
+	files := manifest.remaining()
+	if len(files) == 0 {
+		if !silent {
+			fmt.Printf("✅ Run '%s' already ingested all %d file(s)\n", id, len(manifest.Files))
+		}
+		return nil
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would resume run '%s', ingesting %d of %d file(s) into collection '%s' of vector database '%s'\n",
+				id, len(files), len(manifest.Files), manifest.Collection, manifest.Database)
+			for _, file := range files {
+				fmt.Printf("  %s → %s\n", file.Path, file.Name)
+			}
+		}
+		return nil
+	}
+
+	return runIngest(manifest, files, true)
+}
+
+// validateIngestOutput checks the --output flag of document ingest
+func validateIngestOutput() error {
+	if ingestOutput != "text" && ingestOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", ingestOutput)
+	}
+	return nil
+}
+
+// runIngest ingests files into the manifest's collection and keeps the manifest up to date.
+// When resuming, pending files whose document already exists were written before the previous
+// run stopped and are recorded as ingested instead of being written again.
+func runIngest(manifest *IngestManifest, files []ingestFile, resuming bool) error {
+	vdbName, collectionName := manifest.Database, manifest.Collection
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return err
+	}
+
+	if resuming {
+		existing, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+		if err != nil {
+			return err
+		}
+		present := make(map[string]bool, len(existing))
+		for _, name := range existing {
+			present[name] = true
+		}
+		var pending []ingestFile
+		for _, file := range files {
+			if present[file.Name] && manifest.Files[manifest.index[file.Name]].Status == ingestStatusPending {
+				if err := manifest.record(file.Name, "", nil); err != nil {
+					return err
+				}
+				continue
+			}
+			pending = append(pending, file)
+		}
+		if verbose && len(pending) < len(files) {
+			fmt.Printf("%d pending file(s) were already written by the previous run\n", len(files)-len(pending))
+		}
+		files = pending
+	}
+
+	var duplicates []DuplicateMatch
+	if !resuming {
+		if duplicates, err = planDuplicates(client, serverURI, vdbName, collectionName, files, ingestConcurrency); err != nil {
+			return err
+		}
+	}
+
+	if err := manifest.Save(); err != nil {
+		return err
+	}
+	if !silent && ingestOutput == "text" {
+		fmt.Printf("Ingestion run: %s\n", manifest.ID)
+	}
+
+	report := ingestFiles(client, serverURI, manifest, files, ingestConcurrency)
+	report.Duplicates = duplicates
+	if err := manifest.Save(); err != nil {
+		return err
+	}
+	return finishIngestReport(report)
+}
+
+// finishIngestReport prints an ingestion summary and returns an error when any file failed
+func finishIngestReport(report *IngestReport) error {
+	if ingestOutput == "json" {
+		if err := printJSON(report); err != nil {
+			return fmt.Errorf("failed to encode ingestion report: %w", err)
+		}
+	} else if !silent {
+		printIngestReport(report)
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed to ingest; retry them with 'maestro document ingest --resume %s'", report.Failed, report.Total, report.RunID)
+	}
+	return nil
+}
+
+// ingestFiles writes files to the manifest's collection, recording every outcome in the manifest
+func ingestFiles(client *MCPClient, serverURI string, manifest *IngestManifest, files []ingestFile, concurrency int) *IngestReport {
+	vdbName, collectionName := manifest.Database, manifest.Collection
+	start := time.Now()
+	progress := newBulkProgress(fmt.Sprintf("Ingesting %d file(s) into '%s'...", len(files), collectionName), len(files))
+	progress.SetUnit("files")
+
+	report := &IngestReport{RunID: manifest.ID, Database: vdbName, Collection: collectionName, Total: len(files), Failures: []IngestFailure{}}
+	var mu sync.Mutex
+	runConcurrently(files, concurrency, func(file ingestFile) {
+		defer progress.Increment()
+
+		hash, err := ingestFileDocument(client, serverURI, vdbName, collectionName, file)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if recordErr := manifest.record(file.Name, hash, err); recordErr != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", recordErr)
+		}
+		if isRedactionDrop(err) || isDuplicateSkip(err) {
+			report.Dropped++
+			return
+		}
+		if err != nil {
+			report.Failures = append(report.Failures, IngestFailure{Path: file.Path, Document: file.Name, Error: err.Error()})
+			return
+		}
+		report.Succeeded++
+		report.Bytes += file.Size
+	})
+
+	report.Failed = len(report.Failures)
+	report.Duration = time.Since(start).Seconds()
+	report.Redaction = redactionReport()
+	sort.Slice(report.Failures, func(i, j int) bool { return report.Failures[i].Path < report.Failures[j].Path })
+	progress.Stop(fmt.Sprintf("Ingested %d file(s)", report.Succeeded), report.Failed)
+	return report
+}
+
+// ingestFileDocument reads one file, writes it as a document and returns the content hash
+func ingestFileDocument(client *MCPClient, serverURI, vdbName, collectionName string, file ingestFile) (string, error) {
+	doc, err := prepareFileDocument(file)
+	if err != nil {
+		return doc.Hash, err
+	}
+	return doc.Hash, writeDocumentText(client, serverURI, vdbName, collectionName, file.Name, doc.Text, file.Path, doc.Metadata)
+}
+
+// fileDocument is a file ready to be written: its content hash, extracted text and metadata
+type fileDocument struct {
+	Hash     string
+	Text     string
+	Metadata map[string]interface{}
+}
+
+// prepareFileDocument reads one file and runs it through extraction, redaction and the size limits
+// without contacting the server. The hash is set once the file could be read, even on error.
+func prepareFileDocument(file ingestFile) (fileDocument, error) {
+	content, err := os.ReadFile(file.Path)
+	if err != nil {
+		return fileDocument{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	doc := fileDocument{Hash: contentHash(content)}
+	if err := checkDuplicate(file.Name); err != nil {
+		return doc, err
+	}
+	extracted, err := extractText(file.Path, content, extractRaw)
+	if err != nil {
+		return doc, err
+	}
+	if err := redactDocument(file.Name, extracted); err != nil {
+		return doc, err
+	}
+	if err := checkDocumentSize(file.Name, extracted.Text); err != nil {
+		return doc, err
+	}
+
+	metadata := documentMetadata(extracted, userMetadata)
+	for key, value := range file.Metadata {
+		metadata[key] = value
+	}
+	metadata[contentHashMetadataKey] = doc.Hash
+	if fp := computeFingerprint(extracted.Text); fp != nil {
+		for key, value := range fp.Metadata() {
+			metadata[key] = value
+		}
+	}
+	doc.Text, doc.Metadata = extracted.Text, metadata
+	return doc, nil
+}
+
+// contentHash returns the hex SHA-256 of file content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// printIngestReport prints an ingestion summary as text
+func printIngestReport(report *IngestReport) {
+	rate := 0.0
+	if report.Duration > 0 {
+		rate = float64(report.Succeeded) / report.Duration
+	}
+	defer printDuplicateMatches(report.Duplicates)
+	defer printRedactionReport(report.Redaction)
+	if report.Failed == 0 {
+		fmt.Printf("✅ Ingested %d file(s) (%s) into collection '%s' of vector database '%s' in %.2fs (%.1f files/s)\n",
+			report.Succeeded, formatBytes(report.Bytes), report.Collection, report.Database, report.Duration, rate)
+		return
+	}
+
+	fmt.Printf("Ingested %d of %d file(s) (%s) into collection '%s' of vector database '%s' in %.2fs (%.1f files/s)\n",
+		report.Succeeded, report.Total, formatBytes(report.Bytes), report.Collection, report.Database, report.Duration, rate)
+	fmt.Printf("\n%d failure(s):\n", report.Failed)
+	for _, failure := range report.Failures {
+		fmt.Printf("  ❌ %s: %s\n", failure.Path, failure.Error)
+	}
+	fmt.Printf("\nRetry the failed files with: maestro document ingest --resume %s\n", report.RunID)
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// collectIngestFiles walks the given paths and returns the files to ingest sorted by document name
+func collectIngestFiles(paths, include, exclude []string, hidden bool) ([]ingestFile, error) {
+	var files []ingestFile
+	owners := make(map[string]string)
+
+	add := func(filePath, name string, size int64) error {
+		if !matchesFilters(name, include, exclude) {
+			return nil
+		}
+		if other, ok := owners[name]; ok {
+			return fmt.Errorf("files '%s' and '%s' would both be ingested as document '%s'", other, filePath, name)
+		}
+		owners[name] = filePath
+		files = append(files, ingestFile{Path: filePath, Name: name, Size: size})
+		return nil
+	}
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read path '%s': %w", root, err)
+		}
+		if !info.IsDir() {
+			if err := add(root, filepath.Base(root), info.Size()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if filePath != root && !hidden && strings.HasPrefix(entry.Name(), ".") {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, filePath)
+			if err != nil {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			return add(filePath, filepath.ToSlash(rel), info.Size())
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk '%s': %w", root, err)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// matchesFilters reports whether a relative path passes the include and exclude globs
+func matchesFilters(rel string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matchGlob(pattern, rel) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlob checks the syntax of an include or exclude pattern
+func validateGlob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid glob '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchGlob matches a slash-separated relative path against a glob. A pattern without a slash
+// matches the base name; '**' matches zero or more directories.
+func matchGlob(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
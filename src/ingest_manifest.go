@@ -0,0 +1,447 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ingestManifestDir holds one manifest per ingestion run, relative to the working directory
+var ingestManifestDir = filepath.Join(".maestro", "ingest")
+
+// ingestManifestVersion is the format version written to new manifests
+const ingestManifestVersion = 1
+
+// ingestManifestSaveInterval limits how often a running ingestion rewrites its manifest
+const ingestManifestSaveInterval = time.Second
+
+// Status of a file in an ingestion manifest
+const (
+	ingestStatusPending  = "pending"
+	ingestStatusIngested = "ingested"
+	ingestStatusFailed   = "failed"
+	ingestStatusDropped  = "dropped"
+)
+
+// IngestManifest records the files of an ingestion run and the outcome for each of them
+type IngestManifest struct {
+	Version      int                    `json:"version"`
+	ID           string                 `json:"id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Database     string                 `json:"database"`
+	Collection   string                 `json:"collection"`
+	Paths        []string               `json:"paths"`
+	Include      []string               `json:"include,omitempty"`
+	Exclude      []string               `json:"exclude,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Redact       string                 `json:"redact,omitempty"`
+	RedactConfig string                 `json:"redact_config,omitempty"`
+	Files        []IngestManifestEntry  `json:"files"`
+
+	mu       sync.Mutex
+	path     string
+	index    map[string]int
+	lastSave time.Time
+}
+
+// IngestManifestEntry is the state of one file in an ingestion run
+type IngestManifestEntry struct {
+	Path     string `json:"path"`
+	Document string `json:"document"`
+	Size     int64  `json:"size"`
+	Hash     string `json:"sha256,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// newIngestManifest creates a manifest with a fresh run ID for the given files
+func newIngestManifest(vdbName, collectionName string, paths []string, files []ingestFile) (*IngestManifest, error) {
+	id, err := newIngestRunID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	manifest := &IngestManifest{
+		Version:      ingestManifestVersion,
+		ID:           id,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Database:     vdbName,
+		Collection:   collectionName,
+		Paths:        paths,
+		Include:      ingestInclude,
+		Exclude:      ingestExclude,
+		Metadata:     userMetadata,
+		Redact:       redactMode,
+		RedactConfig: redactConfigFile,
+		path:         ingestManifestPath(id),
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, IngestManifestEntry{Path: file.Path, Document: file.Name, Size: file.Size, Status: ingestStatusPending})
+	}
+	manifest.buildIndex()
+	return manifest, nil
+}
+
+// newIngestRunID returns a sortable, unique run ID such as 20240102-150405-a1b2
+func newIngestRunID() (string, error) {
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix), nil
+}
+
+func ingestManifestPath(id string) string {
+	return filepath.Join(ingestManifestDir, id+".json")
+}
+
+// loadIngestManifest reads the manifest of a previous run
+func loadIngestManifest(id string) (*IngestManifest, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid run ID '%s'", id)
+	}
+	manifestPath := ingestManifestPath(id)
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("ingestion run '%s' not found in %s", id, ingestManifestDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest '%s': %w", manifestPath, err)
+	}
+
+	var manifest IngestManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest '%s': %w", manifestPath, err)
+	}
+	if manifest.Version > ingestManifestVersion {
+		return nil, fmt.Errorf("manifest '%s' has unsupported version %d (this CLI supports version %d)", manifestPath, manifest.Version, ingestManifestVersion)
+	}
+	manifest.path = manifestPath
+	manifest.buildIndex()
+	return &manifest, nil
+}
+
+// listIngestManifests returns all recorded runs, newest first
+func listIngestManifests() ([]*IngestManifest, error) {
+	entries, err := os.ReadDir(ingestManifestDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ingestManifestDir, err)
+	}
+
+	var manifests []*IngestManifest
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		manifest, err := loadIngestManifest(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].ID > manifests[j].ID })
+	return manifests, nil
+}
+
+func (m *IngestManifest) buildIndex() {
+	m.index = make(map[string]int, len(m.Files))
+	for i, entry := range m.Files {
+		m.index[entry.Document] = i
+	}
+}
+
+// remaining returns the files that are pending or failed; files dropped by redaction or skipped as
+// near-duplicates are not retried
+func (m *IngestManifest) remaining() []ingestFile {
+	var files []ingestFile
+	for _, entry := range m.Files {
+		if entry.Status != ingestStatusIngested && entry.Status != ingestStatusDropped {
+			files = append(files, ingestFile{Path: entry.Path, Name: entry.Document, Size: entry.Size})
+		}
+	}
+	return files
+}
+
+// counts returns the number of files per status
+func (m *IngestManifest) counts() map[string]int {
+	counts := map[string]int{ingestStatusPending: 0, ingestStatusIngested: 0, ingestStatusFailed: 0, ingestStatusDropped: 0}
+	for _, entry := range m.Files {
+		counts[entry.Status]++
+	}
+	return counts
+}
+
+// record stores the outcome for a document and periodically saves the manifest
+func (m *IngestManifest) record(document, hash string, ingestErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, ok := m.index[document]
+	if !ok {
+		return fmt.Errorf("document '%s' is not part of ingestion run '%s'", document, m.ID)
+	}
+	if hash != "" {
+		m.Files[i].Hash = hash
+	}
+	switch {
+	case isRedactionDrop(ingestErr) || isDuplicateSkip(ingestErr):
+		m.Files[i].Status = ingestStatusDropped
+		m.Files[i].Error = ingestErr.Error()
+	case ingestErr != nil:
+		m.Files[i].Status = ingestStatusFailed
+		m.Files[i].Error = ingestErr.Error()
+	default:
+		m.Files[i].Status = ingestStatusIngested
+		m.Files[i].Error = ""
+	}
+
+	if time.Since(m.lastSave) < ingestManifestSaveInterval {
+		return nil
+	}
+	return m.saveLocked()
+}
+
+// Save writes the manifest to disk
+func (m *IngestManifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveLocked()
+}
+
+// saveLocked writes the manifest through a temporary file so a crash never leaves it truncated
+func (m *IngestManifest) saveLocked() error {
+	m.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(m.path), err)
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest '%s': %w", m.path, err)
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		return fmt.Errorf("failed to write manifest '%s': %w", m.path, err)
+	}
+	m.lastSave = time.Now()
+	return nil
+}
+
+// Ingest run inspection commands
+var ingestCmd = &cobra.Command{
+	Use:   "ingest",
+	Short: "Inspect document ingestion runs",
+	Long: `Inspect the manifests that 'maestro document ingest' keeps under .maestro/ingest.
+
+Every ingestion run records each file's path, content hash, document name, status and error.
+Interrupted or partially failed runs can be continued with 'maestro document ingest --resume RUN_ID'.`,
+	Example: `  maestro ingest runs list
+  maestro ingest runs show 20240102-150405-a1b2`,
+}
+
+var ingestRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Manage ingestion runs",
+	Long:  `List and show recorded document ingestion runs.`,
+	Example: `  maestro ingest runs list
+  maestro ingest runs show 20240102-150405-a1b2 --failed`,
+}
+
+var ingestRunsListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List ingestion runs",
+	Long:    `List recorded ingestion runs, newest first, with per-status file counts.`,
+	Example: `  maestro ingest runs list`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return listIngestRuns()
+	},
+}
+
+var ingestRunsShowCmd = &cobra.Command{
+	Use:   "show RUN_ID",
+	Short: "Show an ingestion run",
+	Long:  `Show the target, file counts and per-file status of an ingestion run.`,
+	Example: `  maestro ingest runs show 20240102-150405-a1b2
+  maestro ingest runs show 20240102-150405-a1b2 --failed
+  maestro ingest runs show 20240102-150405-a1b2 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return showIngestRun(args[0])
+	},
+}
+
+// Flags for ingest runs
+var (
+	ingestRunsOutput     string
+	ingestRunsFailedOnly bool
+)
+
+func init() {
+	ingestRunsListCmd.Flags().StringVarP(&ingestRunsOutput, "output", "o", "text", "Output format (text, json)")
+	ingestRunsShowCmd.Flags().StringVarP(&ingestRunsOutput, "output", "o", "text", "Output format (text, json)")
+	ingestRunsShowCmd.Flags().BoolVar(&ingestRunsFailedOnly, "failed", false, "Only show files that failed")
+}
+
+// ingestRunSummary is the list view of a run
+type ingestRunSummary struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Database   string    `json:"database"`
+	Collection string    `json:"collection"`
+	Total      int       `json:"total"`
+	Ingested   int       `json:"ingested"`
+	Failed     int       `json:"failed"`
+	Pending    int       `json:"pending"`
+	Dropped    int       `json:"dropped,omitempty"`
+}
+
+func summarizeIngestRun(m *IngestManifest) ingestRunSummary {
+	counts := m.counts()
+	return ingestRunSummary{
+		ID:         m.ID,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+		Database:   m.Database,
+		Collection: m.Collection,
+		Total:      len(m.Files),
+		Ingested:   counts[ingestStatusIngested],
+		Failed:     counts[ingestStatusFailed],
+		Pending:    counts[ingestStatusPending],
+		Dropped:    counts[ingestStatusDropped],
+	}
+}
+
+func listIngestRuns() error {
+	if ingestRunsOutput != "text" && ingestRunsOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", ingestRunsOutput)
+	}
+
+	manifests, err := listIngestManifests()
+	if err != nil {
+		return err
+	}
+
+	summaries := []ingestRunSummary{}
+	for _, manifest := range manifests {
+		summaries = append(summaries, summarizeIngestRun(manifest))
+	}
+
+	if ingestRunsOutput == "json" {
+		return printJSON(summaries)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Printf("No ingestion runs found in %s\n", ingestManifestDir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN ID\tSTARTED\tTARGET\tTOTAL\tINGESTED\tFAILED\tPENDING")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s/%s\t%d\t%d\t%d\t%d\n", s.ID, s.CreatedAt.Local().Format("2006-01-02 15:04:05"), s.Database, s.Collection, s.Total, s.Ingested, s.Failed, s.Pending)
+	}
+	return w.Flush()
+}
+
+func showIngestRun(id string) error {
+	if ingestRunsOutput != "text" && ingestRunsOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text or json)", ingestRunsOutput)
+	}
+
+	manifest, err := loadIngestManifest(id)
+	if err != nil {
+		return err
+	}
+
+	files := manifest.Files
+	if ingestRunsFailedOnly {
+		files = nil
+		for _, entry := range manifest.Files {
+			if entry.Status == ingestStatusFailed {
+				files = append(files, entry)
+			}
+		}
+	}
+
+	if ingestRunsOutput == "json" {
+		if files == nil {
+			files = []IngestManifestEntry{}
+		}
+		return printJSON(&IngestManifest{
+			Version:    manifest.Version,
+			ID:         manifest.ID,
+			CreatedAt:  manifest.CreatedAt,
+			UpdatedAt:  manifest.UpdatedAt,
+			Database:   manifest.Database,
+			Collection: manifest.Collection,
+			Paths:      manifest.Paths,
+			Include:    manifest.Include,
+			Exclude:    manifest.Exclude,
+			Files:      files,
+		})
+	}
+
+	s := summarizeIngestRun(manifest)
+	fmt.Printf("Run:        %s\n", s.ID)
+	fmt.Printf("Started:    %s\n", s.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Updated:    %s\n", s.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Target:     collection '%s' in vector database '%s'\n", s.Collection, s.Database)
+	fmt.Printf("Paths:      %s\n", strings.Join(manifest.Paths, ", "))
+	if len(manifest.Include) > 0 {
+		fmt.Printf("Include:    %s\n", strings.Join(manifest.Include, ", "))
+	}
+	if len(manifest.Exclude) > 0 {
+		fmt.Printf("Exclude:    %s\n", strings.Join(manifest.Exclude, ", "))
+	}
+	fmt.Printf("Files:      %d total, %d ingested, %d failed, %d pending\n", s.Total, s.Ingested, s.Failed, s.Pending)
+
+	if len(files) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tDOCUMENT\tSHA256\tERROR")
+		for _, entry := range files {
+			hash := entry.Hash
+			if len(hash) > 12 {
+				hash = hash[:12]
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Status, entry.Document, hash, entry.Error)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if s.Failed+s.Pending > 0 {
+		fmt.Printf("\nResume with: maestro document ingest --resume %s\n", s.ID)
+	}
+	return nil
+}
+
+// printJSON writes a value as indented JSON to stdout
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
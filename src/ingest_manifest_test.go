@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIngestManifestRoundTrip(t *testing.T) {
+	ingestManifestDir = t.TempDir()
+	defer func() { ingestManifestDir = filepath.Join(".maestro", "ingest") }()
+
+	files := []ingestFile{{Path: "docs/a.md", Name: "a.md", Size: 3}, {Path: "docs/b.md", Name: "b.md", Size: 4}, {Path: "docs/c.md", Name: "c.md", Size: 5}}
+	manifest, err := newIngestManifest("vdb", "coll", []string{"docs"}, files)
+	if err != nil {
+		t.Fatalf("newIngestManifest() returned error: %v", err)
+	}
+
+	if err := manifest.record("a.md", "abc", nil); err != nil {
+		t.Fatalf("record() returned error: %v", err)
+	}
+	if err := manifest.record("b.md", "def", errors.New("server error")); err != nil {
+		t.Fatalf("record() returned error: %v", err)
+	}
+	if err := manifest.record("missing.md", "", nil); err == nil {
+		t.Error("record() should fail for a document outside the run")
+	}
+	if err := manifest.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ingestManifestDir, manifest.ID+".json")); err != nil {
+		t.Fatalf("manifest file was not written: %v", err)
+	}
+
+	loaded, err := loadIngestManifest(manifest.ID)
+	if err != nil {
+		t.Fatalf("loadIngestManifest() returned error: %v", err)
+	}
+	if loaded.Database != "vdb" || loaded.Collection != "coll" || len(loaded.Files) != 3 {
+		t.Errorf("loadIngestManifest() = %+v", loaded)
+	}
+	if b := loaded.Files[1]; b.Status != ingestStatusFailed || b.Error != "server error" || b.Hash != "def" {
+		t.Errorf("failed entry = %+v", b)
+	}
+
+	remaining := loaded.remaining()
+	if len(remaining) != 2 || remaining[0].Name != "b.md" || remaining[1].Name != "c.md" {
+		t.Errorf("remaining() = %+v, expected b.md and c.md", remaining)
+	}
+	counts := loaded.counts()
+	if counts[ingestStatusIngested] != 1 || counts[ingestStatusFailed] != 1 || counts[ingestStatusPending] != 1 {
+		t.Errorf("counts() = %v", counts)
+	}
+
+	runs, err := listIngestManifests()
+	if err != nil || len(runs) != 1 || runs[0].ID != manifest.ID {
+		t.Errorf("listIngestManifests() = %v, %v", runs, err)
+	}
+}
+
+func TestLoadIngestManifestErrors(t *testing.T) {
+	ingestManifestDir = t.TempDir()
+	defer func() { ingestManifestDir = filepath.Join(".maestro", "ingest") }()
+
+	if _, err := loadIngestManifest("../escape"); err == nil {
+		t.Error("loadIngestManifest() should reject run IDs containing path separators")
+	}
+	if _, err := loadIngestManifest("unknown"); err == nil {
+		t.Error("loadIngestManifest() should fail for an unknown run")
+	}
+}
+
+func TestIngestManifestDroppedFiles(t *testing.T) {
+	ingestManifestDir = t.TempDir()
+	defer func() { ingestManifestDir = filepath.Join(".maestro", "ingest") }()
+
+	files := []ingestFile{{Path: "wiki/a.md", Name: "a.md"}, {Path: "wiki/b.md", Name: "b.md"}}
+	manifest, err := newIngestManifest("vdb", "coll", []string{"wiki"}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropErr := &RedactionDropError{Document: "a.md", Findings: map[string]int{"aws_access_key": 1}}
+	if err := manifest.record("a.md", "abc", dropErr); err != nil {
+		t.Fatal(err)
+	}
+
+	if a := manifest.Files[0]; a.Status != ingestStatusDropped || a.Error != "dropped by redaction: aws_access_key 1" {
+		t.Errorf("dropped entry = %+v", a)
+	}
+	if remaining := manifest.remaining(); len(remaining) != 1 || remaining[0].Name != "b.md" {
+		t.Errorf("remaining() = %+v, expected only b.md", remaining)
+	}
+	if summary := summarizeIngestRun(manifest); summary.Dropped != 1 || summary.Pending != 1 {
+		t.Errorf("summarizeIngestRun() = %+v", summary)
+	}
+}
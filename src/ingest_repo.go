@@ -0,0 +1,477 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Document metadata keys recorded by document ingest-repo
+const (
+	repoMetadataKey         = "repo"
+	repoPathMetadataKey     = "repo_path"
+	commitMetadataKey       = "commit"
+	lastCommitMetadataKey   = "last_commit"
+	lastAuthorMetadataKey   = "last_author"
+	lastModifiedMetadataKey = "last_modified"
+)
+
+// vendoredDirectories hold third-party code; files under them are skipped unless --vendored is set
+var vendoredDirectories = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"third_party":      true,
+	"bower_components": true,
+	"jspm_packages":    true,
+	"site-packages":    true,
+	"Pods":             true,
+	"Carthage":         true,
+}
+
+// binarySniffSize is the number of leading bytes searched for a NUL byte, as git does
+const binarySniffSize = 8000
+
+// Flags for document ingest-repo
+var (
+	repoInclude     []string
+	repoExclude     []string
+	repoHidden      bool
+	repoVendored    bool
+	repoPrune       bool
+	repoConcurrency int
+)
+
+var documentIngestRepoCmd = &cobra.Command{
+	Use:   "ingest-repo PATH",
+	Short: "Ingest the tracked files of a git checkout",
+	Long: `Write the files tracked by a local git repository to a collection, one document per file.
+
+The repository is read directly from its .git directory; git does not need to be installed. Only
+files in the index are considered, limited to PATH when it is a subdirectory of the checkout. Files
+excluded by .gitignore, .git/info/exclude or .maestroignore (same syntax, higher precedence) are
+skipped, as are binary files, symlinks, submodules, hidden files unless --hidden is set, and files
+under vendored directories (vendor, node_modules, third_party, ...) unless --vendored is set.
+--include and --exclude filter files by glob as for 'document ingest'.
+
+Documents are named by their path in the repository and record the repository (its origin URL, or
+the checkout path without one), the path, the HEAD commit and the last commit, author and date that
+changed the file on the first-parent history.
+
+Running the command again only re-writes files whose content differs from the commit recorded with
+their document, including uncommitted changes. Documents of the repository whose files are no longer
+tracked are only deleted with --prune. With --dry-run the changes are listed without being applied.`,
+	Example: `  maestro document ingest-repo . --vdb=my-vdb --collection=code
+  maestro document ingest-repo ~/src/service --vdb=my-vdb --collection=code --include='*.go' --include='*.md'
+  maestro document ingest-repo ~/src/service --vdb=my-vdb --collection=code --prune --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return ingestRepository(vdbName, collectionName, args[0])
+	},
+}
+
+func init() {
+	documentIngestRepoCmd.Flags().String("vdb", "", "Vector database name")
+	documentIngestRepoCmd.Flags().String("collection", "", "Collection name")
+	documentIngestRepoCmd.Flags().StringSliceVar(&repoInclude, "include", nil, "Only ingest files matching these globs (repeatable)")
+	documentIngestRepoCmd.Flags().StringSliceVar(&repoExclude, "exclude", nil, "Skip files matching these globs (repeatable)")
+	documentIngestRepoCmd.Flags().BoolVar(&repoHidden, "hidden", false, "Include hidden files and directories")
+	documentIngestRepoCmd.Flags().BoolVar(&repoVendored, "vendored", false, "Include files under vendored directories")
+	documentIngestRepoCmd.Flags().BoolVar(&repoPrune, "prune", false, "Delete documents of the repository whose files are no longer tracked")
+	documentIngestRepoCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send file contents as-is instead of extracting their text")
+	addMetadataFlags(documentIngestRepoCmd)
+	addMaxSizeFlag(documentIngestRepoCmd)
+	addRedactFlags(documentIngestRepoCmd)
+	documentIngestRepoCmd.Flags().IntVar(&repoConcurrency, "concurrency", defaultConcurrency, "Number of files to ingest in parallel")
+}
+
+// repoFile is a tracked file selected for ingestion with its current git blob and content hash
+type repoFile struct {
+	File ingestFile
+	Blob string
+	Hash string
+}
+
+// repoSnapshot is the state of a checkout to ingest
+type repoSnapshot struct {
+	Repo     *gitRepository
+	Identity string
+	Head     *gitCommit
+	Scope    string // slash-separated directory PATH selects, "" for the whole checkout
+	Files    map[string]repoFile
+	Skipped  map[string]int // reason → number of tracked files
+}
+
+func ingestRepository(vdbName, collectionName, dir string) error {
+	for _, pattern := range append(append([]string{}, repoInclude...), repoExclude...) {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+
+	repo, err := openGitRepository(dir)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+	snapshot, err := readRepoSnapshot(repo, dir)
+	if err != nil {
+		return err
+	}
+	if len(snapshot.Files) == 0 {
+		return fmt.Errorf("no tracked files to ingest under '%s'", dir)
+	}
+
+	if verbose {
+		fmt.Printf("Ingesting %d tracked file(s) of '%s' at commit %s into collection '%s' of vector database '%s'...\n",
+			len(snapshot.Files), snapshot.Identity, shortSHA(snapshot.Head.SHA), collectionName, vdbName)
+		for _, reason := range sortedKeys(snapshot.Skipped) {
+			fmt.Printf("  skipped %d %s file(s)\n", snapshot.Skipped[reason], reason)
+		}
+	}
+
+	if dryRun {
+		// Computing the changes only reads from the server; they are not listed when it is unavailable
+		if err := reportRepoPlan(vdbName, collectionName, snapshot); err != nil {
+			if !silent {
+				fmt.Printf("[DRY RUN] Would ingest %d tracked file(s) of '%s' at commit %s into collection '%s' of vector database '%s'\n",
+					len(snapshot.Files), snapshot.Identity, shortSHA(snapshot.Head.SHA), collectionName, vdbName)
+			}
+			fmt.Fprintf(os.Stderr, "Change list unavailable: %v\n", err)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plan, err := computeRepoPlan(client, serverURI, vdbName, collectionName, snapshot)
+	if err != nil {
+		return err
+	}
+	if !silent {
+		printSyncPlan(plan)
+	}
+	if len(plan.Changes) == 0 {
+		if !silent {
+			fmt.Printf("✅ Collection '%s' is up to date with '%s' at commit %s (%d file(s))\n", collectionName, snapshot.Identity, shortSHA(snapshot.Head.SHA), plan.Unchanged)
+		}
+		return nil
+	}
+
+	if deletions := plan.count(syncDelete); deletions > 0 {
+		if err := confirmDestructiveOperation("delete", fmt.Sprintf("%d document(s) from collection '%s'", deletions, collectionName)); err != nil {
+			return err
+		}
+	}
+	if err := attachCommitMetadata(snapshot, plan); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	failures, transferred := applySyncPlan(client, serverURI, vdbName, collectionName, plan, repoConcurrency)
+	if !silent {
+		printSyncSummary(plan, failures, transferred, time.Since(start))
+		printRedactionReport(redactionReport())
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d change(s) failed; rerun ingest-repo to retry them", len(failures), len(plan.Changes))
+	}
+	return nil
+}
+
+// readRepoSnapshot selects the tracked files under dir and reads their content
+func readRepoSnapshot(repo *gitRepository, dir string) (*repoSnapshot, error) {
+	headSHA, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.ReadCommit(headSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	entries, err := repo.ReadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := filepath.Rel(repo.Root, abs)
+	if err != nil {
+		return nil, err
+	}
+	scope = filepath.ToSlash(scope)
+	if scope == "." {
+		scope = ""
+	}
+
+	snapshot := &repoSnapshot{Repo: repo, Identity: repo.Identity(), Head: head, Scope: scope, Files: map[string]repoFile{}, Skipped: map[string]int{}}
+
+	dirs := map[string]bool{"": true}
+	for _, entry := range entries {
+		for d := path.Dir(entry.Path); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	ignores := loadIgnoreMatcher(repo, sortedKeys(dirs))
+
+	for _, entry := range entries {
+		name := entry.Path
+		if scope != "" && !strings.HasPrefix(name, scope+"/") {
+			continue
+		}
+		reason := ""
+		switch {
+		case entry.Mode != gitModeFile && entry.Mode != gitModeExecutable:
+			reason = "symlink or submodule"
+		case !repoHidden && isHiddenPath(name):
+			reason = "hidden"
+		case !repoVendored && isVendoredPath(name):
+			reason = "vendored"
+		case ignores.Ignored(name):
+			reason = "ignored"
+		case !matchesFilters(name, repoInclude, repoExclude):
+			reason = "filtered"
+		}
+		if reason != "" {
+			snapshot.Skipped[reason]++
+			continue
+		}
+
+		filePath := filepath.Join(repo.Root, filepath.FromSlash(name))
+		content, err := os.ReadFile(filePath)
+		if os.IsNotExist(err) {
+			snapshot.Skipped["deleted"]++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+		}
+		if isBinaryContent(filePath, content) {
+			snapshot.Skipped["binary"]++
+			continue
+		}
+		snapshot.Files[name] = repoFile{
+			File: ingestFile{Path: filePath, Name: name, Size: int64(len(content))},
+			Blob: gitBlobHash(content),
+			Hash: contentHash(content),
+		}
+	}
+	return snapshot, nil
+}
+
+// isVendoredPath reports whether a slash-separated path is under a vendored directory
+func isVendoredPath(name string) bool {
+	segments := strings.Split(name, "/")
+	for _, segment := range segments[:len(segments)-1] {
+		if vendoredDirectories[segment] {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinaryContent reports whether a file has a NUL byte near its start and no extractor, like PDF,
+// that reads such files
+func isBinaryContent(filePath string, content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffSize {
+		sniff = sniff[:binarySniffSize]
+	}
+	if !bytes.Contains(sniff, []byte{0}) {
+		return false
+	}
+	return findExtractor(filePath, detectContentType(filePath, content)) == nil
+}
+
+// reportRepoPlan prints the changes ingest-repo would make without applying them
+func reportRepoPlan(vdbName, collectionName string, snapshot *repoSnapshot) error {
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plan, err := computeRepoPlan(client, serverURI, vdbName, collectionName, snapshot)
+	if err != nil {
+		return err
+	}
+	if !silent {
+		fmt.Printf("[DRY RUN] Would ingest '%s' at commit %s into collection '%s' of vector database '%s':\n",
+			snapshot.Identity, shortSHA(snapshot.Head.SHA), collectionName, vdbName)
+		printSyncPlan(plan)
+		fmt.Printf("[DRY RUN] %d to add, %d to update, %d to delete, %d unchanged\n",
+			plan.count(syncAdd), plan.count(syncUpdate), plan.count(syncDelete), plan.Unchanged)
+	}
+	return nil
+}
+
+// computeRepoPlan verifies the target and compares the snapshot with the collection's documents
+func computeRepoPlan(client *MCPClient, serverURI, vdbName, collectionName string, snapshot *repoSnapshot) (*syncPlan, error) {
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return nil, err
+	}
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]map[string]interface{})
+	for _, doc := range documentListEntries(entries) {
+		remote[doc.Name] = doc.Metadata
+	}
+
+	trees := make(map[string]map[string]string)
+	blobAt := func(commit, name string) string {
+		files, ok := trees[commit]
+		if !ok {
+			// A recorded commit missing from the repository, e.g. after a rebase, matches nothing
+			if c, err := snapshot.Repo.ReadCommit(commit); err == nil {
+				files, _ = snapshot.Repo.TreeFiles(c.Tree)
+			}
+			trees[commit] = files
+		}
+		return files[name]
+	}
+	return planRepoSync(snapshot, remote, blobAt, repoPrune), nil
+}
+
+// planRepoSync compares the snapshot with the metadata of the collection's documents. A document is
+// unchanged when its file has the blob recorded at the document's commit, or the content hash
+// stored with the document. Only documents of the same repository within the scope may be pruned.
+func planRepoSync(snapshot *repoSnapshot, remote map[string]map[string]interface{}, blobAt func(commit, name string) string, prune bool) *syncPlan {
+	plan := &syncPlan{}
+	for _, name := range sortedKeys(snapshot.Files) {
+		file := snapshot.Files[name]
+		metadata, ok := remote[name]
+		if !ok {
+			plan.Changes = append(plan.Changes, syncChange{Kind: syncAdd, File: file.File})
+			continue
+		}
+		repo, _ := metadata[repoMetadataKey].(string)
+		commit, _ := metadata[commitMetadataKey].(string)
+		hash, _ := metadata[contentHashMetadataKey].(string)
+		if repo == snapshot.Identity && ((commit != "" && blobAt(commit, name) == file.Blob) || hash == file.Hash) {
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, syncChange{Kind: syncUpdate, File: file.File})
+	}
+
+	for _, name := range sortedKeys(remote) {
+		if _, ok := snapshot.Files[name]; ok {
+			continue
+		}
+		if repo, _ := remote[name][repoMetadataKey].(string); repo != snapshot.Identity {
+			continue
+		}
+		if snapshot.Scope != "" && !strings.HasPrefix(name, snapshot.Scope+"/") {
+			continue
+		}
+		if prune {
+			plan.Changes = append(plan.Changes, syncChange{Kind: syncDelete, File: ingestFile{Name: name}})
+		} else {
+			plan.Extraneous = append(plan.Extraneous, name)
+		}
+	}
+	return plan
+}
+
+// attachCommitMetadata records the repository, path, HEAD commit and the last commit of each file
+// to write
+func attachCommitMetadata(snapshot *repoSnapshot, plan *syncPlan) error {
+	headFiles, err := snapshot.Repo.TreeFiles(snapshot.Head.Tree)
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD tree: %w", err)
+	}
+	var committed []string
+	for _, change := range plan.Changes {
+		if _, ok := headFiles[change.File.Name]; ok && change.Kind != syncDelete {
+			committed = append(committed, change.File.Name)
+		}
+	}
+	sort.Strings(committed)
+
+	var progress *ProgressIndicator
+	if ShouldShowProgress() && len(committed) > 0 {
+		progress = NewProgressIndicator(fmt.Sprintf("Reading the history of %d file(s)...", len(committed)))
+		progress.Start()
+	}
+	last, err := snapshot.Repo.LastCommits(snapshot.Head.SHA, committed)
+	if err != nil {
+		if progress != nil {
+			progress.StopWithError("Failed to read commit history")
+		}
+		return fmt.Errorf("failed to read commit history: %w", err)
+	}
+	if progress != nil {
+		progress.Stop(fmt.Sprintf("Read the history of %d file(s)", len(committed)))
+	}
+
+	for i, change := range plan.Changes {
+		if change.Kind == syncDelete {
+			continue
+		}
+		metadata := map[string]interface{}{
+			repoMetadataKey:     snapshot.Identity,
+			repoPathMetadataKey: change.File.Name,
+			commitMetadataKey:   snapshot.Head.SHA,
+		}
+		if commit, ok := last[change.File.Name]; ok {
+			metadata[lastCommitMetadataKey] = commit.SHA
+			metadata[lastAuthorMetadataKey] = commit.Author
+			metadata[lastModifiedMetadataKey] = commit.AuthorDate.Format(time.RFC3339)
+		}
+		plan.Changes[i].File.Metadata = metadata
+	}
+	return nil
+}
+
+// shortSHA abbreviates a commit name for display
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := &ignoreMatcher{}
+	m.add("", "# build output\nbuild/\n*.log\n!keep.log\n/root-only.txt\ndocs/**/draft-*.md\n[!a]*.tmp\n")
+	m.add("pkg", "generated.go\n")
+
+	tests := map[string]bool{
+		"build/out.txt":           true,
+		"src/build/out.txt":       true,
+		"build.txt":               false,
+		"server.log":              true,
+		"logs/keep.log":           false,
+		"root-only.txt":           true,
+		"sub/root-only.txt":       false,
+		"docs/a/b/draft-intro.md": true,
+		"docs/intro.md":           false,
+		"b.tmp":                   true,
+		"a.tmp":                   false,
+		"pkg/generated.go":        true,
+		"pkg/sub/generated.go":    true,
+		"generated.go":            false,
+	}
+	for name, expected := range tests {
+		if got := m.Ignored(name); got != expected {
+			t.Errorf("Ignored(%s) = %v, expected %v", name, got, expected)
+		}
+	}
+
+	// A file cannot be re-included when its directory is excluded
+	m.add("", "!build/keep.txt\n")
+	if !m.Ignored("build/keep.txt") {
+		t.Error("build/keep.txt should stay ignored")
+	}
+}
+
+func TestReadRepoSnapshot(t *testing.T) {
+	dir := newTestGitRepo(t)
+	writeTestFile(t, dir, ".gitignore", "*.log\n")
+	writeTestFile(t, dir, ".maestroignore", "docs/\n")
+	writeTestFile(t, dir, "vendor/lib/lib.go", "package lib\n")
+	writeTestFile(t, dir, "logo.png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	writeTestFile(t, dir, "debug.log", "forced\n")
+	writeTestFile(t, dir, "untracked.md", "not added\n")
+	runGit(t, dir, "add", ".gitignore", ".maestroignore", "vendor", "logo.png")
+	runGit(t, dir, "add", "-f", "debug.log")
+	writeTestFile(t, dir, "src/main.go", "package main\n\nfunc main() {}\n")
+
+	repo, err := openGitRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	snapshot, err := readRepoSnapshot(repo, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := sortedKeys(snapshot.Files); !reflect.DeepEqual(names, []string{"README.md", "src/main.go", "src/util.go"}) {
+		t.Errorf("files = %v", names)
+	}
+	expected := map[string]int{"hidden": 2, "ignored": 2, "vendored": 1, "binary": 1}
+	if !reflect.DeepEqual(snapshot.Skipped, expected) {
+		t.Errorf("skipped = %v, expected %v", snapshot.Skipped, expected)
+	}
+	if main := snapshot.Files["src/main.go"]; main.Blob != gitBlobHash([]byte("package main\n\nfunc main() {}\n")) {
+		t.Errorf("src/main.go blob = %s; the working tree content should be used", main.Blob)
+	}
+
+	scoped, err := readRepoSnapshot(repo, dir+"/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := sortedKeys(scoped.Files); scoped.Scope != "src" || !reflect.DeepEqual(names, []string{"src/main.go", "src/util.go"}) {
+		t.Errorf("scoped snapshot = %s %v", scoped.Scope, names)
+	}
+}
+
+func TestPlanRepoSync(t *testing.T) {
+	snapshot := &repoSnapshot{
+		Identity: "https://example.org/project.git",
+		Scope:    "src",
+		Files: map[string]repoFile{
+			"src/new.go":       {File: ingestFile{Name: "src/new.go"}, Blob: "b1", Hash: "h1"},
+			"src/same.go":      {File: ingestFile{Name: "src/same.go"}, Blob: "b2", Hash: "h2"},
+			"src/changed.go":   {File: ingestFile{Name: "src/changed.go"}, Blob: "b3", Hash: "h3"},
+			"src/uncommit.go":  {File: ingestFile{Name: "src/uncommit.go"}, Blob: "b4", Hash: "h4"},
+			"src/other.go":     {File: ingestFile{Name: "src/other.go"}, Blob: "b5", Hash: "h5"},
+			"src/rewritten.go": {File: ingestFile{Name: "src/rewritten.go"}, Blob: "b6", Hash: "h6"},
+		},
+	}
+	doc := func(repo, commit, hash string) map[string]interface{} {
+		return map[string]interface{}{repoMetadataKey: repo, commitMetadataKey: commit, contentHashMetadataKey: hash}
+	}
+	remote := map[string]map[string]interface{}{
+		"src/same.go":      doc(snapshot.Identity, "c1", "old"),
+		"src/changed.go":   doc(snapshot.Identity, "c1", "old"),
+		"src/uncommit.go":  doc(snapshot.Identity, "gone", "h4"),
+		"src/other.go":     doc("https://example.org/fork.git", "c1", "h5"),
+		"src/rewritten.go": doc(snapshot.Identity, "gone", "old"),
+		"src/removed.go":   doc(snapshot.Identity, "c1", "x"),
+		"docs/removed.md":  doc(snapshot.Identity, "c1", "x"),
+		"src/foreign.go":   doc("https://example.org/fork.git", "c1", "x"),
+	}
+	blobs := map[string]string{"c1 src/same.go": "b2", "c1 src/changed.go": "old-blob"}
+	blobAt := func(commit, name string) string { return blobs[commit+" "+name] }
+
+	plan := planRepoSync(snapshot, remote, blobAt, true)
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.Kind+" "+change.File.Name)
+	}
+	expected := []string{"update src/changed.go", "add src/new.go", "update src/other.go", "update src/rewritten.go", "delete src/removed.go"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("changes = %v, expected %v", changes, expected)
+	}
+	if plan.Unchanged != 2 {
+		t.Errorf("unchanged = %d, expected 2", plan.Unchanged)
+	}
+
+	if plan := planRepoSync(snapshot, remote, blobAt, false); !reflect.DeepEqual(plan.Extraneous, []string{"src/removed.go"}) {
+		t.Errorf("extraneous = %v", plan.Extraneous)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		match   bool
+	}{
+		{"*.md", "guide.md", true},
+		{"*.md", "docs/guide.md", true},
+		{"*.md", "docs/guide.txt", false},
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/api/guide.md", false},
+		{"docs/**/*.md", "docs/guide.md", true},
+		{"docs/**/*.md", "docs/api/v1/guide.md", true},
+		{"drafts/**", "drafts/a/b.md", true},
+		{"drafts/**", "published/b.md", false},
+		{"./drafts/*", "drafts/b.md", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.rel); got != tt.match {
+			t.Errorf("matchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.rel, got, tt.match)
+		}
+	}
+}
+
+func TestCollectIngestFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.md", "b.txt", "sub/c.md", "drafts/d.md", ".hidden/e.md", ".f.md"} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := collectIngestFiles([]string{dir}, []string{"*.md"}, []string{"drafts/**"}, false)
+	if err != nil {
+		t.Fatalf("collectIngestFiles() returned error: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if strings.Join(names, ",") != "a.md,sub/c.md" {
+		t.Errorf("collectIngestFiles() names = %v, expected [a.md sub/c.md]", names)
+	}
+
+	files, err = collectIngestFiles([]string{dir}, nil, nil, true)
+	if err != nil {
+		t.Fatalf("collectIngestFiles() returned error: %v", err)
+	}
+	if len(files) != 6 {
+		t.Errorf("collectIngestFiles() with hidden files returned %d files, expected 6", len(files))
+	}
+
+	// A file argument is named after its base name and can collide with a directory entry
+	_, err = collectIngestFiles([]string{dir, filepath.Join(dir, "sub", "c.md"), filepath.Join(dir, "a.md")}, nil, nil, false)
+	if err == nil || !strings.Contains(err.Error(), "would both be ingested as document 'a.md'") {
+		t.Errorf("collectIngestFiles() = %v, expected duplicate name error", err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{512: "512 B", 2048: "2.0 KiB", 5 * 1024 * 1024: "5.0 MiB"}
+	for n, expected := range tests {
+		if got := formatBytes(n); got != expected {
+			t.Errorf("formatBytes(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// maxHistoryEntries is the number of history lines kept in memory and offered for recall
+const maxHistoryEntries = 1000
+
+// errLineInterrupted is returned by ReadLine when the user presses Ctrl-C
+var errLineInterrupted = errors.New("interrupted")
+
+// lineReader reads input lines for interactive sessions
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+	AddHistory(line string)
+}
+
+// newLineReader returns a line editor with history when stdin is a terminal and a plain line
+// scanner otherwise, so that sessions can also be scripted through a pipe
+func newLineReader(history []string) lineReader {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) && term.IsTerminal(int(os.Stdout.Fd())) {
+		return &terminalLineReader{
+			fd:      fd,
+			in:      bufio.NewReader(os.Stdin),
+			out:     os.Stdout,
+			history: history,
+		}
+	}
+	return &plainLineReader{scanner: bufio.NewScanner(os.Stdin)}
+}
+
+// plainLineReader reads lines without echo or editing
+type plainLineReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainLineReader) ReadLine(prompt string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+func (r *plainLineReader) AddHistory(line string) {}
+
+// terminalLineReader edits lines in raw terminal mode with cursor movement and history recall
+type terminalLineReader struct {
+	fd      int
+	in      *bufio.Reader
+	out     io.Writer
+	history []string
+}
+
+func (r *terminalLineReader) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to enable terminal line editing: %w", err)
+	}
+	defer term.Restore(r.fd, state)
+	return editLine(r.in, r.out, prompt, r.history)
+}
+
+func (r *terminalLineReader) AddHistory(line string) {
+	r.history = appendHistoryEntry(r.history, line)
+}
+
+// lineEditor is the state of a line being edited
+type lineEditor struct {
+	out     io.Writer
+	prompt  string
+	line    []rune
+	pos     int
+	history []string
+	index   int    // history entry shown, len(history) for the line being typed
+	draft   []rune // the line being typed while browsing history
+}
+
+// editLine reads one line from in, echoing and editing it on out with the usual readline keys:
+// arrows, Home/End, Delete, Ctrl-A/E/B/F/K/U/W/L and Ctrl-P/N or Up/Down for history. The
+// terminal must already be in raw mode. Ctrl-C returns errLineInterrupted and Ctrl-D on an
+// empty line returns io.EOF.
+func editLine(in *bufio.Reader, out io.Writer, prompt string, history []string) (string, error) {
+	e := &lineEditor{out: out, prompt: prompt, history: history, index: len(history)}
+	e.refresh()
+	for {
+		key, _, err := in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(e.line) > 0 {
+				fmt.Fprint(out, "\r\n")
+				return string(e.line), nil
+			}
+			return "", err
+		}
+
+		switch key {
+		case '\r', '\n':
+			fmt.Fprint(out, "\r\n")
+			return string(e.line), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(out, "^C\r\n")
+			return "", errLineInterrupted
+		case 4: // Ctrl-D
+			if len(e.line) == 0 {
+				fmt.Fprint(out, "\r\n")
+				return "", io.EOF
+			}
+			e.deleteForward()
+		case 127, 8: // Backspace
+			e.deleteBackward()
+		case 1: // Ctrl-A
+			e.moveTo(0)
+		case 5: // Ctrl-E
+			e.moveTo(len(e.line))
+		case 2: // Ctrl-B
+			e.moveTo(e.pos - 1)
+		case 6: // Ctrl-F
+			e.moveTo(e.pos + 1)
+		case 11: // Ctrl-K
+			e.line = e.line[:e.pos]
+		case 21: // Ctrl-U
+			e.line = append([]rune{}, e.line[e.pos:]...)
+			e.pos = 0
+		case 23: // Ctrl-W
+			e.deleteWordBackward()
+		case 12: // Ctrl-L
+			fmt.Fprint(out, "\x1b[H\x1b[2J")
+		case 16: // Ctrl-P
+			e.recall(-1)
+		case 14: // Ctrl-N
+			e.recall(1)
+		case 27: // Escape sequence
+			e.escape(in)
+		default:
+			if unicode.IsPrint(key) {
+				e.insert(key)
+			}
+		}
+		e.refresh()
+	}
+}
+
+// escape handles the CSI and SS3 sequences sent by arrow and navigation keys
+func (e *lineEditor) escape(in *bufio.Reader) {
+	intro, _, err := in.ReadRune()
+	if err != nil || (intro != '[' && intro != 'O') {
+		return
+	}
+	var param strings.Builder
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			return
+		}
+		if b >= 0x40 && b <= 0x7e {
+			e.navigate(b, param.String())
+			return
+		}
+		param.WriteByte(b)
+	}
+}
+
+func (e *lineEditor) navigate(final byte, param string) {
+	switch final {
+	case 'A':
+		e.recall(-1)
+	case 'B':
+		e.recall(1)
+	case 'C':
+		e.moveTo(e.pos + 1)
+	case 'D':
+		e.moveTo(e.pos - 1)
+	case 'H':
+		e.moveTo(0)
+	case 'F':
+		e.moveTo(len(e.line))
+	case '~':
+		switch param {
+		case "1", "7":
+			e.moveTo(0)
+		case "4", "8":
+			e.moveTo(len(e.line))
+		case "3":
+			e.deleteForward()
+		}
+	}
+}
+
+func (e *lineEditor) insert(key rune) {
+	e.line = append(e.line, 0)
+	copy(e.line[e.pos+1:], e.line[e.pos:])
+	e.line[e.pos] = key
+	e.pos++
+}
+
+func (e *lineEditor) deleteBackward() {
+	if e.pos > 0 {
+		e.line = append(e.line[:e.pos-1], e.line[e.pos:]...)
+		e.pos--
+	}
+}
+
+func (e *lineEditor) deleteForward() {
+	if e.pos < len(e.line) {
+		e.line = append(e.line[:e.pos], e.line[e.pos+1:]...)
+	}
+}
+
+func (e *lineEditor) deleteWordBackward() {
+	start := e.pos
+	for start > 0 && unicode.IsSpace(e.line[start-1]) {
+		start--
+	}
+	for start > 0 && !unicode.IsSpace(e.line[start-1]) {
+		start--
+	}
+	e.line = append(e.line[:start], e.line[e.pos:]...)
+	e.pos = start
+}
+
+func (e *lineEditor) moveTo(pos int) {
+	if pos >= 0 && pos <= len(e.line) {
+		e.pos = pos
+	}
+}
+
+// recall replaces the line with an older (delta -1) or newer (delta 1) history entry
+func (e *lineEditor) recall(delta int) {
+	index := e.index + delta
+	if index < 0 || index > len(e.history) {
+		return
+	}
+	if e.index == len(e.history) {
+		e.draft = append([]rune{}, e.line...)
+	}
+	e.index = index
+	if index == len(e.history) {
+		e.line = append([]rune{}, e.draft...)
+	} else {
+		e.line = []rune(e.history[index])
+	}
+	e.pos = len(e.line)
+}
+
+// refresh redraws the prompt and line and places the cursor
+func (e *lineEditor) refresh() {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.line))
+	if back := len(e.line) - e.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// appendHistoryEntry adds a line to the history, skipping blank lines and immediate repeats
+func appendHistoryEntry(history []string, line string) []string {
+	if strings.TrimSpace(line) == "" || (len(history) > 0 && history[len(history)-1] == line) {
+		return history
+	}
+	history = append(history, line)
+	if len(history) > maxHistoryEntries {
+		history = history[len(history)-maxHistoryEntries:]
+	}
+	return history
+}
+
+// defaultHistoryFile returns ~/.maestro/history
+func defaultHistoryFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".maestro", "history"), nil
+}
+
+// loadHistory reads the most recent entries of a history file; a missing file is an empty history
+func loadHistory(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+	var history []string
+	for _, line := range strings.Split(string(data), "\n") {
+		history = appendHistoryEntry(history, strings.TrimRight(line, "\r"))
+	}
+	return history, nil
+}
+
+// appendHistory appends one entry to a history file, creating it and its directory as needed
+func appendHistory(path, line string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history %s: %w", path, err)
+	}
+	return f.Close()
+}
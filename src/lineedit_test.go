@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditLine(t *testing.T) {
+	history := []string{"first query", "second query"}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "hello\r", "hello"},
+		{"backspace", "helxx\x7f\x7flo\r", "hello"},
+		{"left arrow insert", "hllo\x1b[D\x1b[D\x1b[De\r", "hello"},
+		{"home and end", "ello\x01h\x05!\r", "hello!"},
+		{"delete key", "hxello\x01\x1b[C\x1b[3~\r", "hello"},
+		{"kill to end", "hello world\x01\x06\x06\x06\x06\x06\x0b\r", "hello"},
+		{"kill to start", "junk hello\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x15\r", "hello"},
+		{"delete word", "hello big world\x17\x17world\r", "hello world"},
+		{"history up", "\x1b[A\r", "second query"},
+		{"history up twice", "\x1b[A\x1b[A\r", "first query"},
+		{"history stops at oldest", "\x10\x10\x10\r", "first query"},
+		{"history down restores draft", "dra\x1b[A\x1b[Bft\r", "draft"},
+		{"edit recalled entry", "\x1b[A\x17answer\r", "second answer"},
+		{"unicode", "héllo\x1b[D\x7fö\r", "hélöo"},
+		{"ss3 arrows", "ab\x1bOD-\r", "a-b"},
+		{"eof ends line", "partial", "partial"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			got, err := editLine(bufio.NewReader(strings.NewReader(tt.input)), &out, "> ", history)
+			if err != nil {
+				t.Fatalf("editLine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("editLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if !reflect.DeepEqual(history, []string{"first query", "second query"}) {
+		t.Errorf("editLine() modified history: %q", history)
+	}
+}
+
+func TestEditLineControl(t *testing.T) {
+	if _, err := editLine(bufio.NewReader(strings.NewReader("abc\x03")), io.Discard, "> ", nil); err != errLineInterrupted {
+		t.Errorf("Ctrl-C error = %v, want errLineInterrupted", err)
+	}
+	if _, err := editLine(bufio.NewReader(strings.NewReader("\x04")), io.Discard, "> ", nil); err != io.EOF {
+		t.Errorf("Ctrl-D on empty line error = %v, want io.EOF", err)
+	}
+	got, err := editLine(bufio.NewReader(strings.NewReader("abc\x01\x04\r")), io.Discard, "> ", nil)
+	if err != nil || got != "bc" {
+		t.Errorf("Ctrl-D within line = %q, %v, want \"bc\"", got, err)
+	}
+}
+
+func TestAppendHistoryEntry(t *testing.T) {
+	var history []string
+	for _, line := range []string{"a", "", "  ", "b", "b", "a"} {
+		history = appendHistoryEntry(history, line)
+	}
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(history, want) {
+		t.Errorf("history = %q, want %q", history, want)
+	}
+
+	history = nil
+	for i := 0; i < maxHistoryEntries+10; i++ {
+		history = appendHistoryEntry(history, strings.Repeat("x", i%7+1)+string(rune('a'+i%26)))
+	}
+	if len(history) != maxHistoryEntries {
+		t.Errorf("history length = %d, want %d", len(history), maxHistoryEntries)
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".maestro", "history")
+
+	history, err := loadHistory(path)
+	if err != nil || history != nil {
+		t.Fatalf("loadHistory() of missing file = %q, %v", history, err)
+	}
+	for _, line := range []string{"what is maestro?", ":limit 3", ":limit 3", "how do I ingest?"} {
+		if err := appendHistory(path, line); err != nil {
+			t.Fatalf("appendHistory() error = %v", err)
+		}
+	}
+	history, err = loadHistory(path)
+	if err != nil {
+		t.Fatalf("loadHistory() error = %v", err)
+	}
+	if want := []string{"what is maestro?", ":limit 3", "how do I ingest?"}; !reflect.DeepEqual(history, want) {
+		t.Errorf("loadHistory() = %q, want %q", history, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("history file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+}
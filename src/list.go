@@ -4,8 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"text/tabwriter"
 )
 
+// documentListOutput is the format of document list: the server's listing, a wide table or JSON
+var documentListOutput string
+
+// DocumentListEntry is a document as shown by document list -o wide and -o json
+type DocumentListEntry struct {
+	Name     string                 `json:"name"`
+	Chunks   int                    `json:"chunks"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 func listVectorDatabases() error {
 	if verbose {
 		fmt.Println("Listing vector databases...")
@@ -272,6 +283,10 @@ func showCollectionInfo(vdbName, collectionName string) error {
 }
 
 func listDocuments(vdbName, collectionName string) error {
+	if documentListOutput != "text" && documentListOutput != "wide" && documentListOutput != "json" {
+		return fmt.Errorf("unsupported output format '%s' (use text, wide or json)", documentListOutput)
+	}
+
 	if verbose {
 		fmt.Printf("Listing documents in collection '%s' for vector database '%s'...\n", collectionName, vdbName)
 	}
@@ -328,7 +343,16 @@ func listDocuments(vdbName, collectionName string) error {
 
 	// Display results
 	if !silent {
-		fmt.Println(documentsResult)
+		switch documentListOutput {
+		case "wide":
+			printDocumentListWide(documentListEntries(parseDocumentEntries(documentsResult)))
+		case "json":
+			if err := printJSON(documentListEntries(parseDocumentEntries(documentsResult))); err != nil {
+				return fmt.Errorf("failed to encode documents: %w", err)
+			}
+		default:
+			fmt.Println(documentsResult)
+		}
 	}
 
 	if verbose {
@@ -338,6 +362,35 @@ func listDocuments(vdbName, collectionName string) error {
 	return nil
 }
 
+// documentListEntries groups the entries of a listing by document, keeping the metadata of the first chunk
+func documentListEntries(entries []DocumentRecord) []DocumentListEntry {
+	documents := []DocumentListEntry{}
+	index := make(map[string]int)
+	for _, entry := range entries {
+		if i, ok := index[entry.Name]; ok {
+			documents[i].Chunks++
+			continue
+		}
+		index[entry.Name] = len(documents)
+		documents = append(documents, DocumentListEntry{Name: entry.Name, Chunks: 1, Metadata: entry.Metadata})
+	}
+	return documents
+}
+
+// printDocumentListWide prints one row per document with its chunk count and metadata
+func printDocumentListWide(documents []DocumentListEntry) {
+	if len(documents) == 0 {
+		fmt.Println("No documents found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHUNKS\tMETADATA")
+	for _, doc := range documents {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", doc.Name, doc.Chunks, formatMetadata(doc.Metadata, "doc_name"))
+	}
+	w.Flush()
+}
+
 // listChunkingStrategies calls the MCP tool to retrieve supported chunking strategies
 func listChunkingStrategies() error {
 	if verbose {
@@ -366,32 +419,23 @@ func listChunkingStrategies() error {
 	}
 	defer client.Close()
 
-	// Use the raw call to the tool (no db context required)
-	resp, err := client.callMCPServer("get_supported_chunking_strategies", nil)
+	result, err := client.SupportedChunkingStrategies()
 	if err != nil {
-		return fmt.Errorf("failed to call MCP tool: %w", err)
-	}
-	if resp.Error != nil {
-		return fmt.Errorf("MCP server error: %s", resp.Error.Message)
-	}
-	if resp.Result == nil {
-		return fmt.Errorf("no response from MCP server")
+		return fmt.Errorf("failed to list chunking strategies: %w", err)
 	}
 
 	// Support both plain string responses and parsed JSON objects
-	if resultStr, ok := resp.Result.(string); ok {
+	if resultStr, ok := result.(string); ok {
 		fmt.Println(resultStr)
 		return nil
 	}
 
-	// Attempt to pretty-print JSON-like results without HTML escaping
-	// Note: resp.Result may already be a map[string]interface{} or []interface{}
+	// Pretty-print JSON-like results without HTML escaping
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetEscapeHTML(false)
 	enc.SetIndent("", "  ")
-	if err := enc.Encode(resp.Result); err == nil {
-		return nil
+	if err := enc.Encode(result); err != nil {
+		return fmt.Errorf("unexpected response format from MCP server: %T", result)
 	}
-
-	return fmt.Errorf("unexpected response format from MCP server: %T", resp.Result)
+	return nil
 }
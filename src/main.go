@@ -131,10 +131,13 @@ A command-line interface for working with Maestro configurations.`,
 	rootCmd.AddCommand(documentCmd)
 	rootCmd.AddCommand(embeddingCmd)
 	rootCmd.AddCommand(chunkingCmd)
+	rootCmd.AddCommand(ingestCmd)
 	rootCmd.AddCommand(queryCmd)
+	rootCmd.AddCommand(chatCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(resyncCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(evalCmd)
 	rootCmd.AddCommand(statusCmd)
 
 	// Add completion command
@@ -144,18 +147,39 @@ A command-line interface for working with Maestro configurations.`,
 	vdbCmd.AddCommand(vdbListCmd)
 	vdbCmd.AddCommand(vdbCreateCmd)
 	vdbCmd.AddCommand(vdbDeleteCmd)
+	vdbCmd.AddCommand(vdbExportCmd)
+	vdbCmd.AddCommand(vdbImportCmd)
 
 	collectionCmd.AddCommand(collectionListCmd)
 	collectionCmd.AddCommand(collectionInfoCmd)
 	collectionCmd.AddCommand(collectionCreateCmd)
 	collectionCmd.AddCommand(collectionDeleteCmd)
+	collectionCmd.AddCommand(collectionMigrateCmd)
+	collectionCmd.AddCommand(collectionUpdateCmd)
+	collectionCmd.AddCommand(collectionStatsCmd)
+	collectionCmd.AddCommand(collectionDedupeCmd)
 
 	documentCmd.AddCommand(documentListCmd)
 	documentCmd.AddCommand(documentCreateCmd)
 	documentCmd.AddCommand(documentDeleteCmd)
+	documentCmd.AddCommand(documentIngestCmd)
+	documentCmd.AddCommand(documentIngestRepoCmd)
+	documentCmd.AddCommand(documentSyncCmd)
+	documentCmd.AddCommand(documentWatchCmd)
+	documentCmd.AddCommand(documentExtractCmd)
+	documentCmd.AddCommand(documentImportCmd)
+	documentCmd.AddCommand(documentGetCmd)
+	documentCmd.AddCommand(documentUpdateCmd)
+	documentCmd.AddCommand(documentHistoryCmd)
+
+	ingestCmd.AddCommand(ingestRunsCmd)
+	ingestRunsCmd.AddCommand(ingestRunsListCmd)
+	ingestRunsCmd.AddCommand(ingestRunsShowCmd)
 
 	embeddingCmd.AddCommand(embeddingListCmd)
 
+	evalCmd.AddCommand(evalRetrievalCmd)
+
 	agentCmd.AddCommand(commands.NewCreateCommand())
 	agentCmd.AddCommand(commands.NewAgentServeCommand())
 
@@ -167,6 +191,7 @@ A command-line interface for working with Maestro configurations.`,
 
 	// Chunking
 	chunkingCmd.AddCommand(chunkingListCmd)
+	chunkingCmd.AddCommand(chunkingPreviewCmd)
 
 	// Add contextual help to commands
 	addContextualHelp()
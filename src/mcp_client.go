@@ -1,15 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -19,6 +22,12 @@ type MCPClient struct {
 	baseURL string
 	ctx     context.Context
 	cancel  context.CancelFunc
+	// callTimeout bounds each tool call when set; otherwise calls share ctx's deadline
+	callTimeout time.Duration
+	initMu      sync.Mutex
+
+	toolsMu sync.Mutex
+	tools   map[string]json.RawMessage // input schemas from tools/list, fetched on first use
 }
 
 // MCPResponse represents the response from the MCP server
@@ -83,15 +92,29 @@ func getMCPServerURI(cmdServerURI string) (string, error) {
 	return normalizeURL(serverURI), nil
 }
 
-// NewMCPClient creates a new MCP client
-func NewMCPClient(serverURI string) (*MCPClient, error) {
-	// Create context with timeout - use shorter timeout for tests
-	timeout := 30 * time.Second
+// mcpTimeout returns the timeout used for MCP operations - shorter for tests
+func mcpTimeout() time.Duration {
 	if os.Getenv("MAESTRO_TEST_MODE") == "true" {
-		timeout = 5 * time.Second // Shorter timeout for tests
+		return 5 * time.Second // Shorter timeout for tests
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	return 30 * time.Second
+}
 
+// NewMCPClient creates a new MCP client
+func NewMCPClient(serverURI string) (*MCPClient, error) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), mcpTimeout())
+	return newMCPClient(serverURI, ctx, cancel, 0)
+}
+
+// NewMCPSessionClient creates an MCP client for long-running operations such as
+// bulk imports. The session itself has no deadline; each tool call is bounded instead.
+func NewMCPSessionClient(serverURI string) (*MCPClient, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return newMCPClient(serverURI, ctx, cancel, mcpTimeout())
+}
+
+func newMCPClient(serverURI string, ctx context.Context, cancel context.CancelFunc, callTimeout time.Duration) (*MCPClient, error) {
 	// Create MCP client using HTTP transport
 	// The mark3labs/mcp-go library supports HTTP transport for connecting to existing servers
 	mcpClient, err := client.NewStreamableHttpClient(serverURI)
@@ -112,41 +135,57 @@ func NewMCPClient(serverURI string) (*MCPClient, error) {
 	}
 
 	return &MCPClient{
-		client:  mcpClient,
-		baseURL: serverURI,
-		ctx:     ctx,
-		cancel:  cancel,
+		client:      mcpClient,
+		baseURL:     serverURI,
+		ctx:         ctx,
+		cancel:      cancel,
+		callTimeout: callTimeout,
 	}, nil
 }
 
-// callMCPServer makes a call to the MCP server using the mark3labs/mcp-go library
-func (c *MCPClient) callMCPServer(method string, params interface{}) (*MCPResponse, error) {
+// initialize initializes the client if not already initialized; concurrent callers wait for the first one
+func (c *MCPClient) initialize(ctx context.Context) error {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+	if c.client.IsInitialized() {
+		return nil
+	}
+	initRequest := mcp.InitializeRequest{
+		Request: mcp.Request{
+			Method: "initialize",
+		},
+		Params: mcp.InitializeParams{
+			ProtocolVersion: "2024-11-05",
+			Capabilities:    mcp.ClientCapabilities{},
+		},
+	}
 
-	// Initialize the client if not already initialized
-	if !c.client.IsInitialized() {
-		initRequest := mcp.InitializeRequest{
-			Request: mcp.Request{
-				Method: "initialize",
-			},
-			Params: mcp.InitializeParams{
-				ProtocolVersion: "2024-11-05",
-				Capabilities:    mcp.ClientCapabilities{},
-			},
+	if _, err := c.client.Initialize(ctx, initRequest); err != nil {
+		// Provide user-friendly error messages for common connection issues
+		errStr := err.Error()
+		if strings.Contains(errStr, "connection refused") ||
+			strings.Contains(errStr, "no such host") ||
+			strings.Contains(errStr, "timeout") ||
+			strings.Contains(errStr, "context deadline exceeded") ||
+			strings.Contains(errStr, "network is unreachable") {
+			return fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", c.baseURL)
 		}
+		return fmt.Errorf("failed to initialize MCP client: %w", err)
+	}
+	return nil
+}
 
-		_, err := c.client.Initialize(c.ctx, initRequest)
-		if err != nil {
-			// Provide user-friendly error messages for common connection issues
-			errStr := err.Error()
-			if strings.Contains(errStr, "connection refused") ||
-				strings.Contains(errStr, "no such host") ||
-				strings.Contains(errStr, "timeout") ||
-				strings.Contains(errStr, "context deadline exceeded") ||
-				strings.Contains(errStr, "network is unreachable") {
-				return nil, fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", c.baseURL)
-			}
-			return nil, fmt.Errorf("failed to initialize MCP client: %w", err)
-		}
+// callMCPServer makes a call to the MCP server using the mark3labs/mcp-go library
+func (c *MCPClient) callMCPServer(method string, params interface{}) (*MCPResponse, error) {
+	ctx := c.ctx
+	if c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(c.ctx, c.callTimeout)
+		defer cancel()
+	}
+
+	if err := c.initialize(ctx); err != nil {
+		return nil, err
 	}
 
 	// Create the tool call request
@@ -161,7 +200,7 @@ func (c *MCPClient) callMCPServer(method string, params interface{}) (*MCPRespon
 	}
 
 	// Call the tool
-	response, err := c.client.CallTool(c.ctx, request)
+	response, err := c.client.CallTool(ctx, request)
 	if err != nil {
 		// Provide user-friendly error messages for common connection issues
 		errStr := err.Error()
@@ -213,6 +252,23 @@ func (c *MCPClient) callMCPServer(method string, params interface{}) (*MCPRespon
 	return result, nil
 }
 
+// resultText returns the result as text, re-encoding structured JSON results
+// so that callers can parse string and object responses the same way
+func (r *MCPResponse) resultText() (string, bool) {
+	if resultStr, ok := r.Result.(string); ok {
+		return resultStr, true
+	}
+
+	// Keep <, > and & as they are, as the server sent them
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.Result); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(data.String(), "\n"), true
+}
+
 // ListDatabases calls the list_databases tool on the MCP server
 func (c *MCPClient) ListDatabases() ([]DatabaseInfo, error) {
 	response, err := c.callMCPServer("list_databases", nil)
@@ -403,7 +459,7 @@ func (c *MCPClient) ListCollections(dbName string) (string, error) {
 		return "", fmt.Errorf("no response from MCP server (check server at %s)", c.baseURL)
 	}
 
-	if resultStr, ok := response.Result.(string); ok {
+	if resultStr, ok := response.resultText(); ok {
 		return resultStr, nil
 	}
 
@@ -438,7 +494,7 @@ func (c *MCPClient) GetCollectionInfo(dbName, collectionName string) (string, er
 		return "", fmt.Errorf("no response from MCP server (check server at %s)", c.baseURL)
 	}
 
-	if resultStr, ok := response.Result.(string); ok {
+	if resultStr, ok := response.resultText(); ok {
 		return resultStr, nil
 	}
 
@@ -469,7 +525,7 @@ func (c *MCPClient) ListDocumentsInCollection(dbName, collectionName string) (st
 		return "", fmt.Errorf("no response from MCP server (check server at %s)", c.baseURL)
 	}
 
-	if resultStr, ok := response.Result.(string); ok {
+	if resultStr, ok := response.resultText(); ok {
 		return resultStr, nil
 	}
 
@@ -562,26 +618,41 @@ func (c *MCPClient) DeleteCollection(dbName, collectionName string) error {
 	return nil
 }
 
-// WriteDocument calls the write_document_to_collection tool on the MCP server
-func (c *MCPClient) WriteDocument(dbName, collectionName, docName, fileName, embedding string) error {
-	// Read the file content
-	content, err := os.ReadFile(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to read file '%s': %w", fileName, err)
+// WriteDocument calls the write_document_to_collection tool on the MCP server with text read by
+// readDocumentSource, its front matter and the given user metadata, and where it came from
+func (c *MCPClient) WriteDocument(dbName, collectionName, docName string, extracted *ExtractedText, userMetadata map[string]interface{}, embedding string) error {
+	metadata := documentMetadata(extracted, userMetadata)
+	metadata["doc_name"] = docName
+
+	source := extracted.URL
+	if source == "" {
+		source = extracted.Path
+	}
+	return c.writeDocument(dbName, collectionName, docName, extracted.Text, source, metadata, embedding)
+}
+
+// WriteDocumentText calls the write_document_to_collection tool with in-memory content
+func (c *MCPClient) WriteDocumentText(dbName, collectionName, docName, text, url string, metadata map[string]interface{}) error {
+	// Always record the document name so listings can map chunks back to documents
+	meta := map[string]interface{}{}
+	for k, v := range metadata {
+		meta[k] = v
 	}
+	meta["doc_name"] = docName
 
+	return c.writeDocument(dbName, collectionName, docName, text, url, meta, "default")
+}
+
+func (c *MCPClient) writeDocument(dbName, collectionName, docName, text, url string, metadata map[string]interface{}, embedding string) error {
 	params := map[string]interface{}{
 		"input": map[string]interface{}{
 			"db_name":         dbName,
 			"collection_name": collectionName,
 			"doc_name":        docName,
-			"text":            string(content),
-			"url":             fileName,
-			"metadata": map[string]interface{}{
-				"filename": fileName,
-				"doc_name": docName,
-			},
-			"embedding": embedding,
+			"text":            text,
+			"url":             url,
+			"metadata":        metadata,
+			"embedding":       embedding,
 		},
 	}
 
@@ -657,7 +728,7 @@ func (c *MCPClient) GetDocument(dbName, collectionName, docName string) (string,
 	}
 
 	// Convert the result to a string
-	resultStr, ok := response.Result.(string)
+	resultStr, ok := response.resultText()
 	if !ok {
 		return "", fmt.Errorf("unexpected response type from MCP server")
 	}
@@ -665,17 +736,80 @@ func (c *MCPClient) GetDocument(dbName, collectionName, docName string) (string,
 	return resultStr, nil
 }
 
-// Query calls the query tool on the MCP server
-func (c *MCPClient) Query(dbName, query string, limit int, collectionName string) (string, error) {
+// GetSupportedChunkingStrategies calls the get_supported_chunking_strategies tool on the MCP server
+func (c *MCPClient) GetSupportedChunkingStrategies() (string, error) {
+	result, err := c.SupportedChunkingStrategies()
+	if err != nil {
+		return "", err
+	}
+
+	resultStr, ok := (&MCPResponse{Result: result}).resultText()
+	if !ok {
+		return "", fmt.Errorf("unexpected response type from MCP server")
+	}
+
+	return resultStr, nil
+}
+
+// SupportedChunkingStrategies returns the get_supported_chunking_strategies result as sent: a
+// string, or the decoded JSON value
+func (c *MCPClient) SupportedChunkingStrategies() (interface{}, error) {
+	response, err := c.callMCPServer("get_supported_chunking_strategies", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, fmt.Errorf("MCP server error: %s", response.Error.Message)
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("no response from MCP server (check server at %s)", c.baseURL)
+	}
+	return response.Result, nil
+}
+
+// ChunkText calls the chunk_text tool on the MCP server to chunk text without storing it
+func (c *MCPClient) ChunkText(text string, chunkingConfig map[string]interface{}) (string, error) {
 	params := map[string]interface{}{
 		"input": map[string]interface{}{
-			"db_name":         dbName,
-			"query":           query,
-			"limit":           limit,
-			"collection_name": collectionName,
+			"text":            text,
+			"chunking_config": chunkingConfig,
 		},
 	}
 
+	response, err := c.callMCPServer("chunk_text", params)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != nil {
+		return "", fmt.Errorf("MCP server error: %s", response.Error.Message)
+	}
+
+	if response.Result == nil {
+		return "", fmt.Errorf("no response from MCP server (check server at %s)", c.baseURL)
+	}
+
+	resultStr, ok := response.resultText()
+	if !ok {
+		return "", fmt.Errorf("unexpected response type from MCP server")
+	}
+
+	return resultStr, nil
+}
+
+// Query calls the query tool on the MCP server
+func (c *MCPClient) Query(dbName, query string, limit int, collectionName string) (string, error) {
+	return c.QueryWithOptions(dbName, query, limit, collectionName, nil)
+}
+
+// QueryWithOptions calls the query tool with additional inputs such as a metadata filter
+func (c *MCPClient) QueryWithOptions(dbName, query string, limit int, collectionName string, options map[string]interface{}) (string, error) {
+	params := map[string]interface{}{
+		"input": retrievalInput(dbName, query, limit, collectionName, options),
+	}
+
 	response, err := c.callMCPServer("query", params)
 	if err != nil {
 		return "", err
@@ -703,12 +837,7 @@ func (c *MCPClient) Query(dbName, query string, limit int, collectionName string
 // Search calls the search tool on the MCP server
 func (c *MCPClient) Search(dbName, query string, limit int, collectionName string) (string, error) {
 	params := map[string]interface{}{
-		"input": map[string]interface{}{
-			"db_name":         dbName,
-			"query":           query,
-			"limit":           limit,
-			"collection_name": collectionName,
-		},
+		"input": retrievalInput(dbName, query, limit, collectionName, nil),
 	}
 
 	response, err := c.callMCPServer("search", params)
@@ -738,6 +867,54 @@ func (c *MCPClient) Search(dbName, query string, limit int, collectionName strin
 	return string(prettyJSON), nil
 }
 
+// SearchHits calls the search tool with additional inputs and decodes the results
+func (c *MCPClient) SearchHits(dbName, query string, limit int, collectionName string, options map[string]interface{}) ([]SearchHit, error) {
+	params := map[string]interface{}{
+		"input": retrievalInput(dbName, query, limit, collectionName, options),
+	}
+
+	response, err := c.callMCPServer("search", params)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("MCP server error: %s", response.Error.Message)
+	}
+	if response.Result == nil {
+		return nil, fmt.Errorf("no response from MCP server (possible causes: missing/invalid collection, empty result, or connection issue at %s)", c.baseURL)
+	}
+
+	resultStr, ok := response.resultText()
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type from MCP server")
+	}
+	var hits []SearchHit
+	if err := json.Unmarshal([]byte(resultStr), &hits); err != nil {
+		var wrapped struct {
+			Results []SearchHit `json:"results"`
+		}
+		if json.Unmarshal([]byte(resultStr), &wrapped) != nil || wrapped.Results == nil {
+			return nil, fmt.Errorf("unexpected search response from MCP server: %w", err)
+		}
+		hits = wrapped.Results
+	}
+	return hits, nil
+}
+
+// retrievalInput builds the input of the search and query tools
+func retrievalInput(dbName, query string, limit int, collectionName string, options map[string]interface{}) map[string]interface{} {
+	input := map[string]interface{}{
+		"db_name":         dbName,
+		"query":           query,
+		"limit":           limit,
+		"collection_name": collectionName,
+	}
+	for key, value := range options {
+		input[key] = value
+	}
+	return input
+}
+
 // ResyncDatabases calls the resync_databases tool on the MCP server
 func (c *MCPClient) ResyncDatabases() (string, error) {
 	// No params required
@@ -766,6 +943,128 @@ func (c *MCPClient) ResyncDatabases() (string, error) {
 	return string(prettyJSON), nil
 }
 
+// ToolInputProperties returns the properties a tool declares for its "input" argument in tools/list.
+// The result is empty when the tool does not exist or its schema does not describe them.
+func (c *MCPClient) ToolInputProperties(tool string) (map[string]bool, error) {
+	c.toolsMu.Lock()
+	defer c.toolsMu.Unlock()
+	if c.tools == nil {
+		tools, err := c.listToolSchemas()
+		if err != nil {
+			return nil, err
+		}
+		c.tools = tools
+	}
+	return schemaInputProperties(c.tools[tool]), nil
+}
+
+// listToolSchemas returns the raw input schema of every tool. ListTools decodes schemas without
+// their $defs, where FastMCP servers describe structured inputs, so tools/list is sent directly.
+func (c *MCPClient) listToolSchemas() (map[string]json.RawMessage, error) {
+	ctx := c.ctx
+	if c.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(c.ctx, c.callTimeout)
+		defer cancel()
+	}
+	if err := c.initialize(ctx); err != nil {
+		return nil, err
+	}
+
+	tools := make(map[string]json.RawMessage)
+	var cursor string
+	for page := 0; ; page++ {
+		var params map[string]interface{}
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		// A string ID cannot collide with the numeric IDs of the client's own requests
+		response, err := c.client.GetTransport().SendRequest(ctx, transport.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      mcp.NewRequestId(fmt.Sprintf("tools-list-%d", page)),
+			Method:  "tools/list",
+			Params:  params,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+		}
+		if response.Error != nil {
+			return nil, fmt.Errorf("failed to list MCP tools: %s", response.Error.Message)
+		}
+
+		var result struct {
+			Tools []struct {
+				Name        string          `json:"name"`
+				InputSchema json.RawMessage `json:"inputSchema"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			return nil, fmt.Errorf("unexpected tools/list response: %w", err)
+		}
+		for _, tool := range result.Tools {
+			tools[tool.Name] = tool.InputSchema
+		}
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// schemaInputProperties returns the property names of the "input" argument of a tool input
+// schema, following local $ref links and single-schema allOf/anyOf wrappers
+func schemaInputProperties(raw json.RawMessage) map[string]bool {
+	properties := make(map[string]bool)
+	var root map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &root) != nil {
+		return properties
+	}
+
+	resolve := func(schema map[string]interface{}) map[string]interface{} {
+		for depth := 0; schema != nil && depth < 8; depth++ {
+			if ref, ok := schema["$ref"].(string); ok {
+				schema = nil
+				for _, key := range []string{"$defs", "definitions"} {
+					prefix := "#/" + key + "/"
+					if defs, ok := root[key].(map[string]interface{}); ok && strings.HasPrefix(ref, prefix) {
+						schema, _ = defs[strings.TrimPrefix(ref, prefix)].(map[string]interface{})
+					}
+				}
+				continue
+			}
+			wrapped := false
+			for _, key := range []string{"allOf", "anyOf"} {
+				// Optional inputs are declared as anyOf the schema and null
+				var options []map[string]interface{}
+				list, _ := schema[key].([]interface{})
+				for _, option := range list {
+					if option, ok := option.(map[string]interface{}); ok && option["type"] != "null" {
+						options = append(options, option)
+					}
+				}
+				if len(options) == 1 {
+					schema, wrapped = options[0], true
+					break
+				}
+			}
+			if !wrapped {
+				return schema
+			}
+		}
+		return schema
+	}
+
+	rootProperties, _ := root["properties"].(map[string]interface{})
+	input, _ := rootProperties["input"].(map[string]interface{})
+	input = resolve(input)
+	inputProperties, _ := input["properties"].(map[string]interface{})
+	for name := range inputProperties {
+		properties[name] = true
+	}
+	return properties
+}
+
 // Close closes the MCP client
 func (c *MCPClient) Close() error {
 	// Cancel the context to prevent context leaks
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags for user-defined document metadata
+var (
+	metaPairs []string
+	metaFile  string
+)
+
+// addMetadataFlags adds --meta and --meta-file to a command that writes documents
+func addMetadataFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&metaPairs, "meta", nil, "Metadata to attach as key=value (repeatable)")
+	cmd.Flags().StringVar(&metaFile, "meta-file", "", "YAML or JSON file with metadata to attach")
+}
+
+// userMetadata holds the metadata from --meta-file and --meta, loaded by loadUserMetadata
+var userMetadata map[string]interface{}
+
+// reservedMetadataKeys are recorded by maestro and cannot be set by users
+var reservedMetadataKeys = map[string]bool{
+	"doc_name":              true,
+	"filename":              true,
+	"url":                   true,
+	"content_type":          true,
+	"extractor":             true,
+	contentHashMetadataKey:  true,
+	versionOfMetadataKey:    true,
+	versionMetadataKey:      true,
+	versionedAtMetadataKey:  true,
+	parentMetadataKey:       true,
+	partMetadataKey:         true,
+	partsMetadataKey:        true,
+	simhashMetadataKey:      true,
+	minhashMetadataKey:      true,
+	repoMetadataKey:         true,
+	repoPathMetadataKey:     true,
+	commitMetadataKey:       true,
+	lastCommitMetadataKey:   true,
+	lastAuthorMetadataKey:   true,
+	lastModifiedMetadataKey: true,
+	redactionsMetadataKey:   true,
+}
+
+// loadUserMetadata loads --meta-file and then --meta, whose values take precedence, into userMetadata
+func loadUserMetadata() error {
+	metadata := map[string]interface{}{}
+	if metaFile != "" {
+		fileMetadata, err := readMetadataFile(metaFile)
+		if err != nil {
+			return err
+		}
+		for key, value := range fileMetadata {
+			metadata[key] = value
+		}
+	}
+
+	pairs, err := parseMetaPairs(metaPairs)
+	if err != nil {
+		return err
+	}
+	for key, value := range pairs {
+		metadata[key] = value
+	}
+
+	for key := range metadata {
+		if reservedMetadataKeys[key] {
+			return fmt.Errorf("metadata key '%s' is reserved", key)
+		}
+	}
+	userMetadata = metadata
+	return nil
+}
+
+// parseMetaPairs parses key=value pairs; values are kept as strings
+func parseMetaPairs(pairs []string) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --meta '%s' (use key=value)", pair)
+		}
+		metadata[key] = value
+	}
+	return metadata, nil
+}
+
+// readMetadataFile reads a YAML or JSON object of metadata
+func readMetadataFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file '%s': %w", path, err)
+	}
+	metadata, err := parseMetadataYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata file '%s': %w", path, err)
+	}
+	return metadata, nil
+}
+
+// parseMetadataYAML decodes a YAML (or JSON) mapping into flat metadata
+func parseMetadataYAML(data []byte) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("expected a mapping of keys to values: %w", err)
+	}
+	metadata := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if value != nil {
+			metadata[key] = flattenMetadataValue(value)
+		}
+	}
+	return metadata, nil
+}
+
+// parseFrontMatter splits a leading YAML block delimited by '---' lines from Markdown lines.
+// Lines are returned unchanged when there is no block or it is not a YAML mapping.
+func parseFrontMatter(lines []string) (map[string]interface{}, []string) {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, lines
+	}
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed == "---" || trimmed == "..." {
+			metadata, err := parseMetadataYAML([]byte(strings.Join(lines[1:i], "\n")))
+			if err != nil {
+				return nil, lines
+			}
+			return metadata, lines[i+1:]
+		}
+	}
+	return nil, lines
+}
+
+// frontMatterMetadata returns the front matter of a Markdown document as metadata
+func frontMatterMetadata(content []byte) map[string]interface{} {
+	metadata, _ := parseFrontMatter(strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"))
+	return metadata
+}
+
+// flattenMetadataValue converts a decoded value to a metadata value, keeping scalars and encoding
+// nested objects and arrays as JSON strings
+func flattenMetadataValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	default:
+		return v
+	}
+}
+
+// documentMetadata combines the metadata written with a document: front matter, then user metadata,
+// then the keys recorded by maestro for its source and extraction
+func documentMetadata(extracted *ExtractedText, user map[string]interface{}) map[string]interface{} {
+	metadata := map[string]interface{}{}
+	for key, value := range extracted.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range user {
+		metadata[key] = value
+	}
+	metadata["content_type"] = extracted.ContentType
+	metadata["extractor"] = extracted.Extractor
+	switch {
+	case extracted.URL != "":
+		metadata["url"] = extracted.URL
+	case extracted.Path != "" && extracted.Path != "stdin":
+		metadata["filename"] = extracted.Path
+	}
+	return metadata
+}
+
+// formatMetadata renders metadata as sorted key=value pairs, leaving out the given keys
+func formatMetadata(metadata map[string]interface{}, omit ...string) string {
+	skip := make(map[string]bool, len(omit))
+	for _, key := range omit {
+		skip[key] = true
+	}
+	var pairs []string
+	for _, key := range sortedKeys(metadata) {
+		if !skip[key] {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, metadata[key]))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
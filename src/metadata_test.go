@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	lines := strings.Split("---\nowner: docs-team\nversion: 2\ntags: [api, v2]\nupdated: 2024-03-01\n---\n# Title\nBody", "\n")
+
+	metadata, body := parseFrontMatter(lines)
+	expected := map[string]interface{}{"owner": "docs-team", "version": 2, "tags": `["api","v2"]`, "updated": "2024-03-01"}
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("parseFrontMatter() metadata = %#v, expected %#v", metadata, expected)
+	}
+	if !reflect.DeepEqual(body, []string{"# Title", "Body"}) {
+		t.Errorf("parseFrontMatter() body = %v", body)
+	}
+
+	// A block that is not a YAML mapping is left in place
+	lines = strings.Split("---\n- a list\n---\nText", "\n")
+	if metadata, body := parseFrontMatter(lines); metadata != nil || len(body) != len(lines) {
+		t.Errorf("parseFrontMatter() = %v, %v, expected no front matter", metadata, body)
+	}
+}
+
+func TestExtractTextFrontMatter(t *testing.T) {
+	extracted, err := extractText("guide.md", []byte("---\nproduct: maestro\n---\n# Guide\n"), false)
+	if err != nil {
+		t.Fatalf("extractText() error = %v", err)
+	}
+	if extracted.Text != "# Guide" || extracted.Metadata["product"] != "maestro" {
+		t.Errorf("extractText() = %+v, expected front matter as metadata", extracted)
+	}
+}
+
+func TestLoadUserMetadata(t *testing.T) {
+	defer func(pairs []string, file string) { metaPairs, metaFile, userMetadata = pairs, file, nil }(metaPairs, metaFile)
+
+	metaFile = filepath.Join(t.TempDir(), "meta.yaml")
+	if err := os.WriteFile(metaFile, []byte("owner: platform\nproduct: maestro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	metaPairs = []string{"owner=docs-team", "version=1.10", "note=a=b"}
+	if err := loadUserMetadata(); err != nil {
+		t.Fatalf("loadUserMetadata() error = %v", err)
+	}
+	expected := map[string]interface{}{"owner": "docs-team", "product": "maestro", "version": "1.10", "note": "a=b"}
+	if !reflect.DeepEqual(userMetadata, expected) {
+		t.Errorf("loadUserMetadata() = %v, expected %v", userMetadata, expected)
+	}
+
+	for _, pairs := range [][]string{{"novalue"}, {"=value"}, {"doc_name=x"}} {
+		metaFile, metaPairs = "", pairs
+		if err := loadUserMetadata(); err == nil {
+			t.Errorf("loadUserMetadata() with %v expected an error", pairs)
+		}
+	}
+}
+
+func TestDocumentMetadata(t *testing.T) {
+	extracted := &ExtractedText{Path: "docs/guide.md", ContentType: "text/markdown", Extractor: "markdown", Metadata: map[string]interface{}{"owner": "front", "title": "Guide"}}
+
+	metadata := documentMetadata(extracted, map[string]interface{}{"owner": "flag"})
+	expected := map[string]interface{}{"owner": "flag", "title": "Guide", "content_type": "text/markdown", "extractor": "markdown", "filename": "docs/guide.md"}
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("documentMetadata() = %v, expected %v", metadata, expected)
+	}
+
+	if got := formatMetadata(metadata, "filename", "content_type", "extractor"); got != "owner=flag title=Guide" {
+		t.Errorf("formatMetadata() = %q", got)
+	}
+}
+
+func TestDocumentListEntries(t *testing.T) {
+	entries := []DocumentRecord{
+		{Name: "a.md", Metadata: map[string]interface{}{"doc_name": "a.md", "owner": "docs"}},
+		{Name: "b.md"},
+		{Name: "a.md", Metadata: map[string]interface{}{"doc_name": "a.md", "owner": "docs"}},
+	}
+
+	documents := documentListEntries(entries)
+	if len(documents) != 2 || documents[0].Name != "a.md" || documents[0].Chunks != 2 || documents[1].Chunks != 1 {
+		t.Fatalf("documentListEntries() = %+v", documents)
+	}
+	if got := formatMetadata(documents[0].Metadata, "doc_name"); got != "owner=docs" {
+		t.Errorf("formatMetadata() = %q, expected %q", got, "owner=docs")
+	}
+}
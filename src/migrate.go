@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for collection migration
+var (
+	migrateTargetVDB     string
+	migrateTargetName    string
+	migrateEmbedding     string
+	migrateChunkStrategy string
+	migrateChunkSize     int
+	migrateChunkOverlap  int
+	migrateConcurrency   int
+	migrateSwap          bool
+)
+
+var collectionMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy a collection to a new collection",
+	Long: `Copy every document of a collection into a new collection, optionally in another
+vector database and with a different embedding model or chunking strategy.
+
+Documents are re-written to the target so they are re-chunked and re-embedded with the
+target's configuration. Embedding and chunking default to the source collection's settings;
+--chunk-size and --chunk-overlap without --chunking-strategy adjust the source chunking.
+With --swap, the source collection is deleted once the target holds the same number of documents.`,
+	Example: `  maestro collection migrate --vdb=src --name=docs --to-name=docs-v2 --embedding=text-embedding-3-large
+  maestro collection migrate --vdb=src --name=docs --to-vdb=dst --to-name=docs --chunking-strategy=Sentence --chunk-size=512
+  maestro collection migrate --vdb=src --name=docs --to-name=docs-v2 --embedding=new-model --swap --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("name")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		if migrateTargetName == "" {
+			return fmt.Errorf("--to-name flag is required")
+		}
+		targetVDB := migrateTargetVDB
+		if targetVDB == "" {
+			targetVDB = vdbName
+		}
+		if targetVDB == vdbName && migrateTargetName == collectionName {
+			return fmt.Errorf("target collection must differ from the source collection")
+		}
+
+		return migrateCollection(vdbName, collectionName, targetVDB, migrateTargetName)
+	},
+}
+
+func init() {
+	collectionMigrateCmd.Flags().String("vdb", "", "Source vector database name")
+	collectionMigrateCmd.Flags().String("name", "", "Source collection name")
+	collectionMigrateCmd.Flags().StringVar(&migrateTargetVDB, "to-vdb", "", "Target vector database name (default: source vector database)")
+	collectionMigrateCmd.Flags().StringVar(&migrateTargetName, "to-name", "", "Target collection name")
+	collectionMigrateCmd.Flags().StringVar(&migrateEmbedding, "embedding", "", "Embedding model for the target collection (default: source embedding)")
+	collectionMigrateCmd.Flags().StringVar(&migrateChunkStrategy, "chunking-strategy", "", "Chunking strategy for the target collection (default: source chunking)")
+	collectionMigrateCmd.Flags().IntVar(&migrateChunkSize, "chunk-size", 0, "Chunk size in characters for the target collection (applied to the source chunking without --chunking-strategy)")
+	collectionMigrateCmd.Flags().IntVar(&migrateChunkOverlap, "chunk-overlap", 0, "Chunk overlap in characters for the target collection (applied to the source chunking without --chunking-strategy)")
+	collectionMigrateCmd.Flags().IntVar(&migrateConcurrency, "concurrency", defaultConcurrency, "Number of documents to copy in parallel")
+	collectionMigrateCmd.Flags().BoolVar(&migrateSwap, "swap", false, "Delete the source collection once document counts match")
+}
+
+func migrateCollection(vdbName, collectionName, targetVDB, targetName string) error {
+	if verbose {
+		fmt.Printf("Migrating collection '%s/%s' to '%s/%s'...\n", vdbName, collectionName, targetVDB, targetName)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would migrate collection '%s' in vector database '%s' to collection '%s' in vector database '%s'\n", collectionName, vdbName, targetName, targetVDB)
+			if migrateSwap {
+				fmt.Printf("[DRY RUN] Would delete collection '%s' from vector database '%s' once document counts match\n", collectionName, vdbName)
+			}
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, name := range []string{vdbName, targetVDB} {
+		var exists bool
+		if err := safeCall(serverURI, func() error {
+			var existsErr error
+			exists, existsErr = client.DatabaseExists(name)
+			return existsErr
+		}); err != nil {
+			return fmt.Errorf("failed to check if database exists: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("vector database '%s' does not exist. Please create it first", name)
+		}
+	}
+
+	source, err := fetchCollectionInfo(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	documents, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+
+	targetCollections, err := fetchCollectionNames(client, serverURI, targetVDB)
+	if err != nil {
+		return err
+	}
+	for _, name := range targetCollections {
+		if name == targetName {
+			return fmt.Errorf("collection '%s' already exists in vector database '%s'", targetName, targetVDB)
+		}
+	}
+
+	embedding := migrateEmbedding
+	if embedding == "" {
+		embedding = source.Embedding
+	}
+	if embedding == "" {
+		embedding = "default"
+	}
+	chunking := migrateChunkingConfig(source.ChunkingConfig(), migrateChunkStrategy, migrateChunkSize, migrateChunkOverlap)
+	if migrateChunkStrategy != "" || migrateChunkSize != 0 || migrateChunkOverlap != 0 {
+		if err := validateChunkingConfig(chunking, fetchChunkingStrategies(client, serverURI)); err != nil {
+			return err
+		}
+	}
+
+	if err := safeCall(serverURI, func() error {
+		return client.CreateCollectionWithChunking(targetVDB, targetName, embedding, chunking)
+	}); err != nil {
+		return fmt.Errorf("failed to create collection '%s' in vector database '%s': %w", targetName, targetVDB, err)
+	}
+	if !silent {
+		fmt.Printf("Created collection '%s' in vector database '%s' with embedding '%s'\n", targetName, targetVDB, embedding)
+	}
+
+	failures := copyDocuments(client, serverURI, vdbName, collectionName, targetVDB, targetName, documents, migrateConcurrency)
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+		}
+		return fmt.Errorf("%d of %d document(s) failed to migrate; collection '%s' was left in place", len(failures), len(documents), collectionName)
+	}
+
+	// Verify the copy before reporting success or deleting the source
+	copied, err := fetchDocumentNames(client, serverURI, targetVDB, targetName)
+	if err != nil {
+		return err
+	}
+	if len(copied) != len(documents) {
+		return fmt.Errorf("document count mismatch: source '%s' has %d document(s), target '%s' has %d; collection '%s' was left in place", collectionName, len(documents), targetName, len(copied), collectionName)
+	}
+
+	if !silent {
+		fmt.Printf("✅ Migrated %d document(s) from '%s/%s' to '%s/%s'\n", len(documents), vdbName, collectionName, targetVDB, targetName)
+	}
+
+	if migrateSwap {
+		if err := confirmDestructiveOperation("delete", fmt.Sprintf("collection '%s' from vector database '%s'", collectionName, vdbName)); err != nil {
+			return err
+		}
+		if err := safeCall(serverURI, func() error {
+			return client.DeleteCollection(vdbName, collectionName)
+		}); err != nil {
+			return fmt.Errorf("failed to delete source collection '%s': %w", collectionName, err)
+		}
+		if !silent {
+			fmt.Printf("✅ Collection '%s' deleted from vector database '%s'\n", collectionName, vdbName)
+		}
+	}
+
+	return nil
+}
+
+// migrateChunkingConfig returns the chunking config of the target collection. --chunking-strategy
+// replaces the source config; otherwise --chunk-size and --chunk-overlap are applied on top of the
+// source's strategy and parameters.
+func migrateChunkingConfig(source map[string]interface{}, strategy string, chunkSize, chunkOverlap int) map[string]interface{} {
+	if strategy == "" && chunkSize == 0 && chunkOverlap == 0 {
+		return source
+	}
+
+	params := map[string]interface{}{}
+	if strategy == "" {
+		strategy = "None"
+		if source != nil {
+			strategy, _ = source["strategy"].(string)
+			sourceParams, _ := source["parameters"].(map[string]interface{})
+			for key, value := range sourceParams {
+				params[key] = value
+			}
+		}
+	}
+	if chunkSize != 0 {
+		params["chunk_size"] = chunkSize
+	}
+	if chunkOverlap != 0 {
+		params["overlap"] = chunkOverlap
+	}
+
+	if strategy == "None" && len(params) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"strategy":   strategy,
+		"parameters": params,
+	}
+}
+
+// copyDocuments re-writes documents from one collection into another and returns a sorted list of failures
+func copyDocuments(client *MCPClient, serverURI, vdbName, collectionName, targetVDB, targetName string, documents []string, concurrency int) []string {
+	progress := newBulkProgress(fmt.Sprintf("Copying %d document(s) to '%s'...", len(documents), targetName), len(documents))
+
+	var mu sync.Mutex
+	var failures []string
+	runConcurrently(documents, concurrency, func(docName string) {
+		defer progress.Increment()
+
+		doc, err := fetchDocument(client, serverURI, vdbName, collectionName, docName)
+		if err == nil {
+			err = safeCall(serverURI, func() error {
+				return client.WriteDocumentText(targetVDB, targetName, docName, doc.Text, doc.URL, doc.Metadata)
+			})
+		}
+		if err != nil {
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", docName, err))
+			mu.Unlock()
+		}
+	})
+
+	progress.Stop(fmt.Sprintf("Copied %d document(s)", len(documents)-len(failures)), len(failures))
+	sort.Strings(failures)
+	return failures
+}
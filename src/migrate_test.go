@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrateChunkingConfig(t *testing.T) {
+	source := map[string]interface{}{
+		"strategy":   "Semantic",
+		"parameters": map[string]interface{}{"chunk_size": float64(768), "model_name": "all-MiniLM-L6-v2"},
+	}
+
+	tests := []struct {
+		name     string
+		source   map[string]interface{}
+		strategy string
+		size     int
+		overlap  int
+		want     map[string]interface{}
+	}{
+		{
+			name:   "no overrides keep the source config",
+			source: source,
+			want:   source,
+		},
+		{
+			name:    "size and overlap apply on top of the source config",
+			source:  source,
+			size:    512,
+			overlap: 64,
+			want: map[string]interface{}{
+				"strategy":   "Semantic",
+				"parameters": map[string]interface{}{"chunk_size": 512, "overlap": 64, "model_name": "all-MiniLM-L6-v2"},
+			},
+		},
+		{
+			name:     "a strategy replaces the source config",
+			source:   source,
+			strategy: "Sentence",
+			size:     256,
+			want: map[string]interface{}{
+				"strategy":   "Sentence",
+				"parameters": map[string]interface{}{"chunk_size": 256},
+			},
+		},
+		{
+			name: "size without source chunking keeps the None strategy",
+			size: 512,
+			want: map[string]interface{}{
+				"strategy":   "None",
+				"parameters": map[string]interface{}{"chunk_size": 512},
+			},
+		},
+		{
+			name:     "None without parameters disables chunking",
+			source:   source,
+			strategy: "None",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		got := migrateChunkingConfig(tt.source, tt.strategy, tt.size, tt.overlap)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: migrateChunkingConfig() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// the source parameters must not be modified
+	if params := source["parameters"].(map[string]interface{}); len(params) != 2 || params["chunk_size"] != float64(768) {
+		t.Errorf("source parameters were modified: %v", params)
+	}
+}
+
+func TestMigrateChunkingConfigIgnoresSemanticFlags(t *testing.T) {
+	// migrate does not register the --semantic-* flags, so values left by other commands must not leak in
+	semanticModel, semanticWindowSize = "other-model", 3
+	defer func() { semanticModel, semanticWindowSize = "", 0 }()
+
+	got := migrateChunkingConfig(nil, "Fixed", 512, 0)
+	want := map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 512}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("migrateChunkingConfig() = %v, want %v", got, want)
+	}
+}
+
+func TestMigrateChunkingConfigValidation(t *testing.T) {
+	// an overlap applied to the source chunk size is checked against it
+	source := map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": float64(100)}}
+	if err := validateChunkingConfig(migrateChunkingConfig(source, "", 0, 200), builtinChunkingStrategies); err == nil {
+		t.Error("an overlap larger than the source chunk size should be rejected")
+	}
+	// chunk parameters without any chunking strategy are rejected rather than ignored
+	if err := validateChunkingConfig(migrateChunkingConfig(nil, "", 512, 0), builtinChunkingStrategies); err == nil {
+		t.Error("--chunk-size for a collection without chunking should be rejected")
+	}
+}
@@ -118,6 +118,7 @@ type ProgressBar struct {
 	total     int
 	current   int
 	width     int
+	unit      string
 	isRunning bool
 	startTime time.Time
 }
@@ -147,6 +148,11 @@ func (p *ProgressBar) Start() {
 	p.update()
 }
 
+// SetUnit enables throughput and ETA reporting, measuring throughput in unit per second
+func (p *ProgressBar) SetUnit(unit string) {
+	p.unit = unit
+}
+
 // Update updates the progress bar with current progress
 func (p *ProgressBar) Update(current int) {
 	if !p.isRunning {
@@ -182,7 +188,22 @@ func (p *ProgressBar) update() {
 
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", p.width-filled)
 
-	fmt.Fprintf(os.Stderr, "\r[%s] %d/%d (%.1f%%)", bar, p.current, p.total, percentage*100)
+	rate := ""
+	if p.unit != "" {
+		rate = formatThroughput(p.current, p.total, time.Since(p.startTime), p.unit)
+	}
+
+	fmt.Fprintf(os.Stderr, "\r[%s] %d/%d (%.1f%%)%s", bar, p.current, p.total, percentage*100, rate)
+}
+
+// formatThroughput renders the rate and the estimated time remaining of a progress bar
+func formatThroughput(current, total int, elapsed time.Duration, unit string) string {
+	if current == 0 || elapsed <= 0 {
+		return fmt.Sprintf(" %s/s: -, ETA: -", unit)
+	}
+	rate := float64(current) / elapsed.Seconds()
+	eta := time.Duration(float64(total-current) / rate * float64(time.Second)).Round(time.Second)
+	return fmt.Sprintf(" %.1f %s/s, ETA: %s  ", rate, unit, eta)
 }
 
 // Stop stops the progress bar with a completion message
@@ -204,3 +204,12 @@ func TestProgressBarIncrementExceedsTotal(t *testing.T) {
 		t.Errorf("Current should be capped at total, got %d", bar.current)
 	}
 }
+
+func TestFormatThroughput(t *testing.T) {
+	if got := formatThroughput(0, 10, time.Second, "files"); got != " files/s: -, ETA: -" {
+		t.Errorf("formatThroughput() before progress = %q", got)
+	}
+	if got := formatThroughput(5, 25, 2*time.Second, "files"); got != " 2.5 files/s, ETA: 8s  " {
+		t.Errorf("formatThroughput() = %q", got)
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 var (
-	docLimit int
+	docLimit         int
+	interactiveQuery bool
 )
 
 var queryCmd = &cobra.Command{
@@ -17,11 +18,39 @@ var queryCmd = &cobra.Command{
 	Long: `Query documents in a vector database using natural language.
 	
 This command allows you to ask questions about documents stored in a vector database.
-The query agent will search through the documents and provide relevant answers.`,
+The query agent will search through the documents and provide relevant answers.
+
+With --interactive, queries are read line by line in a session that keeps one connection open;
+see 'maestro chat --help' for the session commands.
+
+--where, --min-score and --max-distance narrow the documents the answer is based on (see
+'maestro search --help'). Servers that cannot apply them to queries return the matching
+passages instead of an answer.`,
 	Example: `  maestro query "What is the main topic of the documents?" --vdb=my-vdb
-  maestro query "Find information about API endpoints" --vdb=my-vdb --doc-limit 10`,
-	Args: cobra.ExactArgs(1),
+  maestro query "Find information about API endpoints" --vdb=my-vdb --doc-limit 10
+  maestro query "How do I upgrade?" --vdb=my-vdb --where 'meta.product=foo AND meta.version>=2'
+  maestro query --interactive --vdb=my-vdb --collection=docs`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if interactiveQuery {
+			if len(args) > 0 {
+				return fmt.Errorf("--interactive reads queries from the session and takes no query argument")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := retrievalOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if interactiveQuery {
+			cmd.SilenceUsage = true
+			vdbName, _ := cmd.Flags().GetString("vdb")
+			collectionName, _ := cmd.Flags().GetString("collection")
+			return startQuerySession(vdbName, collectionName, docLimit, opts)
+		}
+
 		query := args[0]
 		vdbName, _ := cmd.Flags().GetString("vdb")
 		collectionName, _ := cmd.Flags().GetString("collection")
@@ -33,7 +62,6 @@ The query agent will search through the documents and provide relevant answers.`
 
 		// Use interactive selection if vdb name is not provided
 		if vdbName == "" {
-			var err error
 			vdbName, err = PromptForVectorDatabase(vdbName)
 			if err != nil {
 				return fmt.Errorf("failed to select vector database: %w", err)
@@ -44,7 +72,6 @@ The query agent will search through the documents and provide relevant answers.`
 		if collectionName == "" {
 			// If dry-run, don't interact; leave empty so downstream prints dry-run and exits
 			if !dryRun {
-				var err error
 				collectionName, err = PromptForCollection(vdbName, collectionName)
 				if err != nil {
 					return fmt.Errorf("failed to select collection: %w", err)
@@ -52,11 +79,11 @@ The query agent will search through the documents and provide relevant answers.`
 			}
 		}
 
-		return queryVectorDatabase(vdbName, query, collectionName)
+		return queryVectorDatabase(vdbName, query, collectionName, opts)
 	},
 }
 
-func queryVectorDatabase(dbName, query, collectionName string) error {
+func queryVectorDatabase(dbName, query, collectionName string, opts retrievalOptions) error {
 	// Initialize progress indicator
 	var progress *ProgressIndicator
 	if ShouldShowProgress() {
@@ -72,7 +99,11 @@ func queryVectorDatabase(dbName, query, collectionName string) error {
 		if progress != nil {
 			progress.Stop("Dry run completed")
 		}
-		fmt.Println("[DRY RUN] Would query vector database")
+		if opts.IsZero() {
+			fmt.Println("[DRY RUN] Would query vector database")
+		} else {
+			fmt.Printf("[DRY RUN] Would query vector database with %s\n", opts)
+		}
 		return nil
 	}
 
@@ -108,7 +139,7 @@ func queryVectorDatabase(dbName, query, collectionName string) error {
 	}
 
 	// Call the query method
-	result, err := client.Query(dbName, query, docLimit, collectionName)
+	result, err := queryWithOptions(client, dbName, query, docLimit, collectionName, opts)
 	if err != nil {
 		if progress != nil {
 			progress.StopWithError("Query failed")
@@ -132,4 +163,6 @@ func init() {
 	queryCmd.Flags().String("vdb", "", "Vector database name")
 	queryCmd.Flags().String("collection", "", "Collection name to search in")
 	queryCmd.Flags().IntVarP(&docLimit, "doc-limit", "d", 5, "Maximum number of documents to consider")
+	queryCmd.Flags().BoolVarP(&interactiveQuery, "interactive", "i", false, "Start an interactive query session")
+	addRetrievalFlags(queryCmd)
 }
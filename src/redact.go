@@ -0,0 +1,508 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Redaction modes for --redact
+const (
+	redactModeMask   = "mask"
+	redactModeDrop   = "drop"
+	redactModeReport = "report"
+)
+
+// redactionsMetadataKey records how many values were masked in a document
+const redactionsMetadataKey = "redactions"
+
+// Flags for redaction
+var (
+	redactMode       string
+	redactConfigFile string
+)
+
+// addRedactFlags adds --redact and --redact-config to a command that writes documents
+func addRedactFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&redactMode, "redact", "", "Scan documents for PII and secrets before writing them (mask, drop, report)")
+	cmd.Flags().StringVar(&redactConfigFile, "redact-config", "", "YAML or JSON file with redaction rules (requires --redact)")
+}
+
+// redactionRule finds one kind of sensitive value
+type redactionRule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Group       int                                    // submatch to redact; 0 redacts the whole match
+	Validate    func(text string, start, end int) bool // optional check of a match
+	Replacement string
+}
+
+// redactionConfig is the format of --redact-config
+type redactionConfig struct {
+	Rules []struct {
+		Name        string `yaml:"name"`
+		Pattern     string `yaml:"pattern"`
+		Replacement string `yaml:"replacement"`
+	} `yaml:"rules"`
+	Disable []string `yaml:"disable"`
+	Allow   []string `yaml:"allow"`
+}
+
+// redactor applies redaction rules to documents and collects a report of what it found
+type redactor struct {
+	mode  string
+	rules []redactionRule
+	allow []*regexp.Regexp
+
+	mu     sync.Mutex
+	report *RedactionReport
+}
+
+// activeRedactor is set by loadRedaction when --redact is given
+var activeRedactor *redactor
+
+// RedactionReport summarizes the findings of a redaction run; it never contains the values found
+type RedactionReport struct {
+	Mode      string             `json:"mode"`
+	Scanned   int                `json:"scanned"`
+	Affected  int                `json:"affected"`
+	Dropped   int                `json:"dropped"`
+	Findings  map[string]int     `json:"findings"`
+	Documents []RedactedDocument `json:"documents"`
+}
+
+// RedactedDocument lists the findings in one document
+type RedactedDocument struct {
+	Document string         `json:"document"`
+	Action   string         `json:"action"`
+	Findings map[string]int `json:"findings"`
+	Lines    []int          `json:"lines"`
+}
+
+// RedactionDropError reports a document that was not written because it contains sensitive values
+type RedactionDropError struct {
+	Document string
+	Findings map[string]int
+}
+
+func (e *RedactionDropError) Error() string {
+	return fmt.Sprintf("dropped by redaction: %s", formatFindings(e.Findings))
+}
+
+// isRedactionDrop reports whether err is a RedactionDropError
+func isRedactionDrop(err error) bool {
+	var dropErr *RedactionDropError
+	return errors.As(err, &dropErr)
+}
+
+// builtinRedactionRules returns the built-in detectors. Earlier rules take precedence over later ones
+// where matches overlap, so the generic high-entropy detector comes last.
+func builtinRedactionRules() []redactionRule {
+	return []redactionRule{
+		{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----[\s\S]*?-----END (?:[A-Z]+ )?PRIVATE KEY-----`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+		{Name: "aws_access_key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+		{Name: "aws_secret_key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`), Group: 1},
+		{Name: "gcp_api_key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+		{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+		{Name: "credit_card", Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), Validate: validCardNumber},
+		{Name: "phone", Pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{2,4}\)[ .-]?|\b\d{2,4}[ .-])\d{3,4}[ .-]\d{3,4}\b`), Validate: validPhoneNumber},
+		{Name: "high_entropy", Pattern: regexp.MustCompile(`[A-Za-z0-9+=_-]{20,}`), Validate: highEntropyToken},
+	}
+}
+
+// loadRedaction validates --redact and builds the redactor from the built-in rules and --redact-config
+func loadRedaction() error {
+	activeRedactor = nil
+	if redactMode == "" {
+		if redactConfigFile != "" {
+			return fmt.Errorf("--redact-config requires --redact mask|drop|report")
+		}
+		return nil
+	}
+	if redactMode != redactModeMask && redactMode != redactModeDrop && redactMode != redactModeReport {
+		return fmt.Errorf("unsupported --redact mode '%s' (use mask, drop or report)", redactMode)
+	}
+
+	r, err := newRedactor(redactMode, redactConfigFile)
+	if err != nil {
+		return err
+	}
+	activeRedactor = r
+	return nil
+}
+
+// newRedactor creates a redactor with the built-in rules adjusted by an optional config file
+func newRedactor(mode, configFile string) (*redactor, error) {
+	r := &redactor{mode: mode, rules: builtinRedactionRules()}
+	r.report = &RedactionReport{Mode: mode, Findings: map[string]int{}, Documents: []RedactedDocument{}}
+	if configFile == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction config '%s': %w", configFile, err)
+	}
+	var config redactionConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid redaction config '%s': %w", configFile, err)
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range config.Disable {
+		disabled[name] = true
+	}
+	var rules []redactionRule
+	for _, rule := range r.rules {
+		if !disabled[rule.Name] {
+			rules = append(rules, rule)
+		}
+		delete(disabled, rule.Name)
+	}
+	if len(disabled) > 0 {
+		return nil, fmt.Errorf("invalid redaction config '%s': unknown detector(s) %s in disable", configFile, strings.Join(sortedKeys(disabled), ", "))
+	}
+
+	// User rules run before the built-in detectors so their replacements win on overlaps
+	var userRules []redactionRule
+	for i, rule := range config.Rules {
+		if rule.Name == "" || rule.Pattern == "" {
+			return nil, fmt.Errorf("invalid redaction config '%s': rule %d needs a name and a pattern", configFile, i+1)
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction config '%s': rule '%s': %w", configFile, rule.Name, err)
+		}
+		userRules = append(userRules, redactionRule{Name: rule.Name, Pattern: pattern, Replacement: rule.Replacement})
+	}
+	r.rules = append(userRules, rules...)
+
+	for _, allow := range config.Allow {
+		pattern, err := regexp.Compile(allow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction config '%s': allow pattern '%s': %w", configFile, allow, err)
+		}
+		r.allow = append(r.allow, pattern)
+	}
+	return r, nil
+}
+
+// redactionMatch is a value found by a rule
+type redactionMatch struct {
+	rule       *redactionRule
+	start, end int
+}
+
+// find returns the non-overlapping matches of all rules in text, in order. Where matches overlap, the
+// rule listed first wins.
+func (r *redactor) find(text string) []redactionMatch {
+	// matches is kept sorted by start; it never contains overlapping matches
+	var matches []redactionMatch
+	for i := range r.rules {
+		rule := &r.rules[i]
+		for _, loc := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if rule.Group > 0 && len(loc) > 2*rule.Group+1 && loc[2*rule.Group] >= 0 {
+				start, end = loc[2*rule.Group], loc[2*rule.Group+1]
+			}
+			if start == end || rule.Validate != nil && !rule.Validate(text, start, end) {
+				continue
+			}
+			j := sort.Search(len(matches), func(k int) bool { return matches[k].start >= start })
+			if (j > 0 && matches[j-1].end > start) || (j < len(matches) && matches[j].start < end) {
+				continue
+			}
+			if r.allowed(text[start:end]) {
+				continue
+			}
+			matches = append(matches, redactionMatch{})
+			copy(matches[j+1:], matches[j:])
+			matches[j] = redactionMatch{rule: rule, start: start, end: end}
+		}
+	}
+	return matches
+}
+
+func (r *redactor) allowed(value string) bool {
+	for _, pattern := range r.allow {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact scans a document's text and string metadata values, masking what it finds in place and
+// returning the number of values masked. In drop mode a document with findings is rejected with a
+// RedactionDropError; report mode leaves the document unchanged.
+func (r *redactor) Redact(docName string, extracted *ExtractedText) (int, error) {
+	findings := make(map[string]int)
+	var lines []int
+	matches := r.find(extracted.Text)
+	for _, m := range matches {
+		findings[m.rule.Name]++
+		line := strings.Count(extracted.Text[:m.start], "\n") + 1
+		if len(lines) == 0 || lines[len(lines)-1] != line {
+			lines = append(lines, line)
+		}
+	}
+	metadataMatches := make(map[string][]redactionMatch)
+	for key, value := range extracted.Metadata {
+		if text, ok := value.(string); ok {
+			if found := r.find(text); len(found) > 0 {
+				metadataMatches[key] = found
+				for _, m := range found {
+					findings[m.rule.Name]++
+				}
+			}
+		}
+	}
+
+	action := map[string]string{redactModeMask: "masked", redactModeDrop: "dropped", redactModeReport: "reported"}[r.mode]
+	r.record(docName, action, findings, lines)
+	if len(findings) == 0 {
+		return 0, nil
+	}
+
+	switch r.mode {
+	case redactModeDrop:
+		return 0, &RedactionDropError{Document: docName, Findings: findings}
+	case redactModeReport:
+		return 0, nil
+	}
+
+	masked := len(matches)
+	extracted.Text = maskMatches(extracted.Text, matches)
+	for key, found := range metadataMatches {
+		extracted.Metadata[key] = maskMatches(extracted.Metadata[key].(string), found)
+		masked += len(found)
+	}
+	return masked, nil
+}
+
+// maskMatches replaces matches, which must be sorted and not overlap, with their rule's replacement
+func maskMatches(text string, matches []redactionMatch) string {
+	var masked strings.Builder
+	last := 0
+	for _, m := range matches {
+		masked.WriteString(text[last:m.start])
+		replacement := m.rule.Replacement
+		if replacement == "" {
+			replacement = fmt.Sprintf("[REDACTED:%s]", m.rule.Name)
+		}
+		masked.WriteString(replacement)
+		last = m.end
+	}
+	masked.WriteString(text[last:])
+	return masked.String()
+}
+
+// record adds a scanned document to the report
+func (r *redactor) record(docName, action string, findings map[string]int, lines []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Scanned++
+	if len(findings) == 0 {
+		return
+	}
+	r.report.Affected++
+	if action == "dropped" {
+		r.report.Dropped++
+	}
+	for name, count := range findings {
+		r.report.Findings[name] += count
+	}
+	r.report.Documents = append(r.report.Documents, RedactedDocument{Document: docName, Action: action, Findings: findings, Lines: lines})
+}
+
+// Report returns the findings so far with documents sorted by name
+func (r *redactor) Report() *RedactionReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := *r.report
+	report.Documents = append([]RedactedDocument{}, r.report.Documents...)
+	sort.Slice(report.Documents, func(i, j int) bool { return report.Documents[i].Document < report.Documents[j].Document })
+	return &report
+}
+
+// redactDocument applies --redact to a document about to be written, recording the number of masked
+// values in its metadata. Without --redact the document is left unchanged.
+func redactDocument(docName string, extracted *ExtractedText) error {
+	if activeRedactor == nil {
+		return nil
+	}
+	masked, err := activeRedactor.Redact(docName, extracted)
+	if err != nil {
+		return err
+	}
+	if masked > 0 {
+		if extracted.Metadata == nil {
+			extracted.Metadata = map[string]interface{}{}
+		}
+		extracted.Metadata[redactionsMetadataKey] = masked
+	}
+	return nil
+}
+
+// redactionReport returns the report of the active redactor, or nil without --redact
+func redactionReport() *RedactionReport {
+	if activeRedactor == nil {
+		return nil
+	}
+	return activeRedactor.Report()
+}
+
+// printRedactionReport prints a redaction summary as text
+func printRedactionReport(report *RedactionReport) {
+	if report == nil {
+		return
+	}
+	if report.Affected == 0 {
+		fmt.Printf("🔒 Redaction (%s): no sensitive values found in %d document(s)\n", report.Mode, report.Scanned)
+		return
+	}
+
+	verb := map[string]string{redactModeMask: "masked", redactModeDrop: "found", redactModeReport: "found (not redacted)"}[report.Mode]
+	total := 0
+	for _, count := range report.Findings {
+		total += count
+	}
+	fmt.Printf("🔒 Redaction (%s): %d value(s) %s in %d of %d document(s): %s\n", report.Mode, total, verb, report.Affected, report.Scanned, formatFindings(report.Findings))
+	if report.Dropped > 0 {
+		fmt.Printf("   %d document(s) dropped\n", report.Dropped)
+	}
+	for _, doc := range report.Documents {
+		lines := make([]string, len(doc.Lines))
+		for i, line := range doc.Lines {
+			lines[i] = fmt.Sprint(line)
+		}
+		fmt.Printf("  ⚠️  %s (%s): %s on line(s) %s\n", doc.Document, doc.Action, formatFindings(doc.Findings), strings.Join(lines, ", "))
+	}
+}
+
+// formatFindings renders finding counts as "email 2, jwt 1"
+func formatFindings(findings map[string]int) string {
+	var parts []string
+	for _, name := range sortedKeys(findings) {
+		parts = append(parts, fmt.Sprintf("%s %d", name, findings[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// validCardNumber accepts 13 to 19 digit numbers that pass the Luhn check
+func validCardNumber(text string, start, end int) bool {
+	var digits []int
+	for _, c := range text[start:end] {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validPhoneNumber accepts 9 to 15 digits that are not part of a longer number, version or address
+func validPhoneNumber(text string, start, end int) bool {
+	digits := 0
+	for _, c := range text[start:end] {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	if digits < 9 || digits > 15 {
+		return false
+	}
+	isNumberPart := func(c byte) bool { return c >= '0' && c <= '9' || c == '.' }
+	if start > 0 && isNumberPart(text[start-1]) {
+		return false
+	}
+	if end < len(text) && isNumberPart(text[end]) && (end+1 >= len(text) || text[end+1] >= '0' && text[end+1] <= '9') {
+		return false
+	}
+	return true
+}
+
+// highEntropyToken accepts long tokens with the character entropy of random keys: hexadecimal strings
+// of at least 32 characters with 3 bits per character, and mixed-case tokens with digits with 4 bits
+// that are not made of words, such as camelCase identifiers in source code
+func highEntropyToken(text string, start, end int) bool {
+	token := text[start:end]
+	hasLower, hasUpper, hasDigit, hex := false, false, false, true
+	counts := make(map[rune]int)
+	for _, c := range token {
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			hex = false
+		}
+		counts[c]++
+	}
+	if !hasDigit || !hex && !(hasLower && hasUpper) {
+		return false
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(len(token))
+		entropy -= p * math.Log2(p)
+	}
+	if hex {
+		return len(token) >= 32 && entropy >= 3.0
+	}
+	return entropy >= 4.0 && !wordShaped(token)
+}
+
+// minIdentifierWordLength is the average length of the letter runs of a token, split at case
+// changes, from which it reads as words; random keys change case every two letters or so
+const minIdentifierWordLength = 3.5
+
+// wordShaped reports whether a token is made of words, like newFakeRetrievalClient2 or
+// TestIngestRedactInvalidOptionsV2: its letters split at lower-to-upper case changes and at other
+// characters into runs of minIdentifierWordLength letters on average
+func wordShaped(token string) bool {
+	words, letters := 0, 0
+	var previous rune
+	for _, c := range token {
+		isLetter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		if isLetter {
+			startsWord := !(previous >= 'a' && previous <= 'z' || previous >= 'A' && previous <= 'Z') ||
+				(c >= 'A' && c <= 'Z' && previous >= 'a' && previous <= 'z')
+			if startsWord {
+				words++
+			}
+			letters++
+		}
+		previous = c
+	}
+	return words > 0 && float64(letters)/float64(words) >= minIdentifierWordLength
+}
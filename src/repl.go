@@ -0,0 +1,276 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// chatCmd starts an interactive query session, the same as query --interactive
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Start an interactive query session",
+	Long: `Start an interactive query session against a vector database.
+
+Each line is sent as a query and answered in turn over a single MCP connection. Lines starting
+with ':' are session commands:
+
+` + sessionCommandsHelp + `
+Input lines are kept in ~/.maestro/history and can be recalled with the arrow keys.`,
+	Example: `  maestro chat --vdb=my-vdb --collection=docs
+  maestro chat --vdb=my-vdb --doc-limit 10`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+		limit, _ := cmd.Flags().GetInt("doc-limit")
+		opts, err := retrievalOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		return startQuerySession(vdbName, collectionName, limit, opts)
+	},
+}
+
+// sessionCommandsHelp lists the ':' commands of an interactive query session
+const sessionCommandsHelp = `  :vdb [NAME]          Show or switch the vector database
+  :collection [NAME]   Show or switch the collection
+  :limit [N]           Show or change the number of documents to consider
+  :save FILE           Save the session transcript as markdown
+  :help                List the session commands
+  :quit                End the session (Ctrl-D also works)
+`
+
+// transcriptEntry is one answered (or failed) query of an interactive session
+type transcriptEntry struct {
+	Query      string
+	VDB        string
+	Collection string
+	Limit      int
+	Result     string
+	Err        error
+	Time       time.Time
+}
+
+// querySession is an interactive query session sharing one MCP client across turns
+type querySession struct {
+	vdb        string
+	collection string
+	limit      int
+	out        io.Writer
+	query      func(vdb, query string, limit int, collection string) (string, error)
+	transcript []transcriptEntry
+}
+
+// errSessionEnded is returned by handleMetaCommand when the user ends the session
+var errSessionEnded = errors.New("session ended")
+
+// startQuerySession resolves the target, connects once and runs the read-query-print loop
+func startQuerySession(vdbName, collectionName string, limit int, opts retrievalOptions) error {
+	if limit <= 0 {
+		return fmt.Errorf("doc-limit must be a positive number")
+	}
+
+	if vdbName == "" {
+		var err error
+		vdbName, err = PromptForVectorDatabase(vdbName)
+		if err != nil {
+			return fmt.Errorf("failed to select vector database: %w", err)
+		}
+	}
+	if collectionName == "" && !dryRun {
+		var err error
+		collectionName, err = PromptForCollection(vdbName, collectionName)
+		if err != nil {
+			return fmt.Errorf("failed to select collection: %w", err)
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("[DRY RUN] Would start an interactive query session on vector database '%s'\n", vdbName)
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session := &querySession{
+		vdb:        vdbName,
+		collection: collectionName,
+		limit:      limit,
+		out:        os.Stdout,
+		query: func(vdb, query string, limit int, collection string) (result string, err error) {
+			err = safeCall(serverURI, func() error {
+				result, err = queryWithOptions(client, vdb, query, limit, collection, opts)
+				return err
+			})
+			return result, err
+		},
+	}
+
+	historyFile, err := defaultHistoryFile()
+	if err != nil {
+		return err
+	}
+	history, err := loadHistory(historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	reader := newLineReader(history)
+
+	if !silent {
+		fmt.Printf("Querying vector database '%s', collection '%s'. Type :help for commands, :quit to exit.\n", session.vdb, session.collection)
+	}
+	for {
+		line, err := reader.ReadLine(session.prompt())
+		if err == errLineInterrupted {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		reader.AddHistory(line)
+		if err := appendHistory(historyFile, line); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if err := session.handleMetaCommand(line); err == errSessionEnded {
+				return nil
+			} else if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			continue
+		}
+		session.ask(line)
+	}
+}
+
+// prompt shows the current target of the session
+func (s *querySession) prompt() string {
+	if s.collection == "" {
+		return fmt.Sprintf("%s> ", s.vdb)
+	}
+	return fmt.Sprintf("%s/%s> ", s.vdb, s.collection)
+}
+
+// ask runs one query and records it in the transcript
+func (s *querySession) ask(query string) {
+	var progress *ProgressIndicator
+	if ShouldShowProgress() {
+		progress = NewProgressIndicator("Processing query...")
+		progress.Start()
+	}
+
+	entry := transcriptEntry{Query: query, VDB: s.vdb, Collection: s.collection, Limit: s.limit, Time: time.Now()}
+	entry.Result, entry.Err = s.query(s.vdb, query, s.limit, s.collection)
+	s.transcript = append(s.transcript, entry)
+
+	if entry.Err != nil {
+		if progress != nil {
+			progress.StopWithError("Query failed")
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to query vector database: %v\n", entry.Err)
+		return
+	}
+	if progress != nil {
+		progress.Stop("Query completed successfully")
+	}
+	fmt.Fprintln(s.out, entry.Result)
+}
+
+// handleMetaCommand applies a ':' session command
+func (s *querySession) handleMetaCommand(line string) error {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		return fmt.Errorf("empty command, type :help for the list of commands")
+	}
+	name, args := fields[0], fields[1:]
+	if len(args) > 1 && name != "save" {
+		return fmt.Errorf(":%s takes at most one argument", name)
+	}
+
+	switch name {
+	case "vdb":
+		if len(args) == 1 {
+			s.vdb = args[0]
+		}
+		fmt.Fprintf(s.out, "Vector database: %s\n", s.vdb)
+	case "collection":
+		if len(args) == 1 {
+			s.collection = args[0]
+		}
+		fmt.Fprintf(s.out, "Collection: %s\n", s.collection)
+	case "limit":
+		if len(args) == 1 {
+			limit, err := strconv.Atoi(args[0])
+			if err != nil || limit <= 0 {
+				return fmt.Errorf("limit must be a positive number, got '%s'", args[0])
+			}
+			s.limit = limit
+		}
+		fmt.Fprintf(s.out, "Document limit: %d\n", s.limit)
+	case "save":
+		if len(args) == 0 {
+			return fmt.Errorf(":save requires a file name")
+		}
+		path := strings.Join(args, " ")
+		if err := os.WriteFile(path, []byte(s.renderTranscript()), 0o644); err != nil {
+			return fmt.Errorf("failed to save transcript: %w", err)
+		}
+		fmt.Fprintf(s.out, "✅ Saved transcript of %d queries to %s\n", len(s.transcript), path)
+	case "help":
+		fmt.Fprint(s.out, "Session commands:\n"+sessionCommandsHelp)
+	case "quit", "exit", "q":
+		return errSessionEnded
+	default:
+		return fmt.Errorf("unknown command ':%s', type :help for the list of commands", name)
+	}
+	return nil
+}
+
+// renderTranscript renders the queries of the session as a markdown document
+func (s *querySession) renderTranscript() string {
+	var b strings.Builder
+	b.WriteString("# Maestro query session\n")
+	for _, entry := range s.transcript {
+		fmt.Fprintf(&b, "\n## %s\n\n", entry.Query)
+		target := entry.VDB
+		if entry.Collection != "" {
+			target += "/" + entry.Collection
+		}
+		fmt.Fprintf(&b, "_%s · limit %d · %s_\n\n", target, entry.Limit, entry.Time.Format(time.RFC3339))
+		if entry.Err != nil {
+			fmt.Fprintf(&b, "> Error: %v\n", entry.Err)
+			continue
+		}
+		b.WriteString(strings.TrimRight(entry.Result, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func init() {
+	chatCmd.Flags().String("vdb", "", "Vector database name")
+	chatCmd.Flags().String("collection", "", "Collection name to search in")
+	chatCmd.Flags().IntP("doc-limit", "d", 5, "Maximum number of documents to consider")
+	addRetrievalFlags(chatCmd)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestSession() (*querySession, *strings.Builder, *[]string) {
+	var out strings.Builder
+	var calls []string
+	session := &querySession{
+		vdb:        "vdb1",
+		collection: "docs",
+		limit:      5,
+		out:        &out,
+		query: func(vdb, query string, limit int, collection string) (string, error) {
+			calls = append(calls, fmt.Sprintf("%s/%s/%d: %s", vdb, collection, limit, query))
+			if query == "fail" {
+				return "", fmt.Errorf("no such collection")
+			}
+			return "answer to " + query, nil
+		},
+	}
+	return session, &out, &calls
+}
+
+func TestQuerySessionMetaCommands(t *testing.T) {
+	session, out, calls := newTestSession()
+
+	for _, line := range []string{":vdb vdb2", ":collection notes", ":limit 3"} {
+		if err := session.handleMetaCommand(line); err != nil {
+			t.Fatalf("handleMetaCommand(%q) error = %v", line, err)
+		}
+	}
+	if session.prompt() != "vdb2/notes> " {
+		t.Errorf("prompt() = %q", session.prompt())
+	}
+	session.ask("what changed?")
+	if want := []string{"vdb2/notes/3: what changed?"}; len(*calls) != 1 || (*calls)[0] != want[0] {
+		t.Errorf("query calls = %q, want %q", *calls, want)
+	}
+	if !strings.Contains(out.String(), "answer to what changed?") {
+		t.Errorf("output missing answer: %q", out.String())
+	}
+
+	out.Reset()
+	if err := session.handleMetaCommand(":limit"); err != nil || !strings.Contains(out.String(), "Document limit: 3") {
+		t.Errorf(":limit without argument = %q, %v", out.String(), err)
+	}
+
+	for _, line := range []string{":limit 0", ":limit many", ":vdb a b", ":save", ":bogus", ":"} {
+		if err := session.handleMetaCommand(line); err == nil {
+			t.Errorf("handleMetaCommand(%q) should fail", line)
+		}
+	}
+	if session.limit != 3 || session.vdb != "vdb2" {
+		t.Errorf("failed commands changed the session: limit %d, vdb %s", session.limit, session.vdb)
+	}
+	for _, line := range []string{":quit", ":exit", ":q"} {
+		if err := session.handleMetaCommand(line); err != errSessionEnded {
+			t.Errorf("handleMetaCommand(%q) = %v, want errSessionEnded", line, err)
+		}
+	}
+}
+
+func TestQuerySessionTranscript(t *testing.T) {
+	session, _, _ := newTestSession()
+	session.ask("what is maestro?")
+	session.handleMetaCommand(":collection")
+	session.collection = ""
+	session.ask("fail")
+
+	path := filepath.Join(t.TempDir(), "transcript.md")
+	if err := session.handleMetaCommand(":save " + path); err != nil {
+		t.Fatalf(":save error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read transcript: %v", err)
+	}
+	transcript := string(data)
+	for _, want := range []string{
+		"# Maestro query session\n",
+		"## what is maestro?\n\n_vdb1/docs · limit 5 · ",
+		"answer to what is maestro?\n",
+		"## fail\n\n_vdb1 · limit 5 · ",
+		"> Error: no such collection\n",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript missing %q:\n%s", want, transcript)
+		}
+	}
+	if len(session.transcript) != 2 {
+		t.Errorf("transcript has %d entries, want 2", len(session.transcript))
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for re-ranking search results
+var (
+	rerankMode       string
+	rerankCandidates int
+	rerankWeight     float64
+)
+
+// Re-ranking modes
+const (
+	rerankBM25   = "bm25"
+	rerankHybrid = "hybrid"
+)
+
+// BM25 parameters: bm25K1 saturates the weight of repeated terms, bm25B normalizes for length
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// defaultRerankFactor is the number of candidates fetched per returned result when --candidates
+// is not set
+const defaultRerankFactor = 4
+
+// validateRerank checks the re-ranking flags and returns the number of candidates to fetch
+func validateRerank(cmd *cobra.Command, limit int) (int, error) {
+	if rerankMode == "" {
+		if cmd.Flags().Changed("candidates") || cmd.Flags().Changed("rerank-weight") {
+			return 0, fmt.Errorf("--candidates and --rerank-weight require --rerank")
+		}
+		return limit, nil
+	}
+	if rerankMode != rerankBM25 && rerankMode != rerankHybrid {
+		return 0, fmt.Errorf("unsupported rerank mode '%s' (use bm25 or hybrid)", rerankMode)
+	}
+	if rerankWeight < 0 || rerankWeight > 1 {
+		return 0, fmt.Errorf("--rerank-weight must be between 0 and 1")
+	}
+	if rerankCandidates == 0 {
+		return limit * defaultRerankFactor, nil
+	}
+	if rerankCandidates < limit {
+		return 0, fmt.Errorf("--candidates (%d) must be at least --doc-limit (%d)", rerankCandidates, limit)
+	}
+	return rerankCandidates, nil
+}
+
+// tokenizeForBM25 lowercases text and splits it into words of letters, digits and underscores.
+// Identifiers joined by '-', '.' or ':' such as ERR-1042 or v2.3.1 are also kept whole, so that
+// exact codes match as one rare term.
+func tokenizeForBM25(text string) []string {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' }
+	var tokens []string
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWord(r) && r != '-' && r != '.' && r != ':'
+	}) {
+		parts := strings.FieldsFunc(field, func(r rune) bool { return !isWord(r) })
+		tokens = append(tokens, parts...)
+		if len(parts) > 1 {
+			tokens = append(tokens, strings.Trim(field, "-.:"))
+		}
+	}
+	return tokens
+}
+
+// bm25Scores scores each document against the query with Okapi BM25, using the documents
+// themselves as the corpus for term frequencies and lengths
+func bm25Scores(query string, documents []string) []float64 {
+	docs := make([]map[string]int, len(documents))
+	lengths := make([]int, len(documents))
+	frequency := make(map[string]int)
+	total := 0
+	for i, document := range documents {
+		docs[i] = make(map[string]int)
+		for _, token := range tokenizeForBM25(document) {
+			docs[i][token]++
+			lengths[i]++
+		}
+		for token := range docs[i] {
+			frequency[token]++
+		}
+		total += lengths[i]
+	}
+
+	scores := make([]float64, len(documents))
+	if total == 0 {
+		return scores
+	}
+	avgLength := float64(total) / float64(len(documents))
+	seen := make(map[string]bool)
+	for _, term := range tokenizeForBM25(query) {
+		if seen[term] || frequency[term] == 0 {
+			continue
+		}
+		seen[term] = true
+		n := float64(frequency[term])
+		idf := math.Log(1 + (float64(len(documents))-n+0.5)/(n+0.5))
+		for i, doc := range docs {
+			if tf := float64(doc[term]); tf > 0 {
+				scores[i] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(lengths[i])/avgLength))
+			}
+		}
+	}
+	return scores
+}
+
+// rerankHits re-scores candidate hits locally and keeps the best limit. bm25 ranks by BM25 score
+// over the hit texts; hybrid blends the min-max normalized BM25 and vector scores, giving BM25 the
+// given weight. Each hit keeps its vector_score and gets bm25_score, rerank_score and a new rank.
+func rerankHits(query string, hits []SearchHit, mode string, weight float64, limit int) []SearchHit {
+	texts := make([]string, len(hits))
+	vector := make([]float64, len(hits))
+	for i, hit := range hits {
+		texts[i] = hit.Text()
+		if score, ok := hit.Similarity(); ok {
+			vector[i] = score
+		} else {
+			// without a similarity, fall back to the vector rank
+			vector[i] = 1 - float64(i)/float64(len(hits))
+		}
+	}
+	bm25 := bm25Scores(query, texts)
+
+	scores := bm25
+	if mode == rerankHybrid {
+		normalizedBM25, normalizedVector := minMaxNormalize(bm25), minMaxNormalize(vector)
+		scores = make([]float64, len(hits))
+		for i := range hits {
+			scores[i] = weight*normalizedBM25[i] + (1-weight)*normalizedVector[i]
+		}
+	}
+
+	order := make([]int, len(hits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if scores[order[a]] != scores[order[b]] {
+			return scores[order[a]] > scores[order[b]]
+		}
+		return vector[order[a]] > vector[order[b]]
+	})
+
+	results := []SearchHit{}
+	for _, i := range order {
+		if len(results) == limit {
+			break
+		}
+		hit := hits[i]
+		hit["vector_score"] = vector[i]
+		hit["bm25_score"] = bm25[i]
+		hit["rerank_score"] = scores[i]
+		hit["rank"] = len(results) + 1
+		results = append(results, hit)
+	}
+	return results
+}
+
+// minMaxNormalize scales values to [0, 1]; equal values all become 1, or 0 when they are all zero
+func minMaxNormalize(values []float64) []float64 {
+	normalized := make([]float64, len(values))
+	if len(values) == 0 {
+		return normalized
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	for i, v := range values {
+		switch {
+		case high > low:
+			normalized[i] = (v - low) / (high - low)
+		case high > 0:
+			normalized[i] = 1
+		}
+	}
+	return normalized
+}
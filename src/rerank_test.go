@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeForBM25(t *testing.T) {
+	tests := map[string][]string{
+		"Connection reset: ERR-1042 in v2.3.1": {"connection", "reset", "err", "1042", "err-1042", "in", "v2", "3", "1", "v2.3.1"},
+		"E_ACCESSDENIED (0x80070005).":         {"e_accessdenied", "0x80070005"},
+		"Größe über 10 MB":                     {"größe", "über", "10", "mb"},
+		"--- ... ":                             nil,
+	}
+	for text, want := range tests {
+		if got := tokenizeForBM25(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("tokenizeForBM25(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestBM25Scores(t *testing.T) {
+	documents := []string{
+		"the server returned error ERR-1042 while syncing",
+		"the server restarted after an update",
+		"sync errors are retried by the server the server the server",
+		"",
+	}
+	scores := bm25Scores("ERR-1042 server", documents)
+	if scores[0] <= scores[1] || scores[0] <= scores[2] {
+		t.Errorf("document with the exact code should score highest: %v", scores)
+	}
+	if scores[3] != 0 {
+		t.Errorf("empty document score = %v, want 0", scores[3])
+	}
+	// repeated terms saturate rather than grow linearly
+	if scores[2] >= 3*scores[1] {
+		t.Errorf("term frequency should saturate: %v", scores)
+	}
+
+	// a term in every document is worth less than a rare one
+	rare := bm25Scores("update", documents)[1]
+	common := bm25Scores("server", documents)[1]
+	if rare <= common {
+		t.Errorf("rare term score %v should exceed common term score %v", rare, common)
+	}
+
+	if scores := bm25Scores("anything", []string{"", ""}); scores[0] != 0 || scores[1] != 0 {
+		t.Errorf("scores of empty corpus = %v", scores)
+	}
+}
+
+func rerankCandidatesFixture() []SearchHit {
+	texts := []string{
+		"How to configure connection pooling",
+		"Troubleshooting timeouts and retries",
+		"Error ERR-1042 means the upstream closed the connection",
+		"Release notes for version 2",
+	}
+	hits := make([]SearchHit, len(texts))
+	for i, text := range texts {
+		hits[i] = SearchHit{"text": text, "similarity": 0.9 - 0.1*float64(i), "rank": i + 1}
+	}
+	return hits
+}
+
+func TestRerankHitsBM25(t *testing.T) {
+	results := rerankHits("ERR-1042", rerankCandidatesFixture(), rerankBM25, 0.5, 2)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Text() != "Error ERR-1042 means the upstream closed the connection" {
+		t.Errorf("top result = %q", results[0].Text())
+	}
+	// without keyword matches, ties keep the vector order
+	if results[1].Text() != "How to configure connection pooling" {
+		t.Errorf("second result = %q", results[1].Text())
+	}
+	if results[0]["rank"] != 1 || results[1]["rank"] != 2 {
+		t.Errorf("ranks = %v, %v", results[0]["rank"], results[1]["rank"])
+	}
+	if results[0]["vector_score"] != 0.7 || results[0]["bm25_score"].(float64) <= 0 || results[0]["rerank_score"] != results[0]["bm25_score"] {
+		t.Errorf("scores = vector %v, bm25 %v, rerank %v", results[0]["vector_score"], results[0]["bm25_score"], results[0]["rerank_score"])
+	}
+}
+
+func TestRerankHitsHybridWeight(t *testing.T) {
+	top := func(weight float64) string {
+		return rerankHits("connection ERR-1042", rerankCandidatesFixture(), rerankHybrid, weight, 1)[0].Text()
+	}
+	if got := top(0); got != "How to configure connection pooling" {
+		t.Errorf("weight 0 keeps the vector order, top = %q", got)
+	}
+	if got := top(1); got != "Error ERR-1042 means the upstream closed the connection" {
+		t.Errorf("weight 1 ranks by BM25, top = %q", got)
+	}
+
+	// the lowest BM25 score is 0, so normalized BM25 scores are relative to the highest
+	results := rerankHits("connection ERR-1042", rerankCandidatesFixture(), rerankHybrid, 0.5, 4)
+	for _, hit := range results {
+		vector := (hit["vector_score"].(float64) - 0.6) / 0.3
+		bm25 := hit["bm25_score"].(float64) / maxBM25(results)
+		if want := 0.5*bm25 + 0.5*vector; math.Abs(hit["rerank_score"].(float64)-want) > 1e-9 {
+			t.Errorf("%q rerank_score = %v, want %v", hit.Text(), hit["rerank_score"], want)
+		}
+	}
+}
+
+func maxBM25(hits []SearchHit) float64 {
+	best := 0.0
+	for _, hit := range hits {
+		best = math.Max(best, hit["bm25_score"].(float64))
+	}
+	return best
+}
+
+func TestMinMaxNormalize(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   []float64
+	}{
+		{[]float64{2, 4, 3}, []float64{0, 1, 0.5}},
+		{[]float64{0.7, 0.7}, []float64{1, 1}},
+		{[]float64{0, 0}, []float64{0, 0}},
+		{nil, []float64{}},
+	}
+	for _, tt := range tests {
+		if got := minMaxNormalize(tt.values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("minMaxNormalize(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CollectionInfo represents the configuration reported by the get_collection_info tool
+type CollectionInfo struct {
+	Name          string                 `json:"name"`
+	DocumentCount int                    `json:"document_count"`
+	DBType        string                 `json:"db_type,omitempty"`
+	Embedding     string                 `json:"embedding,omitempty"`
+	Chunking      map[string]interface{} `json:"chunking,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ChunkingConfig returns the chunking config in the shape expected by create_collection,
+// or nil when the collection does not use chunking
+func (ci *CollectionInfo) ChunkingConfig() map[string]interface{} {
+	if len(ci.Chunking) == 0 {
+		return nil
+	}
+	strategy, _ := ci.Chunking["strategy"].(string)
+	if strategy == "" || strategy == "None" {
+		return nil
+	}
+
+	params, _ := ci.Chunking["parameters"].(map[string]interface{})
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"strategy":   strategy,
+		"parameters": params,
+	}
+}
+
+// DocumentRecord represents a single document stored in a collection
+type DocumentRecord struct {
+	Name     string                 `json:"name"`
+	URL      string                 `json:"url,omitempty"`
+	Text     string                 `json:"text"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// extractJSONPayload decodes the JSON value embedded in an MCP result string.
+// Results are often prefixed with a human readable message, e.g. "Found 2 documents ...: [...]".
+func extractJSONPayload(result string) (interface{}, bool) {
+	result = strings.TrimSpace(result)
+
+	start := strings.IndexAny(result, "[{")
+	for start != -1 {
+		var payload interface{}
+		decoder := json.NewDecoder(strings.NewReader(result[start:]))
+		if err := decoder.Decode(&payload); err == nil {
+			return payload, true
+		}
+
+		// Skip brackets that are part of the message rather than the payload
+		next := strings.IndexAny(result[start+1:], "[{")
+		if next == -1 {
+			break
+		}
+		start += next + 1
+	}
+
+	return nil, false
+}
+
+// parseCollectionNames extracts collection names from a list_collections result
+func parseCollectionNames(result string) []string {
+	var names []string
+
+	if payload, ok := extractJSONPayload(result); ok {
+		if items, ok := payload.([]interface{}); ok {
+			for _, item := range items {
+				switch v := item.(type) {
+				case string:
+					names = append(names, v)
+				case map[string]interface{}:
+					if name := firstString(v, "name", "collection_name"); name != "" {
+						names = append(names, name)
+					}
+				}
+			}
+			return names
+		}
+	}
+
+	// Fall back to one collection per line, optionally numbered ("1. name")
+	for _, line := range strings.Split(strings.TrimSpace(result), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Found") || strings.HasPrefix(line, "Collections") || strings.HasPrefix(line, "No ") {
+			continue
+		}
+		if parts := strings.SplitN(line, ". ", 2); len(parts) == 2 {
+			line = parts[1]
+		}
+		names = append(names, strings.Trim(strings.TrimSpace(line), "\","))
+	}
+	return names
+}
+
+// parseDocumentList extracts documents from a list_documents_in_collection result.
+// Chunked documents may be reported once per chunk, so records are de-duplicated by name.
+func parseDocumentList(result string) []DocumentRecord {
+	var documents []DocumentRecord
+	seen := make(map[string]bool)
+	for _, doc := range parseDocumentEntries(result) {
+		if seen[doc.Name] {
+			continue
+		}
+		seen[doc.Name] = true
+		documents = append(documents, doc)
+	}
+	return documents
+}
+
+// parseDocumentEntries extracts every entry of a list_documents_in_collection result.
+// For chunked documents there is one entry per chunk, holding the chunk's text.
+func parseDocumentEntries(result string) []DocumentRecord {
+	payload, ok := extractJSONPayload(result)
+	if !ok {
+		return nil
+	}
+
+	var items []interface{}
+	switch v := payload.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		if docs, ok := v["documents"].([]interface{}); ok {
+			items = docs
+		}
+	}
+
+	var entries []DocumentRecord
+	for _, item := range items {
+		var doc DocumentRecord
+		switch v := item.(type) {
+		case string:
+			doc = DocumentRecord{Name: v}
+		case map[string]interface{}:
+			doc = documentFromMap(v)
+		}
+		if doc.Name == "" {
+			continue
+		}
+		entries = append(entries, doc)
+	}
+	return entries
+}
+
+// parseDocumentNames returns the sorted names of the documents in a list_documents_in_collection result
+func parseDocumentNames(result string) []string {
+	var names []string
+	for _, doc := range parseDocumentList(result) {
+		names = append(names, doc.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseDocumentRecord extracts a document from a get_document result
+func parseDocumentRecord(docName, result string) (*DocumentRecord, error) {
+	payload, ok := extractJSONPayload(result)
+	if !ok {
+		return nil, fmt.Errorf("unexpected get_document response for '%s'", docName)
+	}
+
+	fields, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected get_document response for '%s'", docName)
+	}
+	// Some servers wrap the document in a "document" field
+	if inner, ok := fields["document"].(map[string]interface{}); ok {
+		fields = inner
+	}
+
+	doc := documentFromMap(fields)
+	if doc.Name == "" {
+		doc.Name = docName
+	}
+	return &doc, nil
+}
+
+// parseCollectionInfo extracts collection configuration from a get_collection_info result
+func parseCollectionInfo(result string) (*CollectionInfo, error) {
+	payload, ok := extractJSONPayload(result)
+	if !ok {
+		return nil, fmt.Errorf("unexpected collection info response")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected collection info response: %w", err)
+	}
+
+	var info CollectionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse collection info: %w", err)
+	}
+	return &info, nil
+}
+
+// documentFromMap converts a loosely-typed document object into a DocumentRecord
+func documentFromMap(fields map[string]interface{}) DocumentRecord {
+	doc := DocumentRecord{
+		Text: firstString(fields, "text", "content"),
+		URL:  firstString(fields, "url", "source"),
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		doc.Metadata = metadata
+	}
+
+	doc.Name = firstString(fields, "doc_name", "name")
+	if doc.Name == "" && doc.Metadata != nil {
+		doc.Name = firstString(doc.Metadata, "doc_name")
+	}
+	if doc.Name == "" {
+		doc.Name = firstString(fields, "id")
+	}
+	return doc
+}
+
+// fetchDatabase looks up a vector database by name
+func fetchDatabase(client *MCPClient, serverURI, vdbName string) (*DatabaseInfo, error) {
+	var databases []DatabaseInfo
+	if err := safeCall(serverURI, func() error {
+		var listErr error
+		databases, listErr = client.ListDatabases()
+		return listErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list vector databases: %w", err)
+	}
+
+	for i := range databases {
+		if databases[i].Name == vdbName {
+			return &databases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("vector database '%s' does not exist", vdbName)
+}
+
+// fetchCollectionInfo retrieves and parses a collection's configuration
+func fetchCollectionInfo(client *MCPClient, serverURI, vdbName, collectionName string) (*CollectionInfo, error) {
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var infoErr error
+		result, infoErr = client.GetCollectionInfo(vdbName, collectionName)
+		return infoErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get collection info for '%s': %w", collectionName, err)
+	}
+
+	info, err := parseCollectionInfo(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration of collection '%s': %w", collectionName, err)
+	}
+	return info, nil
+}
+
+// fetchCollectionNames lists the collections of a vector database
+func fetchCollectionNames(client *MCPClient, serverURI, vdbName string) ([]string, error) {
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var listErr error
+		result, listErr = client.ListCollections(vdbName)
+		return listErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	return parseCollectionNames(result), nil
+}
+
+// ensureCollection returns an error unless the vector database and its collection exist
+func ensureCollection(client *MCPClient, serverURI, vdbName, collectionName string) error {
+	var exists bool
+	if err := safeCall(serverURI, func() error {
+		var existsErr error
+		exists, existsErr = client.DatabaseExists(vdbName)
+		return existsErr
+	}); err != nil {
+		return fmt.Errorf("failed to check if database exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("vector database '%s' does not exist. Please create it first", vdbName)
+	}
+
+	collections, err := fetchCollectionNames(client, serverURI, vdbName)
+	if err != nil {
+		return err
+	}
+	for _, name := range collections {
+		if name == collectionName {
+			return nil
+		}
+	}
+	return fmt.Errorf("collection '%s' does not exist in vector database '%s'. Please create it first", collectionName, vdbName)
+}
+
+// fetchDocumentEntries lists the entries of a collection, one per chunk for chunked documents
+func fetchDocumentEntries(client *MCPClient, serverURI, vdbName, collectionName string) ([]DocumentRecord, error) {
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var listErr error
+		result, listErr = client.ListDocumentsInCollection(vdbName, collectionName)
+		return listErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list documents in collection '%s': %w", collectionName, err)
+	}
+	return parseDocumentEntries(result), nil
+}
+
+// fetchDocumentNames lists the document names of a collection
+func fetchDocumentNames(client *MCPClient, serverURI, vdbName, collectionName string) ([]string, error) {
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var listErr error
+		result, listErr = client.ListDocumentsInCollection(vdbName, collectionName)
+		return listErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list documents in collection '%s': %w", collectionName, err)
+	}
+	return parseDocumentNames(result), nil
+}
+
+// fetchDocument retrieves and parses a single document
+func fetchDocument(client *MCPClient, serverURI, vdbName, collectionName, docName string) (*DocumentRecord, error) {
+	var result string
+	if err := safeCall(serverURI, func() error {
+		var getErr error
+		result, getErr = client.GetDocument(vdbName, collectionName, docName)
+		return getErr
+	}); err != nil {
+		return nil, err
+	}
+
+	doc, err := parseDocumentRecord(docName, result)
+	if err != nil {
+		return nil, err
+	}
+	doc.Name = docName
+	return doc, nil
+}
+
+// fetchDocuments retrieves documents concurrently, preserving the order of names.
+// Documents that could not be retrieved are reported as sorted failure messages.
+func fetchDocuments(client *MCPClient, serverURI, vdbName, collectionName string, names []string, concurrency int) ([]DocumentRecord, []string) {
+	progress := newBulkProgress(fmt.Sprintf("Reading %d document(s) from '%s'...", len(names), collectionName), len(names))
+
+	records := make([]*DocumentRecord, len(names))
+	var mu sync.Mutex
+	var failures []string
+	indexes := make([]int, len(names))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	runConcurrently(indexes, concurrency, func(i int) {
+		defer progress.Increment()
+		doc, err := fetchDocument(client, serverURI, vdbName, collectionName, names[i])
+		if err != nil {
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", names[i], err))
+			mu.Unlock()
+			return
+		}
+		records[i] = doc
+	})
+	progress.Stop(fmt.Sprintf("Read %d document(s)", len(names)-len(failures)), len(failures))
+
+	var documents []DocumentRecord
+	for _, doc := range records {
+		if doc != nil {
+			documents = append(documents, *doc)
+		}
+	}
+	sort.Strings(failures)
+	return documents, failures
+}
+
+// firstString returns the first non-empty string value among the given keys
+func firstString(fields map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCollectionNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"prefixed JSON array", "Collections in vector database 'my-db': [\n  \"Collection1\",\n  \"MaestroDocs\"\n]", []string{"Collection1", "MaestroDocs"}},
+		{"array of objects", `[{"name": "docs"}, {"collection_name": "wiki"}]`, []string{"docs", "wiki"}},
+		{"numbered lines", "Found 2 collections:\n1. docs\n2. wiki", []string{"docs", "wiki"}},
+	}
+
+	for _, tt := range tests {
+		result := parseCollectionNames(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: parseCollectionNames() = %v, expected %v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestParseDocumentNames(t *testing.T) {
+	input := `Found 3 documents in collection 'c' of vector database 'v': [
+  {"id": "1", "url": "a.txt", "text": "chunk 1", "metadata": {"doc_name": "doc-b", "chunk_sequence_number": 0}},
+  {"id": "2", "url": "a.txt", "text": "chunk 2", "metadata": {"doc_name": "doc-b", "chunk_sequence_number": 1}},
+  {"id": "3", "url": "b.txt", "text": "text", "metadata": {"doc_name": "doc-a"}}
+]`
+
+	result := parseDocumentNames(input)
+	expected := []string{"doc-a", "doc-b"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseDocumentNames() = %v, expected %v", result, expected)
+	}
+}
+
+func TestParseDocumentRecord(t *testing.T) {
+	input := `Document 'my-doc' from collection 'c': {"url": "data.txt", "text": "hello", "metadata": {"filename": "data.txt"}}`
+
+	doc, err := parseDocumentRecord("my-doc", input)
+	if err != nil {
+		t.Fatalf("parseDocumentRecord() returned error: %v", err)
+	}
+	if doc.Name != "my-doc" || doc.Text != "hello" || doc.URL != "data.txt" {
+		t.Errorf("parseDocumentRecord() = %+v", doc)
+	}
+	if doc.Metadata["filename"] != "data.txt" {
+		t.Errorf("Expected metadata filename 'data.txt', got %v", doc.Metadata["filename"])
+	}
+
+	if _, err := parseDocumentRecord("my-doc", "Document not found"); err == nil {
+		t.Error("parseDocumentRecord() should fail for a non-JSON response")
+	}
+}
+
+func TestParseCollectionInfo(t *testing.T) {
+	input := `Collection information for 'my-collection' in vector database 'my-database':
+{
+  "name": "my-collection",
+  "document_count": 15,
+  "db_type": "weaviate",
+  "embedding": "text2vec-weaviate",
+  "chunking": {"strategy": "Sentence", "parameters": {"chunk_size": 512, "overlap": 32}}
+}`
+
+	info, err := parseCollectionInfo(input)
+	if err != nil {
+		t.Fatalf("parseCollectionInfo() returned error: %v", err)
+	}
+	if info.Name != "my-collection" || info.DocumentCount != 15 || info.Embedding != "text2vec-weaviate" {
+		t.Errorf("parseCollectionInfo() = %+v", info)
+	}
+
+	chunking := info.ChunkingConfig()
+	if chunking["strategy"] != "Sentence" {
+		t.Errorf("Expected chunking strategy 'Sentence', got %v", chunking["strategy"])
+	}
+
+	info.Chunking = map[string]interface{}{"strategy": "None"}
+	if info.ChunkingConfig() != nil {
+		t.Error("ChunkingConfig() should be nil for the None strategy")
+	}
+}
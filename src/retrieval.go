@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags narrowing the results of search and query
+var (
+	whereClause string
+	minScore    float64
+	maxDistance float64
+)
+
+// filterOverfetch is the factor by which search results are over-fetched when the server cannot
+// apply a filter, so that enough hits remain after filtering them locally
+const filterOverfetch = 4
+
+// retrievalOptions are the metadata filter and score thresholds of a search or query
+type retrievalOptions struct {
+	Where          *whereExpr
+	MinScore       float64 // minimum similarity, 0 for none
+	MaxDistance    float64
+	HasMaxDistance bool
+}
+
+// addRetrievalFlags registers --where, --min-score and --max-distance
+func addRetrievalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&whereClause, "where", "", "Only return documents whose metadata matches, e.g. 'meta.product=foo AND meta.version>=2'")
+	cmd.Flags().Float64Var(&minScore, "min-score", 0, "Drop results with a similarity below this value (0-1)")
+	cmd.Flags().Float64Var(&maxDistance, "max-distance", 0, "Drop results with a distance above this value")
+}
+
+// retrievalOptionsFromFlags validates and compiles the retrieval flags of cmd
+func retrievalOptionsFromFlags(cmd *cobra.Command) (retrievalOptions, error) {
+	var opts retrievalOptions
+	if strings.TrimSpace(whereClause) != "" {
+		where, err := parseWhere(whereClause)
+		if err != nil {
+			return opts, err
+		}
+		opts.Where = where
+	}
+	if minScore < 0 || minScore > 1 {
+		return opts, fmt.Errorf("--min-score must be between 0 and 1")
+	}
+	opts.MinScore = minScore
+	if cmd.Flags().Changed("max-distance") {
+		if maxDistance < 0 {
+			return opts, fmt.Errorf("--max-distance cannot be negative")
+		}
+		opts.MaxDistance, opts.HasMaxDistance = maxDistance, true
+	}
+	return opts, nil
+}
+
+// IsZero reports whether the options leave results unchanged
+func (o retrievalOptions) IsZero() bool {
+	return o.Where == nil && o.MinScore == 0 && !o.HasMaxDistance
+}
+
+// String describes the options for dry runs and verbose output
+func (o retrievalOptions) String() string {
+	var parts []string
+	if o.Where != nil {
+		filter, _ := json.Marshal(o.Where.Filter())
+		parts = append(parts, "filter "+string(filter))
+	}
+	if o.MinScore > 0 {
+		parts = append(parts, fmt.Sprintf("min score %g", o.MinScore))
+	}
+	if o.HasMaxDistance {
+		parts = append(parts, fmt.Sprintf("max distance %g", o.MaxDistance))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// serverInput returns the options as inputs of the query tool
+func (o retrievalOptions) serverInput() map[string]interface{} {
+	input := make(map[string]interface{})
+	if o.Where != nil {
+		input["filter"] = o.Where.Filter()
+	}
+	if o.MinScore > 0 {
+		input["min_score"] = o.MinScore
+	}
+	if o.HasMaxDistance {
+		input["max_distance"] = o.MaxDistance
+	}
+	return input
+}
+
+// Accepts reports whether a hit passes the filter and thresholds. A hit without a similarity or
+// distance fails the corresponding threshold.
+func (o retrievalOptions) Accepts(hit SearchHit) bool {
+	if o.Where != nil && !o.Where.Matches(hit.Metadata()) {
+		return false
+	}
+	if o.MinScore > 0 {
+		if score, ok := hit.Similarity(); !ok || score < o.MinScore {
+			return false
+		}
+	}
+	if o.HasMaxDistance {
+		if distance, ok := hit.Distance(); !ok || distance > o.MaxDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchHit is one result of the search tool, kept as decoded so that fields specific to a
+// backend survive in the output
+type SearchHit map[string]interface{}
+
+// Metadata returns the document metadata of the hit
+func (h SearchHit) Metadata() map[string]interface{} {
+	metadata, _ := h["metadata"].(map[string]interface{})
+	return metadata
+}
+
+// DocName returns the name of the document the hit belongs to
+func (h SearchHit) DocName() string {
+	if name, ok := h.Metadata()["doc_name"].(string); ok {
+		return name
+	}
+	if url, ok := h["url"].(string); ok {
+		return url
+	}
+	return ""
+}
+
+// Text returns the text of the hit
+func (h SearchHit) Text() string {
+	text, _ := h["text"].(string)
+	return text
+}
+
+// Similarity returns the canonical score of the hit in [0, 1]
+func (h SearchHit) Similarity() (float64, bool) {
+	return metadataNumber(h["similarity"])
+}
+
+// Distance returns the distance of the hit as reported by the backend
+func (h SearchHit) Distance() (float64, bool) {
+	return metadataNumber(h["distance"])
+}
+
+// searchWithOptions runs a search narrowed by opts. The filter is sent to the server; when the
+// server rejects or ignores it, results are over-fetched and filtered locally instead.
+func searchWithOptions(client *MCPClient, dbName, query string, limit int, collectionName string, opts retrievalOptions) ([]SearchHit, error) {
+	var input map[string]interface{}
+	if opts.Where != nil {
+		input = map[string]interface{}{"filter": opts.Where.Filter()}
+	}
+	hits, err := client.SearchHits(dbName, query, limit, collectionName, input)
+	serverFiltered := true
+	if err != nil {
+		if opts.Where == nil || !isUnsupportedInputError(err, "filter") {
+			return nil, err
+		}
+		serverFiltered = false
+	} else if opts.Where != nil {
+		for _, hit := range hits {
+			if !opts.Where.Matches(hit.Metadata()) {
+				serverFiltered = false
+				break
+			}
+		}
+	}
+
+	if !serverFiltered {
+		if verbose {
+			fmt.Printf("Server does not support filters, filtering up to %d results locally\n", limit*filterOverfetch)
+		}
+		if hits, err = client.SearchHits(dbName, query, limit*filterOverfetch, collectionName, nil); err != nil {
+			return nil, err
+		}
+	}
+	return filterHits(hits, opts, limit), nil
+}
+
+// filterHits keeps the first limit hits accepted by opts, renumbering their ranks
+func filterHits(hits []SearchHit, opts retrievalOptions, limit int) []SearchHit {
+	kept := []SearchHit{}
+	for _, hit := range hits {
+		if len(kept) == limit {
+			break
+		}
+		if !opts.Accepts(hit) {
+			continue
+		}
+		if _, ok := hit["rank"]; ok {
+			hit["rank"] = len(kept) + 1
+		}
+		kept = append(kept, hit)
+	}
+	return kept
+}
+
+// queryWithOptions runs a query narrowed by opts. The options are only sent when the schema of the
+// server's query tool declares them, because servers may silently ignore unknown inputs; otherwise a
+// filtered search is run instead and the matching passages are returned.
+func queryWithOptions(client *MCPClient, dbName, query string, limit int, collectionName string, opts retrievalOptions) (string, error) {
+	if opts.IsZero() {
+		return client.Query(dbName, query, limit, collectionName)
+	}
+	input := opts.serverInput()
+	declared, err := client.ToolInputProperties("query")
+	if err != nil && verbose {
+		fmt.Printf("Could not read the query tool schema: %v\n", err)
+	}
+	if declaresInputs(declared, input) {
+		result, err := client.QueryWithOptions(dbName, query, limit, collectionName, input)
+		if err == nil || !(isUnsupportedInputError(err, "filter") || isUnsupportedInputError(err, "min_score") || isUnsupportedInputError(err, "max_distance")) {
+			return result, err
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Warning: the server does not support filtered queries, showing the matching passages instead")
+	hits, err := searchWithOptions(client, dbName, query, limit, collectionName, opts)
+	if err != nil {
+		return "", err
+	}
+	return formatHits(hits), nil
+}
+
+// declaresInputs reports whether every input name is among the declared tool properties
+func declaresInputs(declared map[string]bool, input map[string]interface{}) bool {
+	for name := range input {
+		if !declared[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnsupportedInputError reports whether err is the server rejecting the tool input name
+func isUnsupportedInputError(err error, name string) bool {
+	message := strings.ToLower(err.Error())
+	if !strings.Contains(message, name) {
+		return false
+	}
+	for _, hint := range []string{"unexpected keyword", "extra inputs", "extra_forbidden", "not permitted", "unknown", "unrecognized", "unsupported", "not supported"} {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatHits renders search hits as numbered passages with their score and distance
+func formatHits(hits []SearchHit) string {
+	if len(hits) == 0 {
+		return "No matching documents found"
+	}
+	var b strings.Builder
+	for i, hit := range hits {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d. %s", i+1, hit.DocName())
+		var scores []string
+		if score, ok := hit.Similarity(); ok {
+			scores = append(scores, fmt.Sprintf("score %.3f", score))
+		}
+		if distance, ok := hit.Distance(); ok {
+			scores = append(scores, fmt.Sprintf("distance %.3f", distance))
+		}
+		if len(scores) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(scores, ", "))
+		}
+		b.WriteString("\n")
+		for _, line := range strings.Split(strings.TrimSpace(hit.Text()), "\n") {
+			fmt.Fprintf(&b, "   %s\n", line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeRetrievalServer serves search and query tools backed by handler and records the inputs of
+// every call
+type fakeRetrievalServer struct {
+	mu     sync.Mutex
+	inputs []map[string]interface{}
+}
+
+func (f *fakeRetrievalServer) calls() []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]interface{}{}, f.inputs...)
+}
+
+// newFakeRetrievalClient starts an MCP server whose tools answer with handler and returns a
+// client connected to it
+func newFakeRetrievalClient(t *testing.T, handler func(tool string, input map[string]interface{}) (string, error)) (*MCPClient, *fakeRetrievalServer) {
+	t.Helper()
+	return newFakeRetrievalClientWithTools(t, []mcp.Tool{mcp.NewTool("search"), mcp.NewTool("query")}, handler)
+}
+
+// newFakeRetrievalClientWithTools is newFakeRetrievalClient with the tool definitions, and so the
+// input schemas, listed by the server
+func newFakeRetrievalClientWithTools(t *testing.T, tools []mcp.Tool, handler func(tool string, input map[string]interface{}) (string, error)) (*MCPClient, *fakeRetrievalServer) {
+	t.Helper()
+	fake := &fakeRetrievalServer{}
+	mcpServer := server.NewMCPServer("fake", "1.0")
+	for _, definition := range tools {
+		tool := definition.Name
+		mcpServer.AddTool(definition, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			input, _ := request.GetArguments()["input"].(map[string]interface{})
+			fake.mu.Lock()
+			fake.inputs = append(fake.inputs, input)
+			fake.mu.Unlock()
+			result, err := handler(tool, input)
+			if err != nil {
+				return mcp.NewToolResultText("Error: " + err.Error()), nil
+			}
+			return mcp.NewToolResultText(result), nil
+		})
+	}
+	testServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewMCPSessionClient(testServer.URL + "/mcp")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, fake
+}
+
+// fakeHits returns n hits ranked by decreasing similarity; even hits are product foo
+func fakeHits(n int) []SearchHit {
+	hits := make([]SearchHit, n)
+	for i := range hits {
+		product := "bar"
+		if i%2 == 0 {
+			product = "foo"
+		}
+		similarity := 0.95 - 0.1*float64(i)
+		hits[i] = SearchHit{
+			"id":         fmt.Sprintf("chunk-%d", i),
+			"text":       fmt.Sprintf("passage %d", i),
+			"metadata":   map[string]interface{}{"doc_name": fmt.Sprintf("doc%d.md", i), "product": product, "version": float64(i)},
+			"similarity": similarity,
+			"distance":   1 - similarity,
+			"rank":       i + 1,
+		}
+	}
+	return hits
+}
+
+func encodeHits(t *testing.T, hits []SearchHit) string {
+	data, err := json.Marshal(hits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func docNames(hits []SearchHit) string {
+	names := make([]string, len(hits))
+	for i, hit := range hits {
+		names[i] = hit.DocName()
+	}
+	return strings.Join(names, ",")
+}
+
+func mustParseWhere(t *testing.T, where string) *whereExpr {
+	t.Helper()
+	expr, err := parseWhere(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expr
+}
+
+func TestSearchWithOptionsServerFilters(t *testing.T) {
+	client, fake := newFakeRetrievalClient(t, func(tool string, input map[string]interface{}) (string, error) {
+		// a server that supports filters returns only matching hits
+		var hits []SearchHit
+		for _, hit := range fakeHits(10) {
+			if hit.Metadata()["product"] == "foo" {
+				hits = append(hits, hit)
+			}
+		}
+		return encodeHits(t, hits[:int(input["limit"].(float64))]), nil
+	})
+
+	opts := retrievalOptions{Where: mustParseWhere(t, "product=foo")}
+	hits, err := searchWithOptions(client, "vdb", "q", 3, "docs", opts)
+	if err != nil {
+		t.Fatalf("searchWithOptions() error = %v", err)
+	}
+	if got := docNames(hits); got != "doc0.md,doc2.md,doc4.md" {
+		t.Errorf("hits = %s", got)
+	}
+	calls := fake.calls()
+	if len(calls) != 1 {
+		t.Fatalf("server was called %d times, want 1", len(calls))
+	}
+	filter, _ := json.Marshal(calls[0]["filter"])
+	if string(filter) != `{"field":"product","op":"eq","value":"foo"}` {
+		t.Errorf("filter sent = %s", filter)
+	}
+}
+
+func TestSearchWithOptionsClientFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(input map[string]interface{}) (string, error)
+	}{
+		{"rejected", func(input map[string]interface{}) (string, error) {
+			if _, ok := input["filter"]; ok {
+				return "", fmt.Errorf("search() got an unexpected keyword argument 'filter'")
+			}
+			return "", nil
+		}},
+		{"ignored", func(input map[string]interface{}) (string, error) { return "", nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeRetrievalClient(t, func(tool string, input map[string]interface{}) (string, error) {
+				if _, err := tt.handler(input); err != nil {
+					return "", err
+				}
+				return encodeHits(t, fakeHits(int(input["limit"].(float64)))), nil
+			})
+
+			opts := retrievalOptions{Where: mustParseWhere(t, "product=foo AND version>=2"), MinScore: 0.5}
+			hits, err := searchWithOptions(client, "vdb", "q", 3, "docs", opts)
+			if err != nil {
+				t.Fatalf("searchWithOptions() error = %v", err)
+			}
+			// doc6 has similarity 0.35 and falls below --min-score
+			if got := docNames(hits); got != "doc2.md,doc4.md" {
+				t.Errorf("hits = %s", got)
+			}
+			if hits[0]["rank"] != 1 || hits[1]["rank"] != 2 {
+				t.Errorf("ranks = %v, %v, want 1, 2", hits[0]["rank"], hits[1]["rank"])
+			}
+
+			calls := fake.calls()
+			if len(calls) != 2 {
+				t.Fatalf("server was called %d times, want 2", len(calls))
+			}
+			if _, ok := calls[1]["filter"]; ok || calls[1]["limit"] != float64(3*filterOverfetch) {
+				t.Errorf("fallback call = %v, want an unfiltered search for %d results", calls[1], 3*filterOverfetch)
+			}
+		})
+	}
+}
+
+func TestSearchWithOptionsErrors(t *testing.T) {
+	client, _ := newFakeRetrievalClient(t, func(tool string, input map[string]interface{}) (string, error) {
+		return "", fmt.Errorf("collection 'docs' not found")
+	})
+	_, err := searchWithOptions(client, "vdb", "q", 3, "docs", retrievalOptions{Where: mustParseWhere(t, "a=1")})
+	if err == nil || !strings.Contains(err.Error(), "collection 'docs' not found") {
+		t.Errorf("searchWithOptions() error = %v, want the server error", err)
+	}
+}
+
+// queryToolSchema is the input schema a FastMCP server lists for a query tool with a pydantic input
+// model that accepts filter, min_score and max_distance
+const queryToolSchema = `{
+	"type": "object",
+	"properties": {"input": {"$ref": "#/$defs/QueryInput"}},
+	"required": ["input"],
+	"$defs": {
+		"QueryInput": {
+			"type": "object",
+			"properties": {
+				"db_name": {"type": "string"},
+				"query": {"type": "string"},
+				"limit": {"type": "integer"},
+				"collection_name": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+				"filter": {"anyOf": [{"type": "object"}, {"type": "null"}]},
+				"min_score": {"anyOf": [{"type": "number"}, {"type": "null"}]},
+				"max_distance": {"anyOf": [{"type": "number"}, {"type": "null"}]}
+			}
+		}
+	}
+}`
+
+func TestQueryWithOptions(t *testing.T) {
+	answer := func(tool string, input map[string]interface{}) (string, error) {
+		if tool == "query" {
+			return "The answer.", nil
+		}
+		return encodeHits(t, fakeHits(int(input["limit"].(float64)))), nil
+	}
+	tools := []mcp.Tool{mcp.NewTool("search"), mcp.NewToolWithRawSchema("query", "", json.RawMessage(queryToolSchema))}
+	client, fake := newFakeRetrievalClientWithTools(t, tools, answer)
+
+	opts := retrievalOptions{MinScore: 0.8, MaxDistance: 0.3, HasMaxDistance: true}
+	result, err := queryWithOptions(client, "vdb", "q", 3, "docs", opts)
+	if err != nil || result != "The answer." {
+		t.Fatalf("queryWithOptions() = %q, %v", result, err)
+	}
+	if calls := fake.calls(); calls[0]["min_score"] != 0.8 || calls[0]["max_distance"] != 0.3 {
+		t.Errorf("query input = %v, want min_score and max_distance", calls[0])
+	}
+
+	// a server that does not declare the options would silently ignore them
+	queried := false
+	client, _ = newFakeRetrievalClient(t, func(tool string, input map[string]interface{}) (string, error) {
+		queried = queried || tool == "query"
+		return answer(tool, input)
+	})
+	result, err = queryWithOptions(client, "vdb", "q", 3, "docs", opts)
+	if err != nil {
+		t.Fatalf("queryWithOptions() fallback error = %v", err)
+	}
+	want := "1. doc0.md (score 0.950, distance 0.050)\n   passage 0\n\n2. doc1.md (score 0.850, distance 0.150)\n   passage 1"
+	if result != want {
+		t.Errorf("fallback result =\n%s\nwant\n%s", result, want)
+	}
+	if queried {
+		t.Error("the query tool should not be called with undeclared options")
+	}
+}
+
+func TestSchemaInputProperties(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   []string
+	}{
+		{queryToolSchema, []string{"collection_name", "db_name", "filter", "limit", "max_distance", "min_score", "query"}},
+		{`{"type": "object", "properties": {"input": {"type": "object", "properties": {"query": {}, "filter": {}}}}}`, []string{"filter", "query"}},
+		{`{"properties": {"input": {"allOf": [{"$ref": "#/definitions/In"}]}}, "definitions": {"In": {"properties": {"query": {}}}}}`, []string{"query"}},
+		{`{"type": "object", "properties": {"input": {"$ref": "#/$defs/Missing"}}}`, nil},
+		{`{"type": "object"}`, nil},
+		{``, nil},
+	}
+	for _, tt := range tests {
+		properties := schemaInputProperties(json.RawMessage(tt.schema))
+		if got := sortedKeys(properties); !reflect.DeepEqual(got, tt.want) && (len(got) > 0 || len(tt.want) > 0) {
+			t.Errorf("schemaInputProperties(%s) = %v, want %v", tt.schema, got, tt.want)
+		}
+	}
+}
+
+func TestRetrievalOptionsAccepts(t *testing.T) {
+	hit := fakeHits(3)[2] // similarity 0.75, distance 0.25
+	tests := []struct {
+		opts retrievalOptions
+		want bool
+	}{
+		{retrievalOptions{}, true},
+		{retrievalOptions{MinScore: 0.75}, true},
+		{retrievalOptions{MinScore: 0.8}, false},
+		{retrievalOptions{MaxDistance: 0.25, HasMaxDistance: true}, true},
+		{retrievalOptions{MaxDistance: 0.2, HasMaxDistance: true}, false},
+		{retrievalOptions{Where: mustParseWhere(t, "product=foo")}, true},
+		{retrievalOptions{Where: mustParseWhere(t, "product=bar")}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.Accepts(hit); got != tt.want {
+			t.Errorf("%s: Accepts() = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+
+	unscored := SearchHit{"text": "no scores"}
+	if (retrievalOptions{MinScore: 0.1}).Accepts(unscored) || (retrievalOptions{HasMaxDistance: true, MaxDistance: 1}).Accepts(unscored) {
+		t.Error("hits without scores should fail thresholds")
+	}
+}
+
+func TestIsUnsupportedInputError(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"MCP server error: search() got an unexpected keyword argument 'filter'", true},
+		{"MCP server error: 1 validation error for input\nfilter\n  Extra inputs are not permitted", true},
+		{"MCP server error: filter is not supported by this backend", true},
+		{"MCP server error: collection 'filter' not found", false},
+		{"MCP server error: unknown collection", false},
+	}
+	for _, tt := range tests {
+		if got := isUnsupportedInputError(fmt.Errorf("%s", tt.message), "filter"); got != tt.want {
+			t.Errorf("isUnsupportedInputError(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
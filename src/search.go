@@ -13,9 +13,24 @@ var searchCmd = &cobra.Command{
 	Long: `Search documents in a vector database using natural language.
 
 This command allows you to ask questions about documents stored in a vector database.
-The query agent will search through the documents and return relevant documents.`,
+The query agent will search through the documents and return relevant documents.
+
+--where narrows the results to documents whose metadata matches an expression; comparisons
+(=, !=, >, >=, <, <=, IN (a, b)) combine with AND, OR, NOT and parentheses. --min-score and
+--max-distance drop weak hits. Filters the server cannot apply are applied to the results locally.
+
+--target VDB/COLLECTION (repeatable, wildcards allowed) searches several collections at once.
+The targets are searched concurrently and their results merged with reciprocal rank fusion;
+each hit is tagged with its source and the output lists the timing and error of every target.
+
+--rerank re-scores --candidates results locally before returning the best --doc-limit: bm25
+ranks them by keyword relevance of their text, which finds exact identifiers such as error codes,
+and hybrid blends the BM25 and vector scores, weighing BM25 by --rerank-weight.`,
 	Example: `  maestro search "What is the main topic of the documents?" --vdb=my-vdb
-  maestro search "Find information about API endpoints" --vdb=my-vdb --doc-limit 10`,
+  maestro search "Find information about API endpoints" --vdb=my-vdb --doc-limit 10
+  maestro search "release notes" --vdb=my-vdb --where 'meta.product=foo AND meta.version>=2' --min-score 0.5
+  maestro search "deployment guide" --target vdbA/docs --target vdbB/wiki --target 'vdbC/*'
+  maestro search "ERR_CONN_RESET 1042" --vdb=my-vdb --rerank hybrid --candidates 50 --rerank-weight 0.6`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := args[0]
@@ -26,10 +41,29 @@ The query agent will search through the documents and return relevant documents.
 		if strings.TrimSpace(query) == "" {
 			return fmt.Errorf("search cannot be empty")
 		}
+		opts, err := retrievalOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := validateRerank(cmd, docLimit)
+		if err != nil {
+			return err
+		}
+
+		if len(searchTargets) > 0 {
+			if vdbName != "" || collectionName != "" {
+				return fmt.Errorf("--target cannot be combined with --vdb or --collection")
+			}
+			if rerankMode != "" {
+				return fmt.Errorf("--rerank cannot be combined with --target")
+			}
+			cmd.SilenceUsage = true
+			return runFederatedSearch(query, opts)
+		}
 
 		// Use interactive selection if vdb name is not provided
 		if vdbName == "" {
-			var err error
 			vdbName, err = PromptForVectorDatabase(vdbName)
 			if err != nil {
 				return fmt.Errorf("failed to select vector database: %w", err)
@@ -40,7 +74,6 @@ The query agent will search through the documents and return relevant documents.
 		if collectionName == "" {
 			// If dry-run, don't interact; leave empty so downstream prints dry-run and exits
 			if !dryRun {
-				var err error
 				collectionName, err = PromptForCollection(vdbName, collectionName)
 				if err != nil {
 					return fmt.Errorf("failed to select collection: %w", err)
@@ -48,11 +81,11 @@ The query agent will search through the documents and return relevant documents.
 			}
 		}
 
-		return searchVectorDatabase(vdbName, query, collectionName)
+		return searchVectorDatabase(vdbName, query, collectionName, opts, candidates)
 	},
 }
 
-func searchVectorDatabase(dbName, query, collectionName string) error {
+func searchVectorDatabase(dbName, query, collectionName string, opts retrievalOptions, candidates int) error {
 	// Initialize progress indicator
 	var progress *ProgressIndicator
 	if ShouldShowProgress() {
@@ -68,7 +101,14 @@ func searchVectorDatabase(dbName, query, collectionName string) error {
 		if progress != nil {
 			progress.Stop("Dry run completed")
 		}
-		fmt.Println("[DRY RUN] Would search vector database")
+		if opts.IsZero() {
+			fmt.Println("[DRY RUN] Would search vector database")
+		} else {
+			fmt.Printf("[DRY RUN] Would search vector database with %s\n", opts)
+		}
+		if rerankMode != "" {
+			fmt.Printf("[DRY RUN] Would re-rank %d candidates with %s and return the best %d\n", candidates, rerankMode, docLimit)
+		}
 		return nil
 	}
 
@@ -103,6 +143,23 @@ func searchVectorDatabase(dbName, query, collectionName string) error {
 		progress.Update("Executing search...")
 	}
 
+	if !opts.IsZero() || rerankMode != "" {
+		hits, err := searchWithOptions(client, dbName, query, candidates, collectionName, opts)
+		if err != nil {
+			if progress != nil {
+				progress.StopWithError("Search failed")
+			}
+			return fmt.Errorf("failed to search vector database: %w", err)
+		}
+		if rerankMode != "" {
+			hits = rerankHits(query, hits, rerankMode, rerankWeight, docLimit)
+		}
+		if progress != nil {
+			progress.Stop("Search completed successfully")
+		}
+		return printJSON(hits)
+	}
+
 	// Call the search method
 	result, err := client.Search(dbName, query, docLimit, collectionName)
 	if err != nil {
@@ -125,4 +182,10 @@ func init() {
 	searchCmd.Flags().String("vdb", "", "Vector database name")
 	searchCmd.Flags().String("collection", "", "Collection name to search in")
 	searchCmd.Flags().IntVarP(&docLimit, "doc-limit", "d", 5, "Maximum number of documents to consider")
+	addRetrievalFlags(searchCmd)
+	searchCmd.Flags().StringArrayVar(&searchTargets, "target", nil, "Collection to search as VDB/COLLECTION, wildcards allowed (repeatable)")
+	searchCmd.Flags().IntVar(&searchConcurrency, "concurrency", defaultConcurrency, "Number of targets searched in parallel")
+	searchCmd.Flags().StringVar(&rerankMode, "rerank", "", "Re-rank the results locally (bm25, hybrid)")
+	searchCmd.Flags().IntVar(&rerankCandidates, "candidates", 0, "Number of results fetched for re-ranking (default 4x --doc-limit)")
+	searchCmd.Flags().Float64Var(&rerankWeight, "rerank-weight", 0.5, "Weight of the BM25 score in hybrid re-ranking (0-1)")
 }
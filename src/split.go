@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// Metadata keys recorded on the parts of a split document
+const (
+	parentMetadataKey = "parent"
+	partMetadataKey   = "part"
+	partsMetadataKey  = "parts"
+)
+
+// documentPartSeparator separates a document name from its part number, as in guide.md#part-3
+const documentPartSeparator = "#part-"
+
+// Defaults for document size limits
+const (
+	defaultMaxDocumentSize = "16MiB"
+	defaultPartSize        = "1MiB"
+)
+
+// Flags for document size limits and splitting
+var (
+	maxDocumentSizeFlag string
+	splitBy             string
+	partSizeFlag        string
+)
+
+// Document size limits in bytes, set by loadSizeLimits; a zero maxDocumentBytes disables the check
+var (
+	maxDocumentBytes int64
+	partBytes        int64
+)
+
+// addMaxSizeFlag adds --max-size to a command that writes documents
+func addMaxSizeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&maxDocumentSizeFlag, "max-size", defaultMaxDocumentSize, "Largest document text to send, e.g. 512KiB or 16MiB (0 for no limit)")
+}
+
+// addSplitFlags adds --max-size, --split-by and --part-size to a command that writes one document
+func addSplitFlags(cmd *cobra.Command) {
+	addMaxSizeFlag(cmd)
+	cmd.Flags().StringVar(&splitBy, "split-by", "", "Split a document larger than --max-size into parts (heading, size)")
+	cmd.Flags().StringVar(&partSizeFlag, "part-size", defaultPartSize, "Largest part written with --split-by")
+}
+
+// loadSizeLimits parses --max-size and --part-size and validates --split-by
+func loadSizeLimits() error {
+	var err error
+	if maxDocumentBytes, err = parseByteSize(maxDocumentSizeFlag); err != nil {
+		return fmt.Errorf("invalid --max-size: %w", err)
+	}
+	if splitBy == "" {
+		return nil
+	}
+	if splitBy != "heading" && splitBy != "size" {
+		return fmt.Errorf("unsupported --split-by '%s' (use heading or size)", splitBy)
+	}
+	if partBytes, err = parseByteSize(partSizeFlag); err != nil {
+		return fmt.Errorf("invalid --part-size: %w", err)
+	}
+	if partBytes == 0 {
+		return fmt.Errorf("--part-size must be larger than 0")
+	}
+	if maxDocumentBytes > 0 && partBytes > maxDocumentBytes {
+		return fmt.Errorf("--part-size %s is larger than --max-size %s", formatBytes(partBytes), formatBytes(maxDocumentBytes))
+	}
+	return nil
+}
+
+var byteSizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMG]?)(?:I?B)?$`)
+
+// parseByteSize parses sizes such as 800, 512KB, 512KiB, 16M or 1.5GiB; units are powers of 1024
+// as in formatBytes
+func parseByteSize(size string) (int64, error) {
+	match := byteSizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(size)))
+	if match == nil {
+		return 0, fmt.Errorf("'%s' is not a size (use a number of bytes or KiB, MiB, GiB)", size)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a size: %w", size, err)
+	}
+	multiplier := map[string]float64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30}[match[2]]
+	return int64(value * multiplier), nil
+}
+
+// checkDocumentSize rejects a document larger than --max-size unless it is going to be split
+func checkDocumentSize(docName, text string) error {
+	if maxDocumentBytes == 0 || int64(len(text)) <= maxDocumentBytes || splitBy != "" {
+		return nil
+	}
+	return fmt.Errorf("document '%s' is %s, larger than the %s limit (raise --max-size or use --split-by heading|size)", docName, formatBytes(int64(len(text))), formatBytes(maxDocumentBytes))
+}
+
+// shouldSplitDocument reports whether a document is split into parts instead of written whole
+func shouldSplitDocument(text string) bool {
+	return splitBy != "" && maxDocumentBytes > 0 && int64(len(text)) > maxDocumentBytes
+}
+
+// describeSplit describes --split-by in dry-run messages
+func describeSplit() string {
+	if splitBy == "" || maxDocumentBytes == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", split by %s into parts of up to %s if larger than %s", splitBy, formatBytes(partBytes), formatBytes(maxDocumentBytes))
+}
+
+// documentPartName returns the name of the part-th part of a document, counting from 1
+func documentPartName(docName string, part int) string {
+	return fmt.Sprintf("%s%s%d", docName, documentPartSeparator, part)
+}
+
+// parseDocumentPartName splits a part name into its document name and part number
+func parseDocumentPartName(name string) (string, int, bool) {
+	i := strings.LastIndex(name, documentPartSeparator)
+	if i <= 0 {
+		return "", 0, false
+	}
+	part, err := strconv.Atoi(name[i+len(documentPartSeparator):])
+	if err != nil || part < 1 || documentPartName(name[:i], part) != name {
+		return "", 0, false
+	}
+	return name[:i], part, true
+}
+
+// documentPartNames returns the parts of docName among names, in part order
+func documentPartNames(names []string, docName string) []string {
+	numbers := make(map[string]int)
+	var parts []string
+	for _, name := range names {
+		if parent, part, ok := parseDocumentPartName(name); ok && parent == docName {
+			numbers[name] = part
+			parts = append(parts, name)
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return numbers[parts[i]] < numbers[parts[j]] })
+	return parts
+}
+
+var markdownHeadingPattern = regexp.MustCompile(`^#{1,6}(\s|$)`)
+
+// splitDocument splits text into parts of at most size bytes. With "heading", parts start at Markdown
+// headings where possible; sections too large for one part, and all text with "size", are split at
+// paragraph, line, word and then character boundaries.
+func splitDocument(text, mode string, size int) []string {
+	if mode == "heading" {
+		return packSegments(markdownSections(text), size)
+	}
+	return splitBySize(text, size)
+}
+
+// markdownSections splits Markdown into sections that each start at a heading outside code fences
+func markdownSections(text string) []string {
+	var sections []string
+	var current strings.Builder
+	inFence := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && markdownHeadingPattern.MatchString(line) && current.Len() > 0 {
+			sections = append(sections, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		sections = append(sections, current.String())
+	}
+	return sections
+}
+
+// splitBySize splits text at paragraph, line, word and then character boundaries
+func splitBySize(text string, size int) []string {
+	if len(text) <= size {
+		return []string{text}
+	}
+	for _, separator := range []string{"\n\n", "\n", " "} {
+		if segments := splitAfterSeparator(text, separator); len(segments) > 1 {
+			return packSegments(segments, size)
+		}
+	}
+
+	var parts []string
+	for len(text) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = size
+		}
+		parts = append(parts, text[:cut])
+		text = text[cut:]
+	}
+	return append(parts, text)
+}
+
+// splitAfterSeparator splits text after each separator without an empty trailing segment
+func splitAfterSeparator(text, separator string) []string {
+	segments := strings.SplitAfter(text, separator)
+	if n := len(segments); n > 1 && segments[n-1] == "" {
+		segments = segments[:n-1]
+	}
+	return segments
+}
+
+// packSegments joins consecutive segments into parts of at most size bytes, splitting segments that
+// are larger than a part on their own
+func packSegments(segments []string, size int) []string {
+	var parts []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+		}
+		current.Reset()
+	}
+	for _, segment := range segments {
+		if len(segment) > size {
+			flush()
+			parts = append(parts, splitBySize(segment, size)...)
+			continue
+		}
+		if current.Len()+len(segment) > size {
+			flush()
+		}
+		current.WriteString(segment)
+	}
+	flush()
+	return parts
+}
+
+// writeDocumentParts splits a document and writes its parts, removing the parts already written
+// when one fails. It returns the number of parts.
+func writeDocumentParts(client *MCPClient, serverURI, vdbName, collectionName, docName string, extracted *ExtractedText, user map[string]interface{}) (int, error) {
+	parts := splitDocument(extracted.Text, splitBy, int(partBytes))
+	source := extracted.URL
+	if source == "" {
+		source = extracted.Path
+	}
+
+	progress := newBulkProgress(fmt.Sprintf("Writing %d part(s) of '%s'...", len(parts), docName), len(parts))
+	for i, text := range parts {
+		metadata := documentMetadata(extracted, user)
+		metadata[parentMetadataKey] = docName
+		metadata[partMetadataKey] = i + 1
+		metadata[partsMetadataKey] = len(parts)
+
+		partName := documentPartName(docName, i+1)
+		if err := writeDocumentText(client, serverURI, vdbName, collectionName, partName, text, source, metadata); err != nil {
+			progress.Stop(fmt.Sprintf("Failed to write part %d of %d", i+1, len(parts)), 1)
+			for j := i; j >= 1; j-- {
+				if cleanupErr := deleteDocumentName(client, serverURI, vdbName, collectionName, documentPartName(docName, j)); cleanupErr != nil && verbose {
+					fmt.Printf("Warning: failed to remove part %d of '%s': %v\n", j, docName, cleanupErr)
+				}
+			}
+			return 0, fmt.Errorf("failed to write part %d of %d: %w", i+1, len(parts), err)
+		}
+		progress.Increment()
+	}
+	progress.Stop(fmt.Sprintf("Wrote %d part(s)", len(parts)), 0)
+	return len(parts), nil
+}
+
+// joinDocumentParts combines the parts of a split document, in part order, into one document
+func joinDocumentParts(docName string, parts []DocumentRecord) *DocumentRecord {
+	sort.SliceStable(parts, func(i, j int) bool {
+		_, a, _ := parseDocumentPartName(parts[i].Name)
+		_, b, _ := parseDocumentPartName(parts[j].Name)
+		return a < b
+	})
+
+	doc := &DocumentRecord{Name: docName}
+	var text strings.Builder
+	for i, part := range parts {
+		if i == 0 {
+			doc.URL = part.URL
+			doc.Metadata = copyMetadata(part.Metadata)
+			delete(doc.Metadata, parentMetadataKey)
+			delete(doc.Metadata, partMetadataKey)
+			if doc.Metadata != nil {
+				doc.Metadata[partsMetadataKey] = len(parts)
+			}
+		}
+		text.WriteString(part.Text)
+	}
+	doc.Text = text.String()
+	return doc
+}
+
+// groupDocumentParts joins the parts of split documents, keeping other documents as they are.
+// A joined document takes the place of its first part; a document missing some of the parts recorded
+// in their metadata is left out and reported as a failure.
+func groupDocumentParts(documents []DocumentRecord) ([]DocumentRecord, []string) {
+	parts := make(map[string][]DocumentRecord)
+	for _, doc := range documents {
+		if parent, _, ok := parseDocumentPartName(doc.Name); ok {
+			parts[parent] = append(parts[parent], doc)
+		}
+	}
+
+	var grouped []DocumentRecord
+	var failures []string
+	for _, doc := range documents {
+		parent, _, ok := parseDocumentPartName(doc.Name)
+		if !ok {
+			grouped = append(grouped, doc)
+			continue
+		}
+		docParts, pending := parts[parent]
+		if !pending {
+			continue
+		}
+		delete(parts, parent)
+		if expected := fmt.Sprint(docParts[0].Metadata[partsMetadataKey]); expected != "<nil>" && expected != strconv.Itoa(len(docParts)) {
+			failures = append(failures, fmt.Sprintf("%s: only %d of %s part(s) could be read", parent, len(docParts), expected))
+			continue
+		}
+		grouped = append(grouped, *joinDocumentParts(parent, docParts))
+	}
+	return grouped, failures
+}
+
+// logicalDocumentNames returns names with the parts of each split document replaced by its name
+func logicalDocumentNames(names []string) []string {
+	seen := make(map[string]bool)
+	var logical []string
+	for _, name := range names {
+		if parent, _, ok := parseDocumentPartName(name); ok {
+			name = parent
+		}
+		if !seen[name] {
+			seen[name] = true
+			logical = append(logical, name)
+		}
+	}
+	return logical
+}
+
+// fetchSplitDocument retrieves a split document by joining its parts; it returns nil when the
+// collection has no parts of docName
+func fetchSplitDocument(client *MCPClient, serverURI, vdbName, collectionName, docName string) (*DocumentRecord, error) {
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	partNames := documentPartNames(names, docName)
+	if len(partNames) == 0 {
+		return nil, nil
+	}
+	parts, failures := fetchDocuments(client, serverURI, vdbName, collectionName, partNames, defaultConcurrency)
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("failed to read %d of %d part(s): %s", len(failures), len(partNames), strings.Join(failures, "; "))
+	}
+	grouped, incomplete := groupDocumentParts(parts)
+	if len(incomplete) > 0 {
+		return nil, fmt.Errorf("split document is incomplete: %s", strings.Join(incomplete, "; "))
+	}
+	return &grouped[0], nil
+}
+
+// deleteDocumentParts deletes every part of a split document and returns the number of parts
+func deleteDocumentParts(vdbName, collectionName, docName string) (int, error) {
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return 0, err
+	}
+	parts := documentPartNames(names, docName)
+	if len(parts) == 0 {
+		return 0, nil
+	}
+	report := deleteDocumentNames(client, serverURI, vdbName, collectionName, parts, defaultConcurrency)
+	if len(report.Failures) > 0 {
+		return len(report.Deleted), fmt.Errorf("%d of %d part(s) of document '%s' could not be deleted: %s", len(report.Failures), len(parts), docName, strings.Join(report.Failures, "; "))
+	}
+	return len(parts), nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"800":     800,
+		"0":       0,
+		"512KB":   512 << 10,
+		"512KiB":  512 << 10,
+		"16m":     16 << 20,
+		"1.5 GiB": 3 << 29,
+	}
+	for input, expected := range tests {
+		got, err := parseByteSize(input)
+		if err != nil || got != expected {
+			t.Errorf("parseByteSize(%q) = %d, %v; expected %d", input, got, err, expected)
+		}
+	}
+	for _, invalid := range []string{"", "MB", "-1", "12TB", "ten"} {
+		if _, err := parseByteSize(invalid); err == nil {
+			t.Errorf("parseByteSize(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestDocumentPartName(t *testing.T) {
+	if name := documentPartName("guide.md", 3); name != "guide.md#part-3" {
+		t.Errorf("documentPartName() = %q", name)
+	}
+	if parent, part, ok := parseDocumentPartName("docs/guide.md#part-12"); !ok || parent != "docs/guide.md" || part != 12 {
+		t.Errorf("parseDocumentPartName() = %q, %d, %v", parent, part, ok)
+	}
+	for _, name := range []string{"guide.md", "#part-1", "guide#part-0", "guide#part-01", "guide#part-x"} {
+		if _, _, ok := parseDocumentPartName(name); ok {
+			t.Errorf("parseDocumentPartName(%q) should not be a part", name)
+		}
+	}
+
+	names := []string{"guide#part-10", "other#part-1", "guide", "guide#part-2", "guide#part-1"}
+	expected := []string{"guide#part-1", "guide#part-2", "guide#part-10"}
+	if parts := documentPartNames(names, "guide"); !reflect.DeepEqual(parts, expected) {
+		t.Errorf("documentPartNames() = %v, expected %v", parts, expected)
+	}
+}
+
+func TestSplitDocumentByHeading(t *testing.T) {
+	text := "# Intro\nHello.\n\n## Install\nRun it.\n```\n# not a heading\n```\n## Usage\nUse it.\n"
+	parts := splitDocument(text, "heading", 50)
+	expected := []string{"# Intro\nHello.\n\n", "## Install\nRun it.\n```\n# not a heading\n```\n", "## Usage\nUse it.\n"}
+	if !reflect.DeepEqual(parts, expected) {
+		t.Errorf("splitDocument() = %q, expected %q", parts, expected)
+	}
+
+	// Small sections are packed into one part
+	if parts := splitDocument(text, "heading", 1000); len(parts) != 1 || parts[0] != text {
+		t.Errorf("splitDocument() with a large part size = %q", parts)
+	}
+}
+
+func TestSplitDocumentBySize(t *testing.T) {
+	text := strings.Repeat("word ", 10) + "\n\n" + strings.Repeat("é", 30)
+	parts := splitDocument(text, "size", 16)
+	if strings.Join(parts, "") != text {
+		t.Fatalf("parts do not add up to the text: %q", parts)
+	}
+	for _, part := range parts {
+		if len(part) > 16 {
+			t.Errorf("part %q is larger than 16 bytes", part)
+		}
+		if !strings.HasPrefix(part, "word") && !strings.HasPrefix(part, "é") && !strings.HasPrefix(part, "\n") {
+			t.Errorf("part %q does not start at a boundary", part)
+		}
+	}
+}
+
+func TestCheckDocumentSize(t *testing.T) {
+	defer func() { maxDocumentBytes, splitBy = 0, "" }()
+
+	maxDocumentBytes, splitBy = 10, ""
+	if err := checkDocumentSize("big", strings.Repeat("x", 11)); err == nil || !strings.Contains(err.Error(), "larger than the 10 B limit") {
+		t.Errorf("checkDocumentSize() error = %v", err)
+	}
+	if err := checkDocumentSize("small", "0123456789"); err != nil {
+		t.Errorf("checkDocumentSize() error = %v", err)
+	}
+
+	splitBy = "size"
+	if err := checkDocumentSize("big", strings.Repeat("x", 11)); err != nil {
+		t.Errorf("checkDocumentSize() with --split-by error = %v", err)
+	}
+	if !shouldSplitDocument(strings.Repeat("x", 11)) || shouldSplitDocument("small") {
+		t.Error("shouldSplitDocument() should only split documents over the limit")
+	}
+}
+
+func TestGroupDocumentParts(t *testing.T) {
+	documents := []DocumentRecord{
+		{Name: "a"},
+		{Name: "guide#part-2", Text: "two", Metadata: map[string]interface{}{"parent": "guide", "part": float64(2), "parts": float64(2), "owner": "docs"}},
+		{Name: "guide#part-1", Text: "one ", URL: "guide.md", Metadata: map[string]interface{}{"parent": "guide", "part": float64(1), "parts": float64(2), "owner": "docs"}},
+		{Name: "broken#part-1", Text: "x", Metadata: map[string]interface{}{"parts": float64(3)}},
+		{Name: "z"},
+	}
+
+	grouped, failures := groupDocumentParts(documents)
+	if len(grouped) != 3 || grouped[0].Name != "a" || grouped[2].Name != "z" {
+		t.Fatalf("groupDocumentParts() = %+v", grouped)
+	}
+	guide := grouped[1]
+	expectedMetadata := map[string]interface{}{"parts": 2, "owner": "docs"}
+	if guide.Name != "guide" || guide.Text != "one two" || guide.URL != "guide.md" || !reflect.DeepEqual(guide.Metadata, expectedMetadata) {
+		t.Errorf("joined document = %+v", guide)
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0], "broken: only 1 of 3 part(s)") {
+		t.Errorf("failures = %v", failures)
+	}
+
+	if names := logicalDocumentNames([]string{"a", "guide#part-1", "guide#part-2", "z"}); !reflect.DeepEqual(names, []string{"a", "guide", "z"}) {
+		t.Errorf("logicalDocumentNames() = %v", names)
+	}
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// CollectionStats summarizes the content of a collection. Sizes are measured in characters.
+type CollectionStats struct {
+	Database          string                 `json:"database" yaml:"database"`
+	Collection        string                 `json:"collection" yaml:"collection"`
+	Embedding         string                 `json:"embedding,omitempty" yaml:"embedding,omitempty"`
+	Chunking          string                 `json:"chunking" yaml:"chunking"`
+	DocumentCount     int                    `json:"document_count" yaml:"document_count"`
+	ChunkCount        int                    `json:"chunk_count" yaml:"chunk_count"`
+	ChunksPerDocument SizeSummary            `json:"chunks_per_document" yaml:"chunks_per_document"`
+	ChunkLength       *SizeSummary           `json:"chunk_length,omitempty" yaml:"chunk_length,omitempty"`
+	ChunkHistogram    []ChunkHistogramBucket `json:"chunk_length_histogram,omitempty" yaml:"chunk_length_histogram,omitempty"`
+	SampledDocuments  int                    `json:"sampled_documents" yaml:"sampled_documents"`
+	TotalSize         int                    `json:"total_size" yaml:"total_size"`
+	TotalSizeEstimate bool                   `json:"total_size_estimated" yaml:"total_size_estimated"`
+	DocumentSize      SizeSummary            `json:"document_size" yaml:"document_size"`
+	MetadataCoverage  []MetadataCoverage     `json:"metadata_coverage" yaml:"metadata_coverage"`
+	LargestDocuments  []DocumentSize         `json:"largest_documents" yaml:"largest_documents"`
+	Duplicates        []DuplicateGroup       `json:"duplicates" yaml:"duplicates"`
+}
+
+// SizeSummary holds the minimum, maximum and mean of a set of sizes
+type SizeSummary struct {
+	Min  int     `json:"min" yaml:"min"`
+	Max  int     `json:"max" yaml:"max"`
+	Mean float64 `json:"mean" yaml:"mean"`
+}
+
+// MetadataCoverage reports how many sampled documents have a metadata key
+type MetadataCoverage struct {
+	Key       string  `json:"key" yaml:"key"`
+	Documents int     `json:"documents" yaml:"documents"`
+	Percent   float64 `json:"percent" yaml:"percent"`
+}
+
+// DocumentSize is the size and chunk count of one document
+type DocumentSize struct {
+	Name   string `json:"name" yaml:"name"`
+	Size   int    `json:"size" yaml:"size"`
+	Chunks int    `json:"chunks" yaml:"chunks"`
+}
+
+// DuplicateGroup lists documents with identical content
+type DuplicateGroup struct {
+	Hash      string   `json:"sha256" yaml:"sha256"`
+	Documents []string `json:"documents" yaml:"documents"`
+}
+
+// Flags for collection stats
+var (
+	statsSample      int
+	statsTop         int
+	statsOutput      string
+	statsConcurrency int
+)
+
+var collectionStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show collection statistics",
+	Long: `Profile the content of a collection: document and chunk counts, document sizes, the chunk-length
+distribution, metadata key coverage, the largest documents and documents with duplicate content.
+
+Chunk counts and lengths come from the document listing. Document sizes, metadata and duplicates
+require fetching each document; use --sample to fetch only N evenly spaced documents of a large
+collection, in which case the total size is estimated from the sample.`,
+	Example: `  maestro collection stats --vdb=my-vdb --name=my-collection
+  maestro collection stats --vdb=my-vdb --name=my-collection --sample=100
+  maestro collection stats --vdb=my-vdb --name=my-collection -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("name")
+
+		// Interactive selection if missing
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return showCollectionStats(vdbName, collectionName)
+	},
+}
+
+func init() {
+	collectionStatsCmd.Flags().String("vdb", "", "Vector database name")
+	collectionStatsCmd.Flags().String("name", "", "Collection name")
+	collectionStatsCmd.Flags().IntVar(&statsSample, "sample", 0, "Fetch only N documents for size, metadata and duplicate statistics (0 = all)")
+	collectionStatsCmd.Flags().IntVar(&statsTop, "top", 5, "Number of largest documents to show")
+	collectionStatsCmd.Flags().StringVarP(&statsOutput, "output", "o", "text", "Output format (text, json, yaml)")
+	collectionStatsCmd.Flags().IntVar(&statsConcurrency, "concurrency", defaultConcurrency, "Number of documents to fetch in parallel")
+}
+
+func showCollectionStats(vdbName, collectionName string) error {
+	if statsOutput != "text" && statsOutput != "json" && statsOutput != "yaml" {
+		return fmt.Errorf("unsupported output format '%s' (use text, json or yaml)", statsOutput)
+	}
+	if statsSample < 0 {
+		return fmt.Errorf("--sample must not be negative")
+	}
+
+	if verbose {
+		fmt.Printf("Computing statistics for collection '%s' in vector database '%s'...\n", collectionName, vdbName)
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would compute statistics for collection '%s' in vector database '%s'\n", collectionName, vdbName)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var exists bool
+	if err := safeCall(serverURI, func() error {
+		var existsErr error
+		exists, existsErr = client.DatabaseExists(vdbName)
+		return existsErr
+	}); err != nil {
+		return fmt.Errorf("failed to check if database exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("vector database '%s' does not exist. Please create it first", vdbName)
+	}
+
+	info, err := fetchCollectionInfo(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+
+	names := sampleNames(uniqueEntryNames(entries), statsSample)
+	documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, names, statsConcurrency)
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+		}
+		return fmt.Errorf("failed to read %d of %d document(s) from collection '%s'", len(failures), len(names), collectionName)
+	}
+
+	stats := computeCollectionStats(entries, documents, statsTop)
+	stats.Database = vdbName
+	stats.Collection = collectionName
+	stats.Embedding = info.Embedding
+	stats.Chunking = describeChunkingConfig(info.ChunkingConfig())
+
+	switch statsOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	case "yaml":
+		data, err := yaml.Marshal(stats)
+		if err != nil {
+			return fmt.Errorf("failed to encode statistics: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if !silent {
+		printCollectionStats(stats)
+	}
+	return nil
+}
+
+// uniqueEntryNames returns the sorted document names of listing entries
+func uniqueEntryNames(entries []DocumentRecord) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range entries {
+		if !seen[entry.Name] {
+			seen[entry.Name] = true
+			names = append(names, entry.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sampleNames picks n evenly spaced names, or all names when n is zero or not smaller than the total
+func sampleNames(names []string, n int) []string {
+	if n <= 0 || n >= len(names) {
+		return names
+	}
+	sample := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sample = append(sample, names[i*len(names)/n])
+	}
+	return sample
+}
+
+// computeCollectionStats derives statistics from the listing entries and the fetched documents
+func computeCollectionStats(entries []DocumentRecord, documents []DocumentRecord, top int) *CollectionStats {
+	stats := &CollectionStats{
+		MetadataCoverage: []MetadataCoverage{},
+		LargestDocuments: []DocumentSize{},
+		Duplicates:       []DuplicateGroup{},
+	}
+
+	// Chunk counts and lengths from the listing
+	chunksPerDoc := make(map[string]int)
+	var chunkLengths []int
+	for _, entry := range entries {
+		chunksPerDoc[entry.Name]++
+		if entry.Text != "" {
+			chunkLengths = append(chunkLengths, utf8.RuneCountInString(entry.Text))
+		}
+	}
+	stats.DocumentCount = len(chunksPerDoc)
+	stats.ChunkCount = len(entries)
+
+	var perDoc []int
+	for _, count := range chunksPerDoc {
+		perDoc = append(perDoc, count)
+	}
+	stats.ChunksPerDocument = summarizeSizes(perDoc)
+
+	if len(chunkLengths) > 0 {
+		summary := summarizeSizes(chunkLengths)
+		stats.ChunkLength = &summary
+		chunks := make([]Chunk, len(chunkLengths))
+		for i, length := range chunkLengths {
+			chunks[i].Size = length
+		}
+		stats.ChunkHistogram = chunkHistogram(chunks, summary.Max, 5)
+	}
+
+	// Sizes, metadata and duplicates from the fetched documents
+	stats.SampledDocuments = len(documents)
+	var sizes []int
+	keyCounts := make(map[string]int)
+	hashes := make(map[string][]string)
+	for _, doc := range documents {
+		size := utf8.RuneCountInString(doc.Text)
+		sizes = append(sizes, size)
+		stats.TotalSize += size
+		stats.LargestDocuments = append(stats.LargestDocuments, DocumentSize{Name: doc.Name, Size: size, Chunks: chunksPerDoc[doc.Name]})
+
+		for key := range doc.Metadata {
+			keyCounts[key]++
+		}
+
+		sum := sha256.Sum256([]byte(doc.Text))
+		hash := hex.EncodeToString(sum[:])
+		hashes[hash] = append(hashes[hash], doc.Name)
+	}
+	stats.DocumentSize = summarizeSizes(sizes)
+	if len(documents) > 0 && len(documents) < stats.DocumentCount {
+		stats.TotalSize = int(stats.DocumentSize.Mean * float64(stats.DocumentCount))
+		stats.TotalSizeEstimate = true
+	}
+
+	for _, key := range sortedKeys(keyCounts) {
+		stats.MetadataCoverage = append(stats.MetadataCoverage, MetadataCoverage{
+			Key:       key,
+			Documents: keyCounts[key],
+			Percent:   float64(keyCounts[key]) * 100 / float64(len(documents)),
+		})
+	}
+	sort.SliceStable(stats.MetadataCoverage, func(i, j int) bool {
+		return stats.MetadataCoverage[i].Documents > stats.MetadataCoverage[j].Documents
+	})
+
+	sort.SliceStable(stats.LargestDocuments, func(i, j int) bool {
+		if stats.LargestDocuments[i].Size != stats.LargestDocuments[j].Size {
+			return stats.LargestDocuments[i].Size > stats.LargestDocuments[j].Size
+		}
+		return stats.LargestDocuments[i].Name < stats.LargestDocuments[j].Name
+	})
+	if top >= 0 && len(stats.LargestDocuments) > top {
+		stats.LargestDocuments = stats.LargestDocuments[:top]
+	}
+
+	for _, hash := range sortedKeys(hashes) {
+		if names := hashes[hash]; len(names) > 1 {
+			sort.Strings(names)
+			stats.Duplicates = append(stats.Duplicates, DuplicateGroup{Hash: hash, Documents: names})
+		}
+	}
+	return stats
+}
+
+func summarizeSizes(sizes []int) SizeSummary {
+	if len(sizes) == 0 {
+		return SizeSummary{}
+	}
+	summary := SizeSummary{Min: sizes[0], Max: sizes[0]}
+	total := 0
+	for _, size := range sizes {
+		total += size
+		summary.Min = min(summary.Min, size)
+		summary.Max = max(summary.Max, size)
+	}
+	summary.Mean = float64(total) / float64(len(sizes))
+	return summary
+}
+
+// printCollectionStats prints statistics as text
+func printCollectionStats(stats *CollectionStats) {
+	fmt.Printf("Statistics for collection '%s' in vector database '%s':\n\n", stats.Collection, stats.Database)
+	if stats.Embedding != "" {
+		fmt.Printf("  Embedding:      %s\n", stats.Embedding)
+	}
+	fmt.Printf("  Chunking:       %s\n", stats.Chunking)
+
+	documents := fmt.Sprintf("%d", stats.DocumentCount)
+	if stats.SampledDocuments < stats.DocumentCount {
+		documents += fmt.Sprintf(" (%d sampled)", stats.SampledDocuments)
+	}
+	fmt.Printf("  Documents:      %s\n", documents)
+
+	if stats.DocumentCount == 0 {
+		return
+	}
+
+	fmt.Printf("  Chunks:         %d (%.1f per document, min %d, max %d)\n",
+		stats.ChunkCount, stats.ChunksPerDocument.Mean, stats.ChunksPerDocument.Min, stats.ChunksPerDocument.Max)
+	if stats.SampledDocuments > 0 {
+		total := fmt.Sprintf("%d", stats.TotalSize)
+		if stats.TotalSizeEstimate {
+			total = "~" + total
+		}
+		fmt.Printf("  Document size:  %s chars total, average %.1f, min %d, max %d\n",
+			total, stats.DocumentSize.Mean, stats.DocumentSize.Min, stats.DocumentSize.Max)
+	}
+	if stats.ChunkLength != nil {
+		fmt.Printf("  Chunk length:   average %.1f, min %d, max %d\n", stats.ChunkLength.Mean, stats.ChunkLength.Min, stats.ChunkLength.Max)
+
+		fmt.Println("\nChunk length distribution:")
+		printChunkHistogram(stats.ChunkHistogram)
+	}
+
+	if stats.SampledDocuments == 0 {
+		return
+	}
+
+	fmt.Printf("\nMetadata coverage (%d document(s)):\n", stats.SampledDocuments)
+	if len(stats.MetadataCoverage) == 0 {
+		fmt.Println("  No metadata")
+	}
+	width := 0
+	for _, coverage := range stats.MetadataCoverage {
+		width = max(width, len(coverage.Key))
+	}
+	for _, coverage := range stats.MetadataCoverage {
+		fmt.Printf("  %-*s %6.1f%% (%d)\n", width, coverage.Key, coverage.Percent, coverage.Documents)
+	}
+
+	if len(stats.LargestDocuments) > 0 {
+		fmt.Println("\nLargest documents:")
+		width = 0
+		for _, doc := range stats.LargestDocuments {
+			width = max(width, len(doc.Name))
+		}
+		for i, doc := range stats.LargestDocuments {
+			fmt.Printf("  %d. %-*s %8d chars, %d chunk(s)\n", i+1, width, doc.Name, doc.Size, doc.Chunks)
+		}
+	}
+
+	fmt.Println("\nDuplicate content:")
+	if len(stats.Duplicates) == 0 {
+		fmt.Println("  No documents with identical content")
+	}
+	for _, group := range stats.Duplicates {
+		fmt.Printf("  %s (sha256 %s)\n", strings.Join(group.Documents, ", "), group.Hash[:12])
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampleNames(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+	if got := sampleNames(names, 0); !reflect.DeepEqual(got, names) {
+		t.Errorf("sampleNames(0) = %v, expected all names", got)
+	}
+	if got := sampleNames(names, 20); !reflect.DeepEqual(got, names) {
+		t.Errorf("sampleNames(20) = %v, expected all names", got)
+	}
+	if got, expected := sampleNames(names, 3), []string{"a", "d", "g"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("sampleNames(3) = %v, expected %v", got, expected)
+	}
+}
+
+func TestComputeCollectionStats(t *testing.T) {
+	entries := []DocumentRecord{
+		{Name: "a.md", Text: "aaaa"},
+		{Name: "a.md", Text: "aa"},
+		{Name: "b.md", Text: "bbbbbb"},
+		{Name: "c.md", Text: "aaaaaa"},
+	}
+	documents := []DocumentRecord{
+		{Name: "a.md", Text: "aaaaaa", Metadata: map[string]interface{}{"source": "x", "lang": "en"}},
+		{Name: "b.md", Text: "bbbbbbbbbb", Metadata: map[string]interface{}{"source": "y"}},
+		{Name: "c.md", Text: "aaaaaa"},
+	}
+
+	stats := computeCollectionStats(entries, documents, 2)
+
+	if stats.DocumentCount != 3 || stats.ChunkCount != 4 {
+		t.Errorf("counts = %d documents, %d chunks, expected 3 and 4", stats.DocumentCount, stats.ChunkCount)
+	}
+	if stats.ChunksPerDocument.Min != 1 || stats.ChunksPerDocument.Max != 2 {
+		t.Errorf("chunks per document = %+v", stats.ChunksPerDocument)
+	}
+	if stats.ChunkLength == nil || stats.ChunkLength.Min != 2 || stats.ChunkLength.Max != 6 || stats.ChunkLength.Mean != 4.5 {
+		t.Errorf("chunk length = %+v", stats.ChunkLength)
+	}
+	if stats.TotalSize != 22 || stats.TotalSizeEstimate || stats.DocumentSize.Max != 10 {
+		t.Errorf("sizes = total %d (estimated %v), %+v", stats.TotalSize, stats.TotalSizeEstimate, stats.DocumentSize)
+	}
+
+	expectedCoverage := []MetadataCoverage{{Key: "source", Documents: 2, Percent: 200.0 / 3}, {Key: "lang", Documents: 1, Percent: 100.0 / 3}}
+	if !reflect.DeepEqual(stats.MetadataCoverage, expectedCoverage) {
+		t.Errorf("metadata coverage = %+v, expected %+v", stats.MetadataCoverage, expectedCoverage)
+	}
+
+	expectedLargest := []DocumentSize{{Name: "b.md", Size: 10, Chunks: 1}, {Name: "a.md", Size: 6, Chunks: 2}}
+	if !reflect.DeepEqual(stats.LargestDocuments, expectedLargest) {
+		t.Errorf("largest documents = %+v, expected %+v", stats.LargestDocuments, expectedLargest)
+	}
+
+	if len(stats.Duplicates) != 1 || !reflect.DeepEqual(stats.Duplicates[0].Documents, []string{"a.md", "c.md"}) {
+		t.Errorf("duplicates = %+v, expected a.md and c.md", stats.Duplicates)
+	}
+}
+
+func TestComputeCollectionStatsEstimatesTotalFromSample(t *testing.T) {
+	entries := []DocumentRecord{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	documents := []DocumentRecord{{Name: "a", Text: "1234"}, {Name: "c", Text: "12"}}
+
+	stats := computeCollectionStats(entries, documents, 5)
+	if !stats.TotalSizeEstimate || stats.TotalSize != 12 {
+		t.Errorf("total size = %d (estimated %v), expected estimate of 12", stats.TotalSize, stats.TotalSizeEstimate)
+	}
+	if stats.ChunkLength != nil {
+		t.Errorf("chunk length = %+v, expected none without chunk text", stats.ChunkLength)
+	}
+}
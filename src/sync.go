@@ -0,0 +1,447 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for document sync
+var (
+	syncInclude     []string
+	syncExclude     []string
+	syncHidden      bool
+	syncPrune       bool
+	syncConcurrency int
+)
+
+var documentSyncCmd = &cobra.Command{
+	Use:   "sync DIR",
+	Short: "Mirror a directory into a collection",
+	Long: `Keep a collection mirroring a directory, one document per file.
+
+Documents are named by their path relative to DIR, as with 'document ingest'. Each file's SHA-256 is
+compared with the content_sha256 metadata stored with its document: new files are written, modified
+files replace their document through a staged copy that restores it if the write fails, and
+unchanged files are skipped. Documents written without a stored hash are
+compared by their text. Documents whose files were deleted locally are only removed with --prune.
+
+--include, --exclude and --hidden select files as for 'document ingest' and also limit which
+documents --prune may delete. With --dry-run the changes are listed without being applied.`,
+	Example: `  maestro document sync ./docs --vdb=my-vdb --collection=my-collection
+  maestro document sync ./docs --vdb=my-vdb --collection=my-collection --prune --dry-run
+  maestro document sync ./docs --vdb=my-vdb --collection=my-collection --include='*.md' --prune --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return syncDocuments(vdbName, collectionName, args[0])
+	},
+}
+
+func init() {
+	documentSyncCmd.Flags().String("vdb", "", "Vector database name")
+	documentSyncCmd.Flags().String("collection", "", "Collection name")
+	documentSyncCmd.Flags().StringSliceVar(&syncInclude, "include", nil, "Only sync files matching these globs (repeatable)")
+	documentSyncCmd.Flags().StringSliceVar(&syncExclude, "exclude", nil, "Skip files matching these globs (repeatable)")
+	documentSyncCmd.Flags().BoolVar(&syncHidden, "hidden", false, "Include hidden files and directories")
+	documentSyncCmd.Flags().BoolVar(&syncPrune, "prune", false, "Delete documents whose files no longer exist locally")
+	documentSyncCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send file contents as-is instead of extracting their text")
+	addMetadataFlags(documentSyncCmd)
+	addMaxSizeFlag(documentSyncCmd)
+	addRedactFlags(documentSyncCmd)
+	documentSyncCmd.Flags().IntVar(&syncConcurrency, "concurrency", defaultConcurrency, "Number of files to sync in parallel")
+}
+
+// Kinds of sync changes
+const (
+	syncAdd    = "add"
+	syncUpdate = "update"
+	syncDelete = "delete"
+)
+
+// syncChange is one change needed to make the collection mirror the directory
+type syncChange struct {
+	Kind string
+	File ingestFile
+}
+
+// syncPlan lists the changes of a sync, sorted by document name
+type syncPlan struct {
+	Changes    []syncChange
+	Extraneous []string // documents without a local file, kept because --prune is not set
+	Unchanged  int
+	Documents  []string // documents of the collection, to find staged copies left by interrupted updates
+}
+
+func (p *syncPlan) count(kind string) int {
+	n := 0
+	for _, change := range p.Changes {
+		if change.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func syncDocuments(vdbName, collectionName, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory '%s': %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", dir)
+	}
+	for _, pattern := range append(append([]string{}, syncInclude...), syncExclude...) {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+
+	files, err := collectIngestFiles([]string{dir}, syncInclude, syncExclude, syncHidden)
+	if err != nil {
+		return err
+	}
+	local, err := hashLocalFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Syncing %d file(s) from '%s' to collection '%s' of vector database '%s'...\n", len(files), dir, collectionName, vdbName)
+	}
+
+	if dryRun {
+		// Computing the changes only reads from the server; they are not listed when it is unavailable
+		if err := reportSyncPlan(vdbName, collectionName, local); err != nil {
+			if !silent {
+				fmt.Printf("[DRY RUN] Would sync %d file(s) from '%s' to collection '%s' of vector database '%s'\n", len(files), dir, collectionName, vdbName)
+			}
+			fmt.Fprintf(os.Stderr, "Change list unavailable: %v\n", err)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plan, err := computeSyncPlan(client, serverURI, vdbName, collectionName, local)
+	if err != nil {
+		return err
+	}
+	if !silent {
+		printSyncPlan(plan)
+	}
+	if len(plan.Changes) == 0 {
+		if !silent {
+			fmt.Printf("✅ Collection '%s' is up to date with '%s' (%d file(s))\n", collectionName, dir, plan.Unchanged)
+		}
+		return nil
+	}
+
+	if deletions := plan.count(syncDelete); deletions > 0 {
+		if err := confirmDestructiveOperation("delete", fmt.Sprintf("%d document(s) from collection '%s'", deletions, collectionName)); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	failures, transferred := applySyncPlan(client, serverURI, vdbName, collectionName, plan, syncConcurrency)
+	if !silent {
+		printSyncSummary(plan, failures, transferred, time.Since(start))
+		printRedactionReport(redactionReport())
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d change(s) failed; rerun sync to retry them", len(failures), len(plan.Changes))
+	}
+	return nil
+}
+
+// hashLocalFiles reads every file and returns the files keyed by document name with their hashes
+func hashLocalFiles(files []ingestFile) (map[string]syncLocalFile, error) {
+	local := make(map[string]syncLocalFile, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%s': %w", file.Path, err)
+		}
+		local[file.Name] = syncLocalFile{File: file, Hash: contentHash(content)}
+	}
+	return local, nil
+}
+
+// syncLocalFile is a local file and its content hash
+type syncLocalFile struct {
+	File ingestFile
+	Hash string
+}
+
+// reportSyncPlan prints the changes a sync would make without applying them
+func reportSyncPlan(vdbName, collectionName string, local map[string]syncLocalFile) error {
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plan, err := computeSyncPlan(client, serverURI, vdbName, collectionName, local)
+	if err != nil {
+		return err
+	}
+	if !silent {
+		fmt.Printf("[DRY RUN] Would sync collection '%s' of vector database '%s':\n", collectionName, vdbName)
+		printSyncPlan(plan)
+		fmt.Printf("[DRY RUN] %d to add, %d to update, %d to delete, %d unchanged\n",
+			plan.count(syncAdd), plan.count(syncUpdate), plan.count(syncDelete), plan.Unchanged)
+	}
+	return nil
+}
+
+// computeSyncPlan verifies the target and compares local files with the collection's documents
+func computeSyncPlan(client *MCPClient, serverURI, vdbName, collectionName string, local map[string]syncLocalFile) (*syncPlan, error) {
+	remote, err := fetchSyncedHashes(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+	return planSync(local, remote, syncPrune), nil
+}
+
+// fetchSyncedHashes verifies the target and returns the hashes of the documents the sync filters select
+func fetchSyncedHashes(client *MCPClient, serverURI, vdbName, collectionName string) (map[string]string, error) {
+	var exists bool
+	if err := safeCall(serverURI, func() error {
+		var existsErr error
+		exists, existsErr = client.DatabaseExists(vdbName)
+		return existsErr
+	}); err != nil {
+		return nil, fmt.Errorf("failed to check if database exists: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("vector database '%s' does not exist. Please create it first", vdbName)
+	}
+
+	remote, err := fetchRemoteHashes(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only documents the filters could have produced are candidates for deletion
+	for name := range remote {
+		if !matchesFilters(name, syncInclude, syncExclude) || (!syncHidden && isHiddenPath(name)) {
+			delete(remote, name)
+		}
+	}
+	return remote, nil
+}
+
+// fetchRemoteHashes returns the content hash of every document in a collection. Hashes come from
+// the listing metadata when present; other documents are fetched and hashed by their text.
+func fetchRemoteHashes(client *MCPClient, serverURI, vdbName, collectionName string) (map[string]string, error) {
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string)
+	for _, entry := range entries {
+		if hash, _ := entry.Metadata[contentHashMetadataKey].(string); hash != "" {
+			hashes[entry.Name] = hash
+		} else if _, ok := hashes[entry.Name]; !ok {
+			hashes[entry.Name] = ""
+		}
+	}
+
+	var missing []string
+	for name, hash := range hashes {
+		if hash == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return hashes, nil
+	}
+
+	sort.Strings(missing)
+	documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, missing, syncConcurrency)
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("failed to read %d document(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	for _, doc := range documents {
+		if hash, _ := doc.Metadata[contentHashMetadataKey].(string); hash != "" {
+			hashes[doc.Name] = hash
+		} else {
+			hashes[doc.Name] = contentHash([]byte(doc.Text))
+		}
+	}
+	return hashes, nil
+}
+
+// planSync compares local file hashes with remote document hashes
+func planSync(local map[string]syncLocalFile, remote map[string]string, prune bool) *syncPlan {
+	plan := &syncPlan{Documents: sortedKeys(remote)}
+	for _, name := range sortedKeys(local) {
+		file := local[name]
+		remoteHash, ok := remote[name]
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, syncChange{Kind: syncAdd, File: file.File})
+		case remoteHash != file.Hash:
+			plan.Changes = append(plan.Changes, syncChange{Kind: syncUpdate, File: file.File})
+		default:
+			plan.Unchanged++
+		}
+	}
+	for _, name := range sortedKeys(remote) {
+		if _, ok := local[name]; ok {
+			continue
+		}
+		if prune {
+			plan.Changes = append(plan.Changes, syncChange{Kind: syncDelete, File: ingestFile{Name: name}})
+		} else {
+			plan.Extraneous = append(plan.Extraneous, name)
+		}
+	}
+	return plan
+}
+
+// isHiddenPath reports whether any segment of a slash-separated path starts with a dot
+func isHiddenPath(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// applySyncPlan applies the changes and returns the failures and the number of bytes written
+func applySyncPlan(client *MCPClient, serverURI, vdbName, collectionName string, plan *syncPlan, concurrency int) ([]string, int64) {
+	progress := newBulkProgress(fmt.Sprintf("Syncing %d change(s) to '%s'...", len(plan.Changes), collectionName), len(plan.Changes))
+	progress.SetUnit("files")
+
+	var mu sync.Mutex
+	var failures []string
+	var transferred int64
+	runConcurrently(plan.Changes, concurrency, func(change syncChange) {
+		defer progress.Increment()
+
+		_, err := applySyncChange(client, serverURI, vdbName, collectionName, change, plan.Documents)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if isRedactionDrop(err) {
+			// Reported in the redaction summary
+			return
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s %s: %v", change.Kind, change.File.Name, err))
+			return
+		}
+		if change.Kind != syncDelete {
+			transferred += change.File.Size
+		}
+	})
+
+	progress.Stop(fmt.Sprintf("Applied %d change(s)", len(plan.Changes)-len(failures)), len(failures))
+	sort.Strings(failures)
+	return failures, transferred
+}
+
+// applySyncChange applies one change and returns the hash of the written file, if any. Files are
+// extracted and redacted before anything is deleted, and modified documents are replaced through a
+// staged copy because writes do not replace documents. names lists the documents of the collection.
+func applySyncChange(client *MCPClient, serverURI, vdbName, collectionName string, change syncChange, names []string) (string, error) {
+	var notFound *DocumentNotFoundError
+	if change.Kind == syncDelete {
+		err := classifyDeleteError(deleteDocumentName(client, serverURI, vdbName, collectionName, change.File.Name), change.File.Name, collectionName, vdbName)
+		if errors.As(err, &notFound) {
+			// Already gone, e.g. deleted by an earlier attempt whose reply was lost
+			return "", nil
+		}
+		return "", err
+	}
+
+	doc, err := prepareFileDocument(change.File)
+	if err != nil {
+		return doc.Hash, err
+	}
+	if change.Kind == syncUpdate {
+		_, err := replaceDocument(client, serverURI, vdbName, collectionName, change.File.Name, doc.Text, doc.Metadata, names, 0)
+		if !errors.As(err, &notFound) {
+			return doc.Hash, err
+		}
+		// The document is gone, e.g. deleted by an earlier attempt that could not restore it
+	}
+	return doc.Hash, writeDocumentText(client, serverURI, vdbName, collectionName, change.File.Name, doc.Text, change.File.Path, doc.Metadata)
+}
+
+// printSyncPlan lists changes one per line in the style of rsync's itemized output
+func printSyncPlan(plan *syncPlan) {
+	symbols := map[string]string{syncAdd: "+", syncUpdate: "~", syncDelete: "-"}
+	for _, change := range plan.Changes {
+		fmt.Printf("%s %s\n", symbols[change.Kind], change.File.Name)
+	}
+	if len(plan.Extraneous) > 0 {
+		fmt.Printf("%d document(s) have no local file and were kept; use --prune to delete them\n", len(plan.Extraneous))
+		if verbose {
+			for _, name := range plan.Extraneous {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+	}
+}
+
+// printSyncSummary prints totals after a sync, in the spirit of rsync --stats
+func printSyncSummary(plan *syncPlan, failures []string, transferred int64, elapsed time.Duration) {
+	fmt.Printf("\nNumber of files: %d (added %d, updated %d, unchanged %d)\n",
+		plan.count(syncAdd)+plan.count(syncUpdate)+plan.Unchanged, plan.count(syncAdd), plan.count(syncUpdate), plan.Unchanged)
+	fmt.Printf("Number of deleted documents: %d\n", plan.count(syncDelete))
+	fmt.Printf("Total transferred file size: %s\n", formatBytes(transferred))
+	fmt.Printf("Elapsed: %.2fs\n", elapsed.Seconds())
+
+	if len(failures) > 0 {
+		fmt.Printf("\n%d failure(s):\n", len(failures))
+		for _, failure := range failures {
+			fmt.Printf("  ❌ %s\n", failure)
+		}
+		return
+	}
+	fmt.Printf("✅ Sync completed\n")
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestPlanSync(t *testing.T) {
+	local := map[string]syncLocalFile{
+		"new.md":       {File: ingestFile{Name: "new.md"}, Hash: "n"},
+		"changed.md":   {File: ingestFile{Name: "changed.md"}, Hash: "c2"},
+		"unchanged.md": {File: ingestFile{Name: "unchanged.md"}, Hash: "u"},
+	}
+	remote := map[string]string{"changed.md": "c1", "unchanged.md": "u", "removed.md": "r"}
+
+	plan := planSync(local, remote, false)
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.Kind+" "+change.File.Name)
+	}
+	if expected := []string{"update changed.md", "add new.md"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("planSync() changes = %v, expected %v", changes, expected)
+	}
+	if plan.Unchanged != 1 || !reflect.DeepEqual(plan.Extraneous, []string{"removed.md"}) {
+		t.Errorf("planSync() unchanged = %d, extraneous = %v", plan.Unchanged, plan.Extraneous)
+	}
+
+	plan = planSync(local, remote, true)
+	if plan.count(syncDelete) != 1 || len(plan.Extraneous) != 0 {
+		t.Errorf("planSync() with prune = %+v, expected one deletion", plan)
+	}
+}
+
+func TestIsHiddenPath(t *testing.T) {
+	tests := map[string]bool{"a.md": false, "docs/a.md": false, ".env": true, "docs/.git/config": true}
+	for name, expected := range tests {
+		if got := isHiddenPath(name); got != expected {
+			t.Errorf("isHiddenPath(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
+
+// fakeDocumentStore serves get, write and delete tools over an in-memory collection. The next
+// failWrites writes of a document fail.
+type fakeDocumentStore struct {
+	mu         sync.Mutex
+	docs       map[string]string
+	failWrites map[string]int
+	deletes    []string
+}
+
+func (f *fakeDocumentStore) text(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	text, ok := f.docs[name]
+	return text, ok
+}
+
+func (f *fakeDocumentStore) call(tool string, input map[string]interface{}) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name, _ := input["doc_name"].(string)
+	notFound := fmt.Errorf("Document '%s' not found in collection '%v'", name, input["collection_name"])
+	switch tool {
+	case "get_document":
+		text, ok := f.docs[name]
+		if !ok {
+			return "", notFound
+		}
+		data, _ := json.Marshal(map[string]interface{}{"doc_name": name, "text": text})
+		return string(data), nil
+	case "write_document_to_collection":
+		if f.failWrites[name] > 0 {
+			f.failWrites[name]--
+			return "", fmt.Errorf("embedding service unavailable")
+		}
+		f.docs[name], _ = input["text"].(string)
+		return "written", nil
+	default:
+		f.deletes = append(f.deletes, name)
+		if _, ok := f.docs[name]; !ok {
+			return "", notFound
+		}
+		delete(f.docs, name)
+		return "deleted", nil
+	}
+}
+
+// newFakeDocumentStore starts an MCP server backed by store and returns a client connected to it
+func newFakeDocumentStore(t *testing.T, store *fakeDocumentStore) *MCPClient {
+	t.Helper()
+	mcpServer := server.NewMCPServer("fake", "1.0")
+	for _, tool := range []string{"get_document", "write_document_to_collection", "delete_document_from_collection"} {
+		tool := tool
+		mcpServer.AddTool(mcp.NewTool(tool), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			input, _ := request.GetArguments()["input"].(map[string]interface{})
+			result, err := store.call(tool, input)
+			if err != nil {
+				return mcp.NewToolResultText("Error: " + err.Error()), nil
+			}
+			return mcp.NewToolResultText(result), nil
+		})
+	}
+	testServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(testServer.Close)
+
+	client, err := NewMCPSessionClient(testServer.URL + "/mcp")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// writeSyncFile writes a local file and returns it as an ingest file
+func writeSyncFile(t *testing.T, name, content string) ingestFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return ingestFile{Path: path, Name: name, Size: int64(len(content))}
+}
+
+func TestApplySyncChangeUpdateRestoresOnFailedWrite(t *testing.T) {
+	store := &fakeDocumentStore{docs: map[string]string{"a.md": "old"}, failWrites: map[string]int{"a.md": 1}}
+	client := newFakeDocumentStore(t, store)
+	change := syncChange{Kind: syncUpdate, File: writeSyncFile(t, "a.md", "new")}
+
+	_, err := applySyncChange(client, "fake", "vdb", "docs", change, []string{"a.md"})
+	if err == nil || !strings.Contains(err.Error(), "its previous content was restored") {
+		t.Fatalf("applySyncChange() error = %v, want a restored write failure", err)
+	}
+	if text, _ := store.text("a.md"); text != "old" {
+		t.Errorf("a.md = %q after the failed write, want the previous content", text)
+	}
+	if _, ok := store.text("a.md" + documentStagingSuffix); ok {
+		t.Error("the staged copy should be deleted")
+	}
+
+	hash, err := applySyncChange(client, "fake", "vdb", "docs", change, []string{"a.md"})
+	if err != nil {
+		t.Fatalf("retried applySyncChange() error = %v", err)
+	}
+	if text, _ := store.text("a.md"); text != "new" || hash != contentHash([]byte("new")) {
+		t.Errorf("a.md = %q, hash = %s after the retry", text, hash)
+	}
+}
+
+func TestApplySyncChangeMissingDocument(t *testing.T) {
+	store := &fakeDocumentStore{docs: map[string]string{}}
+	client := newFakeDocumentStore(t, store)
+
+	// an update of a document that is already gone writes it again
+	update := syncChange{Kind: syncUpdate, File: writeSyncFile(t, "a.md", "new")}
+	if _, err := applySyncChange(client, "fake", "vdb", "docs", update, nil); err != nil {
+		t.Fatalf("applySyncChange(update) error = %v", err)
+	}
+	if text, _ := store.text("a.md"); text != "new" {
+		t.Errorf("a.md = %q, want the new content", text)
+	}
+
+	// deleting a document that is already gone succeeds
+	remove := syncChange{Kind: syncDelete, File: ingestFile{Name: "b.md"}}
+	if _, err := applySyncChange(client, "fake", "vdb", "docs", remove, nil); err != nil {
+		t.Errorf("applySyncChange(delete) error = %v", err)
+	}
+}
+
+func TestApplySyncChangeRedactsBeforeDeleting(t *testing.T) {
+	defer func() { activeRedactor = nil }()
+	activeRedactor, _ = newRedactor(redactModeDrop, "")
+	store := &fakeDocumentStore{docs: map[string]string{"a.md": "old"}}
+	client := newFakeDocumentStore(t, store)
+
+	change := syncChange{Kind: syncUpdate, File: writeSyncFile(t, "a.md", "Owner: ops@example.org")}
+	if _, err := applySyncChange(client, "fake", "vdb", "docs", change, []string{"a.md"}); !isRedactionDrop(err) {
+		t.Fatalf("applySyncChange() error = %v, want a redaction drop", err)
+	}
+	if text, _ := store.text("a.md"); text != "old" || len(store.deletes) != 0 {
+		t.Errorf("a.md = %q, deletes = %v; a dropped update must not touch the remote document", text, store.deletes)
+	}
+}
+
+func TestWatchApplyRetriesFailedWrite(t *testing.T) {
+	retries, delay := watchRetries, watchRetryDelay
+	defer func() { watchRetries, watchRetryDelay = retries, delay }()
+	watchRetries, watchRetryDelay = 2, time.Millisecond
+
+	store := &fakeDocumentStore{docs: map[string]string{"a.md": "old"}, failWrites: map[string]int{"a.md": 1}}
+	logger, _ := newWatchLogger("text")
+	w := &dirWatcher{ctx: context.Background(), vdbName: "vdb", collectionName: "docs", logger: logger, client: newFakeDocumentStore(t, store), serverURI: "fake",
+		remote: map[string]string{"a.md": contentHash([]byte("old"))}}
+
+	change := syncChange{Kind: syncUpdate, File: writeSyncFile(t, "a.md", "new")}
+	if failed := w.apply(&syncPlan{Changes: []syncChange{change}, Documents: []string{"a.md"}}); len(failed) != 0 {
+		t.Fatalf("apply() failed = %v", failed)
+	}
+	if text, _ := store.text("a.md"); text != "new" {
+		t.Errorf("a.md = %q, want the new content", text)
+	}
+	if w.remote["a.md"] != contentHash([]byte("new")) {
+		t.Errorf("remote hash = %s, want the hash of the new content", w.remote["a.md"])
+	}
+}
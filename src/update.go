@@ -0,0 +1,461 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Metadata keys recorded on saved versions of a document
+const (
+	versionOfMetadataKey   = "version_of"
+	versionMetadataKey     = "document_version"
+	versionedAtMetadataKey = "versioned_at"
+)
+
+// Suffixes of the documents kept next to a document: saved versions and the staged replacement
+const (
+	documentVersionSeparator = "@v"
+	documentStagingSuffix    = "@pending"
+)
+
+// Flags for document updates
+var (
+	documentUpsert bool
+	keepVersions   int
+	historyOutput  string
+)
+
+var documentUpdateCmd = &cobra.Command{
+	Use:   "update NAME",
+	Short: "Replace the content of a document",
+	Long: `Replace the content of an existing document.
+
+The new content is first written as NAME@pending, so it stays searchable while NAME is deleted and
+written again; the staged copy is then removed. If NAME cannot be written, its previous content is
+restored. With --keep-versions N, the previous content and metadata are saved as NAME@vK, and only
+the N most recent versions are kept. Use 'document history NAME' to list them.`,
+	Example: `  maestro document update guide --vdb=my-vdb --collection=my-collection --file=./guide.md
+  maestro document update guide --vdb=my-vdb --collection=my-collection --file=./guide.md --keep-versions=3
+  cat notes.md | maestro document update notes --vdb=my-vdb --collection=my-collection --file=-`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return updateDocument(vdbName, collectionName, args[0])
+	},
+}
+
+var documentHistoryCmd = &cobra.Command{
+	Use:   "history NAME",
+	Short: "List the saved versions of a document",
+	Long:  `List the versions of a document saved by 'document update' and 'document create --upsert' with --keep-versions.`,
+	Example: `  maestro document history guide --vdb=my-vdb --collection=my-collection
+  maestro document get guide@v2 --vdb=my-vdb --collection=my-collection`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if historyOutput != "text" && historyOutput != "json" {
+			return fmt.Errorf("unsupported output format '%s' (use text or json)", historyOutput)
+		}
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return showDocumentHistory(vdbName, collectionName, args[0])
+	},
+}
+
+func init() {
+	documentUpdateCmd.Flags().String("vdb", "", "Vector database name")
+	documentUpdateCmd.Flags().String("collection", "", "Collection name")
+	documentUpdateCmd.Flags().StringVar(&documentFileName, "file", "", "File with the new content, or - to read standard input")
+	documentUpdateCmd.Flags().StringVar(&documentURL, "url", "", "Fetch the new content over HTTP(S)")
+	documentUpdateCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send the file content as-is instead of extracting its text")
+	documentUpdateCmd.Flags().IntVar(&keepVersions, "keep-versions", 0, "Save the previous content as NAME@vN, keeping this many versions")
+	addMetadataFlags(documentUpdateCmd)
+	addMaxSizeFlag(documentUpdateCmd)
+	addRedactFlags(documentUpdateCmd)
+
+	documentHistoryCmd.Flags().String("vdb", "", "Vector database name")
+	documentHistoryCmd.Flags().String("collection", "", "Collection name")
+	documentHistoryCmd.Flags().StringVarP(&historyOutput, "output", "o", "text", "Output format (text, json)")
+}
+
+// addUpsertFlags adds --upsert and --keep-versions to a document creation command
+func addUpsertFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&documentUpsert, "upsert", false, "Replace the document if it already exists")
+	cmd.Flags().IntVar(&keepVersions, "keep-versions", 0, "With --upsert, save the previous content as NAME@vN, keeping this many versions")
+}
+
+// DocumentVersion is a saved version of a document
+type DocumentVersion struct {
+	Version  int    `json:"version"`
+	Document string `json:"document"`
+	SavedAt  string `json:"saved_at,omitempty"`
+}
+
+// replaceResult describes a completed replacement
+type replaceResult struct {
+	SavedVersion string
+	Pruned       []string
+	Parts        int // parts of a split document replaced by the new content
+}
+
+func updateDocument(vdbName, collectionName, docName string) error {
+	if documentFileName == "" && documentURL == "" {
+		return fmt.Errorf("--file or --url is required")
+	}
+	if keepVersions < 0 {
+		return fmt.Errorf("--keep-versions must not be negative")
+	}
+	if err := validateDocumentName(docName); err != nil {
+		return err
+	}
+	if err := validateDocumentSource(documentFileName, documentURL); err != nil {
+		return err
+	}
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would update document '%s' in collection '%s' of vector database '%s' from %s%s\n",
+				docName, collectionName, vdbName, describeDocumentSource(documentFileName, documentURL), describeKeepVersions(keepVersions))
+		}
+		return nil
+	}
+
+	extracted, err := readDocumentSource(documentFileName, documentURL, extractRaw)
+	if err != nil {
+		return err
+	}
+	if err := redactDocument(docName, extracted); err != nil {
+		return fmt.Errorf("document '%s' was not updated: %w", docName, err)
+	}
+	if err := checkDocumentSize(docName, extracted.Text); err != nil {
+		return err
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := ensureCollection(client, serverURI, vdbName, collectionName); err != nil {
+		return err
+	}
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	if !documentExists(names, docName) {
+		return fmt.Errorf("document '%s' does not exist in collection '%s' of vector database '%s'; use 'document create' to add it", docName, collectionName, vdbName)
+	}
+
+	result, err := replaceStoredDocument(client, serverURI, vdbName, collectionName, docName, extracted.Text, documentMetadata(extracted, userMetadata), names, keepVersions)
+	if err != nil {
+		return err
+	}
+	if !silent {
+		printReplaceResult(docName, collectionName, vdbName, result)
+		printRedactionReport(redactionReport())
+	}
+	return nil
+}
+
+// documentExists reports whether names contains docName, whole or as the parts of a split document
+func documentExists(names []string, docName string) bool {
+	return containsName(names, docName) || len(documentPartNames(names, docName)) > 0
+}
+
+// replaceStoredDocument replaces an existing document, whole or split, with new text and metadata.
+// A split document is replaced by writing the new content as NAME, or replacing NAME if it was left
+// next to the parts, before its old parts are deleted. Split documents have no saved versions.
+func replaceStoredDocument(client *MCPClient, serverURI, vdbName, collectionName, docName, text string, metadata map[string]interface{}, names []string, keep int) (*replaceResult, error) {
+	parts := documentPartNames(names, docName)
+	if len(parts) == 0 {
+		return replaceDocument(client, serverURI, vdbName, collectionName, docName, text, metadata, names, keep)
+	}
+	if keep > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' is a split document; its previous content is not saved as a version\n", docName)
+	}
+
+	result := &replaceResult{Parts: len(parts)}
+	if containsName(names, docName) {
+		replaced, err := replaceDocument(client, serverURI, vdbName, collectionName, docName, text, metadata, names, 0)
+		if err != nil {
+			return nil, err
+		}
+		result.Pruned = replaced.Pruned
+	} else if err := writeDocumentText(client, serverURI, vdbName, collectionName, docName, text, firstString(metadata, "url", "filename"), metadata); err != nil {
+		return nil, fmt.Errorf("failed to write '%s'; its %d part(s) were left unchanged: %w", docName, len(parts), err)
+	}
+
+	report := deleteDocumentNames(client, serverURI, vdbName, collectionName, parts, defaultConcurrency)
+	if len(report.Failures) > 0 {
+		return nil, fmt.Errorf("wrote '%s' but %d of its %d old part(s) could not be deleted: %s", docName, len(report.Failures), len(parts), strings.Join(report.Failures, "; "))
+	}
+	return result, nil
+}
+
+// replaceDocument replaces an existing document with new text and metadata. The new content is staged
+// under NAME@pending while NAME is rewritten, and the previous content is restored if that fails.
+// With keep > 0 the previous content is saved as the next NAME@vK and older versions beyond keep are
+// deleted. names lists the documents of the collection.
+func replaceDocument(client *MCPClient, serverURI, vdbName, collectionName, docName, text string, metadata map[string]interface{}, names []string, keep int) (*replaceResult, error) {
+	previous, err := fetchDocument(client, serverURI, vdbName, collectionName, docName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the current content of '%s': %w", docName, classifyDeleteError(err, docName, collectionName, vdbName))
+	}
+
+	result := &replaceResult{}
+	if keep > 0 {
+		versions := documentVersions(names, docName)
+		next := 1
+		if len(versions) > 0 {
+			next = versions[len(versions)-1].Version + 1
+		}
+		result.SavedVersion = documentVersionName(docName, next)
+		versionMetadata := copyMetadata(previous.Metadata)
+		versionMetadata[versionOfMetadataKey] = docName
+		versionMetadata[versionMetadataKey] = next
+		versionMetadata[versionedAtMetadataKey] = time.Now().UTC().Format(time.RFC3339)
+		if err := writeDocumentText(client, serverURI, vdbName, collectionName, result.SavedVersion, previous.Text, previous.URL, versionMetadata); err != nil {
+			return nil, fmt.Errorf("failed to save version '%s': %w", result.SavedVersion, err)
+		}
+
+		versions = append(versions, DocumentVersion{Version: next, Document: result.SavedVersion})
+		for _, version := range versions[:max(0, len(versions)-keep)] {
+			if err := deleteDocumentName(client, serverURI, vdbName, collectionName, version.Document); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to delete old version '%s': %v\n", version.Document, err)
+				continue
+			}
+			result.Pruned = append(result.Pruned, version.Document)
+		}
+	}
+
+	source := firstString(metadata, "url", "filename")
+	staging := docName + documentStagingSuffix
+	if containsName(names, staging) {
+		// Left behind by an interrupted update
+		if err := deleteDocumentName(client, serverURI, vdbName, collectionName, staging); err != nil {
+			return nil, fmt.Errorf("failed to remove stale '%s': %w", staging, err)
+		}
+	}
+	if err := writeDocumentText(client, serverURI, vdbName, collectionName, staging, text, source, metadata); err != nil {
+		return nil, fmt.Errorf("failed to stage the new content as '%s': %w", staging, err)
+	}
+	defer func() {
+		if err := deleteDocumentName(client, serverURI, vdbName, collectionName, staging); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete staged copy '%s': %v\n", staging, err)
+		}
+	}()
+
+	// A document that disappeared since it was read is simply written again
+	var notFound *DocumentNotFoundError
+	if err := classifyDeleteError(deleteDocumentName(client, serverURI, vdbName, collectionName, docName), docName, collectionName, vdbName); err != nil && !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("failed to replace '%s': %w", docName, err)
+	}
+	if err := writeDocumentText(client, serverURI, vdbName, collectionName, docName, text, source, metadata); err != nil {
+		if restoreErr := writeDocumentText(client, serverURI, vdbName, collectionName, docName, previous.Text, previous.URL, previous.Metadata); restoreErr != nil {
+			return nil, fmt.Errorf("failed to write '%s' (%v) and to restore its previous content: %w", docName, err, restoreErr)
+		}
+		return nil, fmt.Errorf("failed to write '%s'; its previous content was restored: %w", docName, err)
+	}
+	return result, nil
+}
+
+func printReplaceResult(docName, collectionName, vdbName string, result *replaceResult) {
+	fmt.Printf("✅ Document '%s' updated in collection '%s' of vector database '%s'\n", docName, collectionName, vdbName)
+	if result.SavedVersion != "" {
+		fmt.Printf("Previous content saved as '%s'\n", result.SavedVersion)
+	}
+	if len(result.Pruned) > 0 {
+		fmt.Printf("Deleted %d old version(s): %s\n", len(result.Pruned), strings.Join(result.Pruned, ", "))
+	}
+	if result.Parts > 0 {
+		fmt.Printf("Replaced the %d part(s) of the split document\n", result.Parts)
+	}
+}
+
+func showDocumentHistory(vdbName, collectionName, docName string) error {
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would list the versions of document '%s' in collection '%s' of vector database '%s'\n", docName, collectionName, vdbName)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	entries, err := fetchDocumentEntries(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	documents := documentListEntries(entries)
+	names := make([]string, len(documents))
+	savedAt := make(map[string]string, len(documents))
+	for i, doc := range documents {
+		names[i] = doc.Name
+		savedAt[doc.Name] = firstString(doc.Metadata, versionedAtMetadataKey)
+	}
+
+	versions := documentVersions(names, docName)
+	for i := range versions {
+		versions[i].SavedAt = savedAt[versions[i].Document]
+	}
+	if !containsName(names, docName) && len(versions) == 0 {
+		return fmt.Errorf("document '%s' does not exist in collection '%s' of vector database '%s'", docName, collectionName, vdbName)
+	}
+
+	if historyOutput == "json" {
+		return printJSON(versions)
+	}
+	if len(versions) == 0 {
+		fmt.Printf("Document '%s' has no saved versions\n", docName)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tDOCUMENT\tSAVED")
+	for i := len(versions) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "v%d\t%s\t%s\n", versions[i].Version, versions[i].Document, versions[i].SavedAt)
+	}
+	w.Flush()
+	return nil
+}
+
+// documentVersions returns the saved versions of a document among names, oldest first
+func documentVersions(names []string, docName string) []DocumentVersion {
+	prefix := docName + documentVersionSeparator
+	var versions []DocumentVersion
+	for _, name := range names {
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if version, err := strconv.Atoi(suffix); err == nil && version > 0 && strconv.Itoa(version) == suffix {
+			versions = append(versions, DocumentVersion{Version: version, Document: name})
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions
+}
+
+// documentVersionName returns the name a version of a document is saved under
+func documentVersionName(docName string, version int) string {
+	return fmt.Sprintf("%s%s%d", docName, documentVersionSeparator, version)
+}
+
+// validateDocumentName rejects names that collide with saved versions or staged replacements
+func validateDocumentName(docName string) error {
+	if strings.HasSuffix(docName, documentStagingSuffix) {
+		return fmt.Errorf("document name '%s' is reserved for staged updates", docName)
+	}
+	if i := strings.LastIndex(docName, documentVersionSeparator); i > 0 {
+		if _, err := strconv.Atoi(docName[i+len(documentVersionSeparator):]); err == nil {
+			return fmt.Errorf("document name '%s' is reserved for saved versions; use 'document history' to list them", docName)
+		}
+	}
+	if _, _, ok := parseDocumentPartName(docName); ok {
+		return fmt.Errorf("document name '%s' is reserved for the parts of split documents", docName)
+	}
+	return nil
+}
+
+func describeKeepVersions(keep int) string {
+	if keep == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", keeping %d version(s)", keep)
+}
+
+// writeDocumentText writes a document, taking over its doc_name
+func writeDocumentText(client *MCPClient, serverURI, vdbName, collectionName, docName, text, source string, metadata map[string]interface{}) error {
+	return safeCall(serverURI, func() error {
+		return client.WriteDocumentText(vdbName, collectionName, docName, text, source, metadata)
+	})
+}
+
+// deleteDocumentName deletes a document by name
+func deleteDocumentName(client *MCPClient, serverURI, vdbName, collectionName, docName string) error {
+	return safeCall(serverURI, func() error {
+		return client.DeleteDocumentFromCollection(vdbName, collectionName, docName)
+	})
+}
+
+// copyMetadata returns a copy of metadata without the doc_name key
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		if key != "doc_name" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+// containsName reports whether names contains name
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags for collection update
+var (
+	updateChunkStrategy      string
+	updateChunkSize          int
+	updateChunkOverlap       int
+	updateChunkingConfigFile string
+	updateRechunk            bool
+	updateBackup             string
+	updateNoBackup           bool
+	updateConcurrency        int
+)
+
+var collectionUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Change a collection's chunking configuration",
+	Long: `Change the chunking configuration of an existing collection.
+
+The server cannot alter a collection's configuration in place, so the collection is recreated
+with its embedding and the new chunking configuration. A collection that holds documents can only
+be updated with --rechunk, which reads every document, writes a backup archive, recreates the
+collection and re-ingests the documents through the new strategy.
+
+If --chunking-strategy is omitted, the current strategy is kept and the given parameters replace
+its parameters. With --dry-run, a report of the expected chunk-count change per document is
+printed; Fixed and Sentence counts are computed locally, Semantic counts cannot be estimated.
+
+The backup archive can be restored with 'maestro vectordb import BACKUP --resume'.
+
+Only the chunking configuration can be changed. The server's create_collection tool takes nothing
+but an embedding and a chunking configuration and no tool sets collection metadata, so there is no
+collection metadata to update; document metadata is kept as written.`,
+	Example: `  maestro collection update --vdb=my-vdb --name=docs --chunking-strategy=Sentence --chunk-size=512 --dry-run
+  maestro collection update --vdb=my-vdb --name=docs --chunking-strategy=Sentence --chunk-size=512 --rechunk
+  maestro collection update --vdb=my-vdb --name=docs --chunk-overlap=64 --rechunk --backup=docs-backup.tar.gz
+  maestro collection update --vdb=my-vdb --name=docs --chunking-config=chunking.yaml --rechunk --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("name")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return updateCollection(vdbName, collectionName)
+	},
+}
+
+func init() {
+	collectionUpdateCmd.Flags().String("vdb", "", "Vector database name")
+	collectionUpdateCmd.Flags().String("name", "", "Collection name")
+	collectionUpdateCmd.Flags().StringVar(&updateChunkStrategy, "chunking-strategy", "", "New chunking strategy (None, Fixed, Sentence, Semantic; default: keep the current strategy)")
+	collectionUpdateCmd.Flags().IntVar(&updateChunkSize, "chunk-size", 0, "Chunk size in characters")
+	collectionUpdateCmd.Flags().IntVar(&updateChunkOverlap, "chunk-overlap", 0, "Chunk overlap in characters")
+	collectionUpdateCmd.Flags().StringVar(&updateChunkingConfigFile, "chunking-config", "", "YAML or JSON file with the chunking strategy and parameters")
+	collectionUpdateCmd.Flags().StringVar(&semanticModel, "semantic-model", "", "Semantic chunking model identifier (e.g., all-MiniLM-L6-v2)")
+	collectionUpdateCmd.Flags().IntVar(&semanticWindowSize, "semantic-window-size", 0, "Semantic chunking window size (optional)")
+	collectionUpdateCmd.Flags().Float64Var(&semanticThresholdPercentile, "semantic-threshold-percentile", 0, "Semantic chunking threshold percentile (0-100, optional)")
+	collectionUpdateCmd.Flags().BoolVar(&updateRechunk, "rechunk", false, "Re-ingest existing documents through the new chunking strategy")
+	collectionUpdateCmd.Flags().StringVar(&updateBackup, "backup", "", "Backup archive to write before re-chunking (default VDB-COLLECTION-TIMESTAMP.backup.tar.gz)")
+	collectionUpdateCmd.Flags().BoolVar(&updateNoBackup, "no-backup", false, "Do not write a backup archive before re-chunking")
+	collectionUpdateCmd.Flags().IntVar(&updateConcurrency, "concurrency", defaultConcurrency, "Number of documents to read and write in parallel")
+}
+
+func updateCollection(vdbName, collectionName string) error {
+	keepStrategy := updateChunkStrategy == "" && updateChunkingConfigFile == ""
+	requested, err := resolveChunkingConfig(updateChunkStrategy, updateChunkSize, updateChunkOverlap, updateChunkingConfigFile)
+	if err != nil {
+		return err
+	}
+	if keepStrategy && requested == nil {
+		return fmt.Errorf("nothing to update: specify --chunking-strategy, --chunk-size, --chunk-overlap, --semantic-* or --chunking-config")
+	}
+
+	if verbose {
+		fmt.Printf("Updating chunking of collection '%s' in vector database '%s'...\n", collectionName, vdbName)
+	}
+
+	if dryRun {
+		// Without a server, validate against the built-in strategy list
+		if !keepStrategy {
+			if err := validateChunkingConfig(requested, builtinChunkingStrategies); err != nil {
+				return err
+			}
+		}
+		if !silent {
+			target := describeChunkingConfig(requested)
+			if keepStrategy {
+				params, _ := requested["parameters"].(map[string]interface{})
+				target = "the current strategy with " + describeChunkingParams(params)
+			}
+			fmt.Printf("[DRY RUN] Would update chunking of collection '%s' in vector database '%s' to %s\n", collectionName, vdbName, target)
+			if updateRechunk {
+				fmt.Printf("[DRY RUN] Would re-ingest every document of collection '%s' through the new chunking strategy\n", collectionName)
+			}
+		}
+
+		// The chunk-count report only reads from the server; it is skipped when the server is unavailable
+		if err := reportRechunkPlan(vdbName, collectionName, requested, keepStrategy); err != nil {
+			fmt.Fprintf(os.Stderr, "Chunk-count report unavailable: %v\n", err)
+		}
+		return nil
+	}
+
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	database, err := fetchDatabase(client, serverURI, vdbName)
+	if err != nil {
+		return err
+	}
+	info, err := fetchCollectionInfo(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+
+	target, err := updatedChunkingConfig(client, serverURI, info, requested, keepStrategy)
+	if err != nil {
+		return err
+	}
+	current := info.ChunkingConfig()
+	if describeChunkingConfig(current) == describeChunkingConfig(target) {
+		if !silent {
+			fmt.Printf("Collection '%s' already uses chunking %s; nothing to update\n", collectionName, describeChunkingConfig(current))
+		}
+		return nil
+	}
+
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 && !updateRechunk {
+		return fmt.Errorf("collection '%s' has %d document(s); changing its chunking recreates the collection, so rerun with --rechunk to re-ingest them (use --dry-run to see the expected chunk counts)", collectionName, len(names))
+	}
+
+	documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, names, updateConcurrency)
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+		}
+		return fmt.Errorf("failed to read %d of %d document(s); collection '%s' was not changed", len(failures), len(names), collectionName)
+	}
+
+	backupPath := ""
+	if len(documents) > 0 && !updateNoBackup {
+		backupPath = updateBackup
+		if backupPath == "" {
+			backupPath = defaultBackupPath(vdbName, collectionName)
+		}
+		if err := writeCollectionBackup(backupPath, database, info, documents); err != nil {
+			return err
+		}
+		if !silent {
+			fmt.Printf("Backed up %d document(s) to '%s'\n", len(documents), backupPath)
+		}
+	}
+
+	if len(documents) > 0 {
+		if err := confirmDestructiveOperation("recreate", fmt.Sprintf("collection '%s' in vector database '%s' with chunking %s", collectionName, vdbName, describeChunkingConfig(target))); err != nil {
+			return err
+		}
+	}
+
+	restoreHint := ""
+	if backupPath != "" {
+		restoreHint = fmt.Sprintf("; restore it with 'maestro vectordb import %s --resume'", backupPath)
+	}
+
+	embedding := info.Embedding
+	if embedding == "" {
+		embedding = "default"
+	}
+	if err := safeCall(serverURI, func() error {
+		return client.DeleteCollection(vdbName, collectionName)
+	}); err != nil {
+		return fmt.Errorf("failed to delete collection '%s': %w", collectionName, err)
+	}
+	if err := safeCall(serverURI, func() error {
+		return client.CreateCollectionWithChunking(vdbName, collectionName, embedding, target)
+	}); err != nil {
+		return fmt.Errorf("failed to recreate collection '%s' with the new chunking: %w%s", collectionName, err, restoreHint)
+	}
+
+	failures = rewriteDocuments(client, serverURI, vdbName, collectionName, documents, updateConcurrency)
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  ❌ %s\n", failure)
+		}
+		return fmt.Errorf("%d of %d document(s) failed to re-ingest into collection '%s'%s", len(failures), len(documents), collectionName, restoreHint)
+	}
+
+	if len(documents) > 0 {
+		written, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+		if err != nil {
+			return err
+		}
+		if len(written) != len(documents) {
+			return fmt.Errorf("document count mismatch after re-chunking: expected %d document(s), collection '%s' has %d%s", len(documents), collectionName, len(written), restoreHint)
+		}
+	}
+
+	if !silent {
+		fmt.Printf("✅ Collection '%s' in vector database '%s' now uses chunking %s", collectionName, vdbName, describeChunkingConfig(target))
+		if len(documents) > 0 {
+			fmt.Printf("; re-ingested %d document(s)", len(documents))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// updatedChunkingConfig applies the requested change to the collection's current chunking and validates it
+func updatedChunkingConfig(client *MCPClient, serverURI string, info *CollectionInfo, requested map[string]interface{}, keepStrategy bool) (map[string]interface{}, error) {
+	// Validation normalizes the config, so the caller's map is copied first
+	target := maps.Clone(requested)
+	if params, ok := requested["parameters"].(map[string]interface{}); ok {
+		target["parameters"] = maps.Clone(params)
+	}
+	if target == nil && keepStrategy {
+		target = make(map[string]interface{})
+	}
+	if keepStrategy {
+		strategy := "None"
+		if current := info.ChunkingConfig(); current != nil {
+			strategy, _ = current["strategy"].(string)
+		}
+		target["strategy"] = strategy
+	}
+
+	if err := validateChunkingConfig(target, fetchChunkingStrategies(client, serverURI)); err != nil {
+		return nil, err
+	}
+	if target != nil && target["strategy"] == "None" {
+		return nil, nil
+	}
+	return target, nil
+}
+
+// reportRechunkPlan prints the expected chunk-count change of every document without changing anything
+func reportRechunkPlan(vdbName, collectionName string, requested map[string]interface{}, keepStrategy bool) error {
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	info, err := fetchCollectionInfo(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	target, err := updatedChunkingConfig(client, serverURI, info, requested, keepStrategy)
+	if err != nil {
+		return err
+	}
+	names, err := fetchDocumentNames(client, serverURI, vdbName, collectionName)
+	if err != nil {
+		return err
+	}
+	documents, failures := fetchDocuments(client, serverURI, vdbName, collectionName, names, updateConcurrency)
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to read %d document(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+
+	if !silent {
+		printChunkCountReport(collectionName, info.ChunkingConfig(), target, documents)
+	}
+	return nil
+}
+
+// printChunkCountReport prints per-document chunk counts under the current and new chunking
+func printChunkCountReport(collectionName string, current, target map[string]interface{}, documents []DocumentRecord) {
+	fmt.Printf("\nChunk-count report for collection '%s': %s → %s\n", collectionName, describeChunkingConfig(current), describeChunkingConfig(target))
+	if len(documents) == 0 {
+		fmt.Println("  Collection has no documents")
+		return
+	}
+
+	width := len("DOCUMENT")
+	for _, doc := range documents {
+		width = max(width, len(doc.Name))
+	}
+
+	format := fmt.Sprintf("  %%-%ds %%8s %%8s %%8s\n", width)
+	fmt.Printf(format, "DOCUMENT", "BEFORE", "AFTER", "CHANGE")
+
+	totalBefore, totalAfter := 0, 0
+	unknown := false
+	for _, doc := range documents {
+		before, beforeKnown := estimateChunkCount(current, doc.Text)
+		after, afterKnown := estimateChunkCount(target, doc.Text)
+		totalBefore += before
+		totalAfter += after
+		unknown = unknown || !beforeKnown || !afterKnown
+		fmt.Printf(format, doc.Name, chunkCountLabel(before, beforeKnown), chunkCountLabel(after, afterKnown), chunkChangeLabel(before, after, beforeKnown && afterKnown))
+	}
+
+	_, beforeKnown := estimateChunkCount(current, "")
+	_, afterKnown := estimateChunkCount(target, "")
+	fmt.Printf(format, fmt.Sprintf("Total (%d)", len(documents)), chunkCountLabel(totalBefore, beforeKnown), chunkCountLabel(totalAfter, afterKnown), chunkChangeLabel(totalBefore, totalAfter, beforeKnown && afterKnown))
+	if unknown {
+		fmt.Println("  ? Semantic chunk counts depend on embeddings and cannot be estimated locally")
+	}
+}
+
+func chunkCountLabel(count int, known bool) string {
+	if !known {
+		return "?"
+	}
+	return fmt.Sprintf("%d", count)
+}
+
+func chunkChangeLabel(before, after int, known bool) string {
+	if !known {
+		return "?"
+	}
+	return fmt.Sprintf("%+d", after-before)
+}
+
+// defaultBackupPath names a backup archive after the collection and the current time
+func defaultBackupPath(vdbName, collectionName string) string {
+	clean := strings.NewReplacer("/", "_", "\\", "_", " ", "_")
+	return fmt.Sprintf("%s-%s-%s.backup.tar.gz", clean.Replace(vdbName), clean.Replace(collectionName), time.Now().UTC().Format("20060102T150405Z"))
+}
+
+// writeCollectionBackup writes a collection and its documents as an export archive
+func writeCollectionBackup(backupPath string, database *DatabaseInfo, info *CollectionInfo, documents []DocumentRecord) error {
+	collection := ExportCollection{
+		Name:          info.Name,
+		Embedding:     info.Embedding,
+		Chunking:      info.ChunkingConfig(),
+		DocumentCount: len(documents),
+	}
+	for _, doc := range documents {
+		collection.Documents = append(collection.Documents, ExportDocument{Name: doc.Name, File: documentArchivePath(info.Name, doc.Name)})
+	}
+
+	manifest := &ExportManifest{
+		FormatVersion: exportFormatVersion,
+		CLIVersion:    version,
+		ExportedAt:    time.Now().UTC(),
+		Database: ExportDatabase{
+			Name:       database.Name,
+			Type:       database.Type,
+			Collection: database.Collection,
+		},
+		Collections: []ExportCollection{collection},
+	}
+
+	archive, err := createExportArchive(backupPath)
+	if err != nil {
+		return err
+	}
+	if err := archive.writeJSON(exportManifestName, manifest); err != nil {
+		archive.discard()
+		return err
+	}
+	for i := range documents {
+		if err := archive.writeJSON(collection.Documents[i].File, documents[i]); err != nil {
+			archive.discard()
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write backup '%s': %w", backupPath, err)
+	}
+	return nil
+}
+
+// rewriteDocuments writes documents back into a collection and returns a list of failures
+func rewriteDocuments(client *MCPClient, serverURI, vdbName, collectionName string, documents []DocumentRecord, concurrency int) []string {
+	progress := newBulkProgress(fmt.Sprintf("Re-ingesting %d document(s) into '%s'...", len(documents), collectionName), len(documents))
+
+	var mu sync.Mutex
+	var failures []string
+	runConcurrently(documents, concurrency, func(doc DocumentRecord) {
+		defer progress.Increment()
+		err := safeCall(serverURI, func() error {
+			return client.WriteDocumentText(vdbName, collectionName, doc.Name, doc.Text, doc.URL, doc.Metadata)
+		})
+		if err != nil {
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", doc.Name, err))
+			mu.Unlock()
+		}
+	})
+
+	progress.Stop(fmt.Sprintf("Re-ingested %d document(s)", len(documents)-len(failures)), len(failures))
+	return failures
+}
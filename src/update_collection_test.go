@@ -0,0 +1,95 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEstimateChunkCount(t *testing.T) {
+	text := strings.Repeat("word ", 200) // 1000 characters
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected int
+		known    bool
+	}{
+		{"no chunking", nil, 1, true},
+		{"fixed with default size", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{}}, 2, true},
+		{"fixed from server JSON", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 250.0}}, 4, true},
+		{"fixed with overlap", map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 250, "overlap": 50}}, 5, true},
+		{"semantic", map[string]interface{}{"strategy": "Semantic", "parameters": map[string]interface{}{}}, 0, false},
+	}
+
+	for _, tt := range tests {
+		count, known := estimateChunkCount(tt.config, text)
+		if count != tt.expected || known != tt.known {
+			t.Errorf("%s: estimateChunkCount() = (%d, %v), expected (%d, %v)", tt.name, count, known, tt.expected, tt.known)
+		}
+	}
+}
+
+func TestDescribeChunkingConfig(t *testing.T) {
+	tests := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{nil, "None"},
+		{map[string]interface{}{"strategy": "Sentence", "parameters": map[string]interface{}{}}, "Sentence"},
+		{map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"overlap": 32, "chunk_size": 512.0}}, "Fixed (chunk_size=512, overlap=32)"},
+	}
+
+	for _, tt := range tests {
+		if result := describeChunkingConfig(tt.config); result != tt.expected {
+			t.Errorf("describeChunkingConfig(%v) = %q, expected %q", tt.config, result, tt.expected)
+		}
+	}
+}
+
+func TestWriteCollectionBackup(t *testing.T) {
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	database := &DatabaseInfo{Name: "my-vdb", Type: "milvus", Collection: "docs"}
+	info := &CollectionInfo{
+		Name:      "docs",
+		Embedding: "default",
+		Chunking:  map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 256.0}},
+	}
+	documents := []DocumentRecord{{Name: "a", Text: "alpha"}, {Name: "b", Text: "beta"}}
+
+	if err := writeCollectionBackup(backupPath, database, info, documents); err != nil {
+		t.Fatalf("writeCollectionBackup() returned error: %v", err)
+	}
+
+	manifest, err := readExportManifest(backupPath)
+	if err != nil {
+		t.Fatalf("readExportManifest() returned error: %v", err)
+	}
+	if manifest.Database.Name != "my-vdb" || len(manifest.Collections) != 1 {
+		t.Fatalf("Unexpected backup manifest: %+v", manifest)
+	}
+	collection := manifest.Collections[0]
+	if collection.Name != "docs" || collection.DocumentCount != 2 || collection.Chunking["strategy"] != "Fixed" {
+		t.Errorf("Unexpected backup collection: %+v", collection)
+	}
+}
+
+func TestUpdatedChunkingConfigCopiesRequest(t *testing.T) {
+	client := newFakeDocumentStore(t, &fakeDocumentStore{docs: map[string]string{}})
+	info := &CollectionInfo{Chunking: map[string]interface{}{"strategy": "Fixed", "parameters": map[string]interface{}{"chunk_size": 512.0}}}
+	requested := map[string]interface{}{"parameters": map[string]interface{}{"chunk_size": 256.0}}
+
+	target, err := updatedChunkingConfig(client, "fake", info, requested, true)
+	if err != nil {
+		t.Fatalf("updatedChunkingConfig() error = %v", err)
+	}
+	if target["strategy"] != "Fixed" {
+		t.Errorf("target = %v, want the current Fixed strategy", target)
+	}
+	// the request is reused for the dry-run report and must not change
+	want := map[string]interface{}{"parameters": map[string]interface{}{"chunk_size": 256.0}}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested = %v after the call, want %v", requested, want)
+	}
+}
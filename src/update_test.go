@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentVersions(t *testing.T) {
+	names := []string{"guide", "guide@v10", "guide@v2", "guide@vx", "guide@v02", "guide@pending", "guide-old@v1", "other@v1"}
+
+	versions := documentVersions(names, "guide")
+	expected := []DocumentVersion{{Version: 2, Document: "guide@v2"}, {Version: 10, Document: "guide@v10"}}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("documentVersions() = %+v, expected %+v", versions, expected)
+	}
+	if name := documentVersionName("guide", 11); name != "guide@v11" {
+		t.Errorf("documentVersionName() = %q", name)
+	}
+}
+
+func TestValidateDocumentName(t *testing.T) {
+	for _, name := range []string{"guide", "user@example.com", "notes@vacation"} {
+		if err := validateDocumentName(name); err != nil {
+			t.Errorf("validateDocumentName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"guide@v3", "guide@pending"} {
+		if err := validateDocumentName(name); err == nil {
+			t.Errorf("validateDocumentName(%q) expected an error", name)
+		}
+	}
+}
+
+func TestReplaceStoredDocumentSplit(t *testing.T) {
+	store := &fakeDocumentStore{docs: map[string]string{"big.md#part-1": "one", "big.md#part-2": "two", "other.md": "other"}}
+	client := newFakeDocumentStore(t, store)
+	names := []string{"big.md#part-2", "big.md#part-1", "other.md"}
+	if !documentExists(names, "big.md") || documentExists(names, "big") {
+		t.Fatal("documentExists() should find a split document by its name only")
+	}
+
+	result, err := replaceStoredDocument(client, "fake", "vdb", "docs", "big.md", "new", map[string]interface{}{}, names, 0)
+	if err != nil {
+		t.Fatalf("replaceStoredDocument() error = %v", err)
+	}
+	if result.Parts != 2 {
+		t.Errorf("replaced parts = %d, want 2", result.Parts)
+	}
+	if text, _ := store.text("big.md"); text != "new" {
+		t.Errorf("big.md = %q, want the new content", text)
+	}
+	for _, part := range names[:2] {
+		if _, ok := store.text(part); ok {
+			t.Errorf("old part %s should be deleted", part)
+		}
+	}
+	if text, _ := store.text("other.md"); text != "other" {
+		t.Error("other documents should be left alone")
+	}
+}
+
+func TestReplaceStoredDocumentFailedWriteKeepsParts(t *testing.T) {
+	store := &fakeDocumentStore{docs: map[string]string{"big.md#part-1": "one"}, failWrites: map[string]int{"big.md": 1}}
+	client := newFakeDocumentStore(t, store)
+
+	if _, err := replaceStoredDocument(client, "fake", "vdb", "docs", "big.md", "new", nil, []string{"big.md#part-1"}, 0); err == nil {
+		t.Fatal("replaceStoredDocument() should fail when the write fails")
+	}
+	if text, _ := store.text("big.md#part-1"); text != "one" {
+		t.Errorf("the parts should be left unchanged when the write fails, got %q", text)
+	}
+}
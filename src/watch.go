@@ -0,0 +1,464 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// maxWatchRetries bounds --retries; with the capped delays a failing change is retried for minutes
+const maxWatchRetries = 10
+
+// Flags for document watch
+var (
+	watchDebounce   time.Duration
+	watchRetries    int
+	watchRetryDelay time.Duration
+	watchLogFormat  string
+)
+
+var documentWatchCmd = &cobra.Command{
+	Use:   "watch DIR",
+	Short: "Continuously sync a directory into a collection",
+	Long: `Watch a directory and keep a collection mirroring it, one document per file.
+
+On start the directory is synced as with 'document sync' (documents without a local file are only
+deleted with --prune). Afterwards filesystem events are debounced and coalesced: once no event has
+arrived for --debounce, the changed files are written, re-written or deleted. Files removed while
+watching are always deleted from the collection.
+
+Failed MCP calls are retried --retries times with exponential backoff starting at --retry-delay;
+changes that still fail are queued for the next batch and the server connection is re-established.
+Progress is logged to stderr as text or JSON (--log-format). SIGINT or SIGTERM applies the pending
+changes and stops the watch.`,
+	Example: `  maestro document watch ./site/public --vdb=my-vdb --collection=site
+  maestro document watch ./docs --vdb=my-vdb --collection=docs --include='*.md' --debounce=2s
+  maestro document watch ./docs --vdb=my-vdb --collection=docs --log-format=json --prune --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		vdbName, _ := cmd.Flags().GetString("vdb")
+		collectionName, _ := cmd.Flags().GetString("collection")
+
+		// Use interactive selection if vdb or collection name is not provided
+		if vdbName == "" {
+			var err error
+			vdbName, err = PromptForVectorDatabase(vdbName)
+			if err != nil {
+				return fmt.Errorf("failed to select vector database: %w", err)
+			}
+		}
+		if collectionName == "" {
+			var err error
+			collectionName, err = PromptForCollection(vdbName, collectionName)
+			if err != nil {
+				return fmt.Errorf("failed to select collection: %w", err)
+			}
+		}
+
+		return watchDocuments(vdbName, collectionName, args[0])
+	},
+}
+
+func init() {
+	documentWatchCmd.Flags().String("vdb", "", "Vector database name")
+	documentWatchCmd.Flags().String("collection", "", "Collection name")
+	// File selection is shared with document sync
+	documentWatchCmd.Flags().StringSliceVar(&syncInclude, "include", nil, "Only sync files matching these globs (repeatable)")
+	documentWatchCmd.Flags().StringSliceVar(&syncExclude, "exclude", nil, "Skip files matching these globs (repeatable)")
+	documentWatchCmd.Flags().BoolVar(&syncHidden, "hidden", false, "Include hidden files and directories")
+	documentWatchCmd.Flags().BoolVar(&syncPrune, "prune", false, "On start, delete documents whose files no longer exist locally")
+	documentWatchCmd.Flags().BoolVar(&extractRaw, "raw", false, "Send file contents as-is instead of extracting their text")
+	addMetadataFlags(documentWatchCmd)
+	addMaxSizeFlag(documentWatchCmd)
+	addRedactFlags(documentWatchCmd)
+	documentWatchCmd.Flags().IntVar(&syncConcurrency, "concurrency", defaultConcurrency, "Number of files to sync in parallel")
+	documentWatchCmd.Flags().DurationVar(&watchDebounce, "debounce", 500*time.Millisecond, "Quiet period before changes are applied")
+	documentWatchCmd.Flags().IntVar(&watchRetries, "retries", 3, "Retries for a failed MCP call (at most 10)")
+	documentWatchCmd.Flags().DurationVar(&watchRetryDelay, "retry-delay", time.Second, "Delay before the first retry, doubled on each attempt up to 1m")
+	documentWatchCmd.Flags().StringVar(&watchLogFormat, "log-format", "text", "Log format (text, json)")
+}
+
+// dirWatcher mirrors a directory into a collection as files change
+type dirWatcher struct {
+	ctx            context.Context // cancelled on shutdown, which stops the waits between retries
+	dir            string
+	vdbName        string
+	collectionName string
+	logger         *slog.Logger
+
+	client    *MCPClient
+	serverURI string
+	reconnect bool
+
+	mu     sync.Mutex
+	remote map[string]string // document name → content hash of the synced documents
+}
+
+func watchDocuments(vdbName, collectionName, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory '%s': %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", dir)
+	}
+	for _, pattern := range append(append([]string{}, syncInclude...), syncExclude...) {
+		if err := validateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	logger, err := newWatchLogger(watchLogFormat)
+	if err != nil {
+		return err
+	}
+	if watchDebounce <= 0 {
+		return fmt.Errorf("--debounce must be positive")
+	}
+	if watchRetries < 0 || watchRetries > maxWatchRetries {
+		return fmt.Errorf("--retries must be between 0 and %d", maxWatchRetries)
+	}
+	if watchRetryDelay <= 0 || watchRetryDelay > maxRetryDelay {
+		return fmt.Errorf("--retry-delay must be positive and at most %s", maxRetryDelay)
+	}
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		if !silent {
+			fmt.Printf("[DRY RUN] Would watch '%s' and sync changes to collection '%s' of vector database '%s'\n", dir, collectionName, vdbName)
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start filesystem watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := addWatchDirs(watcher, dir); err != nil {
+		return err
+	}
+
+	w := &dirWatcher{ctx: ctx, dir: dir, vdbName: vdbName, collectionName: collectionName, logger: logger}
+	if err := w.connect(); err != nil {
+		return err
+	}
+	defer func() {
+		if w.client != nil {
+			w.client.Close()
+		}
+	}()
+
+	if err := w.initialSync(); err != nil {
+		return err
+	}
+	logger.Info("watching", "dir", dir, "vdb", vdbName, "collection", collectionName, "debounce", watchDebounce.String())
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("shutting down", "pending", len(pending))
+			if len(pending) > 0 {
+				w.flush(pending)
+			}
+			logger.Info("stopped")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			for _, name := range w.handleEvent(watcher, event) {
+				pending[name] = true
+			}
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Error("watch error", "error", err)
+
+		case <-timer.C:
+			failed := w.flush(pending)
+			pending = make(map[string]bool)
+			if len(failed) > 0 {
+				for _, name := range failed {
+					pending[name] = true
+				}
+				retryIn := backoffDelay(watchRetryDelay, watchRetries)
+				logger.Warn("requeued failed changes", "count", len(failed), "retry_in", retryIn.String())
+				timer.Reset(retryIn)
+			}
+		}
+	}
+}
+
+// newWatchLogger creates the structured logger of document watch
+func newWatchLogger(format string) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	if silent {
+		level = slog.LevelWarn
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, options)), nil
+	}
+	return nil, fmt.Errorf("unsupported log format '%s' (use text or json)", format)
+}
+
+// addWatchDirs watches root and every directory below it that the sync filters do not hide
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(dirPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if dirPath != root && !syncHidden && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(dirPath); err != nil {
+			return fmt.Errorf("failed to watch '%s': %w", dirPath, err)
+		}
+		return nil
+	})
+}
+
+// connect (re)creates the MCP session
+func (w *dirWatcher) connect() error {
+	if w.client != nil {
+		w.client.Close()
+		w.client = nil
+	}
+	client, serverURI, err := connectMCPSession()
+	if err != nil {
+		return err
+	}
+	w.client, w.serverURI, w.reconnect = client, serverURI, false
+	return nil
+}
+
+// initialSync brings the collection up to date with the directory before watching
+func (w *dirWatcher) initialSync() error {
+	files, err := collectIngestFiles([]string{w.dir}, syncInclude, syncExclude, syncHidden)
+	if err != nil {
+		return err
+	}
+	local, err := hashLocalFiles(files)
+	if err != nil {
+		return err
+	}
+
+	var remote map[string]string
+	err = retryWithBackoff(w.ctx, watchRetries, watchRetryDelay, func() error {
+		var fetchErr error
+		remote, fetchErr = fetchSyncedHashes(w.client, w.serverURI, w.vdbName, w.collectionName)
+		return fetchErr
+	}, w.logRetry("fetch documents", ""))
+	if err != nil {
+		return err
+	}
+	w.remote = remote
+
+	plan := planSync(local, remote, syncPrune)
+	if deletions := plan.count(syncDelete); deletions > 0 {
+		if err := confirmDestructiveOperation("delete", fmt.Sprintf("%d document(s) from collection '%s'", deletions, w.collectionName)); err != nil {
+			return err
+		}
+	}
+	failed := w.apply(plan)
+	w.logger.Info("initial sync", "added", plan.count(syncAdd), "updated", plan.count(syncUpdate), "deleted", plan.count(syncDelete),
+		"unchanged", plan.Unchanged, "kept", len(plan.Extraneous), "failed", len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("initial sync failed for %d file(s)", len(failed))
+	}
+	return nil
+}
+
+// handleEvent returns the document names affected by a filesystem event
+func (w *dirWatcher) handleEvent(watcher *fsnotify.Watcher, event fsnotify.Event) []string {
+	rel, err := filepath.Rel(w.dir, event.Name)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	name := filepath.ToSlash(rel)
+	if !syncHidden && isHiddenPath(name) {
+		return nil
+	}
+	w.logger.Debug("event", "op", event.Op.String(), "path", event.Name)
+
+	// A new directory is watched, and the files already in it are picked up
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := addWatchDirs(watcher, event.Name); err != nil {
+				w.logger.Error("watch error", "error", err)
+			}
+			files, err := collectIngestFiles([]string{event.Name}, nil, nil, syncHidden)
+			if err != nil {
+				w.logger.Error("scan error", "path", event.Name, "error", err)
+				return nil
+			}
+			var names []string
+			for _, file := range files {
+				names = append(names, name+"/"+file.Name)
+			}
+			return names
+		}
+	}
+
+	// A removed or renamed directory takes its documents with it
+	names := []string{name}
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		w.mu.Lock()
+		for remoteName := range w.remote {
+			if strings.HasPrefix(remoteName, name+"/") {
+				names = append(names, remoteName)
+			}
+		}
+		w.mu.Unlock()
+	}
+	return names
+}
+
+// flush applies the changes of the pending document names and returns the names that failed
+func (w *dirWatcher) flush(pending map[string]bool) []string {
+	if w.reconnect || w.client == nil {
+		if err := w.connect(); err != nil {
+			w.logger.Error("reconnect failed", "error", err)
+			return sortedKeys(pending)
+		}
+		w.logger.Info("reconnected", "server", w.serverURI)
+	}
+
+	local := make(map[string]syncLocalFile)
+	remote := make(map[string]string)
+	var unreadable []string
+	w.mu.Lock()
+	for name := range pending {
+		if hash, ok := w.remote[name]; ok {
+			remote[name] = hash
+		}
+	}
+	w.mu.Unlock()
+
+	for _, name := range sortedKeys(pending) {
+		filePath := filepath.Join(w.dir, filepath.FromSlash(name))
+		info, err := os.Stat(filePath)
+		if err != nil || !info.Mode().IsRegular() || !matchesFilters(name, syncInclude, syncExclude) {
+			continue
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			w.logger.Error("read failed", "path", filePath, "error", err)
+			unreadable = append(unreadable, name)
+			continue
+		}
+		local[name] = syncLocalFile{File: ingestFile{Path: filePath, Name: name, Size: info.Size()}, Hash: contentHash(content)}
+	}
+	// Documents that failed to read are left alone rather than deleted
+	for _, name := range unreadable {
+		delete(remote, name)
+	}
+
+	plan := planSync(local, remote, true)
+	w.mu.Lock()
+	plan.Documents = sortedKeys(w.remote)
+	w.mu.Unlock()
+	if len(plan.Changes) == 0 {
+		w.logger.Debug("no changes", "events", len(pending))
+		return unreadable
+	}
+	return append(w.apply(plan), unreadable...)
+}
+
+// apply applies a plan with retries, updates the known document hashes and returns the failed names
+func (w *dirWatcher) apply(plan *syncPlan) []string {
+	var mu sync.Mutex
+	var failed []string
+	runConcurrently(plan.Changes, syncConcurrency, func(change syncChange) {
+		start := time.Now()
+		var hash string
+		var dropErr error
+		err := retryWithBackoff(w.ctx, watchRetries, watchRetryDelay, func() error {
+			var applyErr error
+			hash, applyErr = applySyncChange(w.client, w.serverURI, w.vdbName, w.collectionName, change, plan.Documents)
+			if isRedactionDrop(applyErr) {
+				// Retrying would drop the document again
+				dropErr, applyErr = applyErr, nil
+			}
+			return applyErr
+		}, w.logRetry(change.Kind, change.File.Name))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if dropErr != nil {
+			w.logger.Warn("dropped", "document", change.File.Name, "reason", dropErr.Error())
+			w.mu.Lock()
+			delete(w.remote, change.File.Name)
+			w.mu.Unlock()
+			return
+		}
+		if err != nil {
+			w.logger.Error(change.Kind+" failed", "document", change.File.Name, "error", err)
+			failed = append(failed, change.File.Name)
+			w.reconnect = true
+			return
+		}
+		w.mu.Lock()
+		if change.Kind == syncDelete {
+			delete(w.remote, change.File.Name)
+		} else {
+			w.remote[change.File.Name] = hash
+		}
+		w.mu.Unlock()
+		w.logger.Info(change.Kind, "document", change.File.Name, "bytes", change.File.Size, "duration", time.Since(start).Round(time.Millisecond).String())
+	})
+	return failed
+}
+
+// logRetry returns a callback that logs a retried operation
+func (w *dirWatcher) logRetry(operation, document string) func(int, time.Duration, error) {
+	return func(attempt int, delay time.Duration, err error) {
+		attrs := []any{"operation", operation, "attempt", attempt, "delay", delay.String(), "error", err}
+		if document != "" {
+			attrs = append(attrs, "document", document)
+		}
+		w.logger.Warn("retrying", attrs...)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestRetryWithBackoff(t *testing.T) {
+	calls := 0
+	var delays []time.Duration
+	err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, func(attempt int, delay time.Duration, err error) {
+		delays = append(delays, delay)
+	})
+
+	if err != nil || calls != 3 {
+		t.Errorf("retryWithBackoff() = %v after %d calls, expected success after 3", err, calls)
+	}
+	if !reflect.DeepEqual(delays, []time.Duration{time.Millisecond, 2 * time.Millisecond}) {
+		t.Errorf("retry delays = %v, expected doubling delays", delays)
+	}
+
+	calls = 0
+	err = retryWithBackoff(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		return errors.New("permanent")
+	}, nil)
+	if err == nil || calls != 3 {
+		t.Errorf("retryWithBackoff() = %v after %d calls, expected failure after 3", err, calls)
+	}
+
+	// a cancelled context ends the wait instead of sleeping through the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	start := time.Now()
+	err = retryWithBackoff(ctx, 5, time.Hour, func() error {
+		calls++
+		return errors.New("permanent")
+	}, nil)
+	if err == nil || calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("retryWithBackoff() = %v after %d calls in %s, expected to stop after the first call", err, calls, time.Since(start))
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	if delay := backoffDelay(time.Second, 3); delay != 8*time.Second {
+		t.Errorf("backoffDelay(1s, 3) = %s, expected 8s", delay)
+	}
+	// large retry counts used to overflow into a zero or negative delay
+	for _, doublings := range []int{6, 34, 64, 1000} {
+		if delay := backoffDelay(time.Second, doublings); delay != maxRetryDelay {
+			t.Errorf("backoffDelay(1s, %d) = %s, expected the %s cap", doublings, delay, maxRetryDelay)
+		}
+	}
+	if delay := backoffDelay(time.Hour, 0); delay != maxRetryDelay {
+		t.Errorf("backoffDelay(1h, 0) = %s, expected the %s cap", delay, maxRetryDelay)
+	}
+}
+
+func TestNewWatchLogger(t *testing.T) {
+	for _, format := range []string{"text", "json"} {
+		if _, err := newWatchLogger(format); err != nil {
+			t.Errorf("newWatchLogger(%q) returned error: %v", format, err)
+		}
+	}
+	if _, err := newWatchLogger("xml"); err == nil {
+		t.Error("newWatchLogger() should reject unknown formats")
+	}
+}
+
+func TestWatchHandleEventRemovedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	logger, _ := newWatchLogger("text")
+	w := &dirWatcher{dir: dir, logger: logger, remote: map[string]string{
+		"guide/a.md": "a", "guide/b.md": "b", "guides.md": "c", "other.md": "d",
+	}}
+
+	names := w.handleEvent(nil, fsnotify.Event{Name: filepath.Join(dir, "guide"), Op: fsnotify.Remove})
+	sort.Strings(names)
+	if expected := []string{"guide", "guide/a.md", "guide/b.md"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("handleEvent() = %v, expected %v", names, expected)
+	}
+
+	if names := w.handleEvent(nil, fsnotify.Event{Name: filepath.Join(dir, ".cache", "x"), Op: fsnotify.Write}); names != nil {
+		t.Errorf("handleEvent() for a hidden path = %v, expected nothing", names)
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// whereExpr is a compiled --where expression over document metadata. It is sent to the server as
+// a structured filter (see Filter) and can be evaluated locally when the server does not filter.
+type whereExpr struct {
+	Op       string // "and", "or", "not" or a comparison: "eq", "ne", "gt", "gte", "lt", "lte", "in"
+	Children []*whereExpr
+	Field    string
+	Values   []whereValue
+}
+
+// whereValue is a literal of a comparison; unquoted numbers compare numerically
+type whereValue struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+}
+
+// whereOperators maps comparison operators to filter ops, longest first so that '>=' wins over '>'
+var whereOperators = []struct{ symbol, op string }{
+	{"!=", "ne"}, {"<>", "ne"}, {">=", "gte"}, {"<=", "lte"}, {"==", "eq"},
+	{"=", "eq"}, {">", "gt"}, {"<", "lt"},
+}
+
+// whereToken is a lexical token of a --where expression
+type whereToken struct {
+	kind   string // "word", "string", "op", "(", ")", ","
+	text   string
+	offset int
+}
+
+// parseWhere compiles an expression such as "meta.product=foo AND meta.version>=2". Comparisons
+// (=, !=, >, >=, <, <=, IN (a, b)) can be combined with AND, OR, NOT and parentheses; the 'meta.'
+// prefix of keys is optional and values may be quoted with single or double quotes.
+func parseWhere(input string) (*whereExpr, error) {
+	tokens, err := tokenizeWhere(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty where expression")
+	}
+	p := &whereParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected '%s'", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+func tokenizeWhere(input string) ([]whereToken, error) {
+	var tokens []whereToken
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, whereToken{kind: string(c), text: string(c), offset: i})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(input[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d in where expression", i+1)
+			}
+			tokens = append(tokens, whereToken{kind: "string", text: input[i+1 : i+1+end], offset: i})
+			i += end + 2
+		default:
+			if op := matchWhereOperator(input[i:]); op != "" {
+				tokens = append(tokens, whereToken{kind: "op", text: op, offset: i})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(input) && isWhereWordByte(input[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d in where expression", c, i+1)
+			}
+			tokens = append(tokens, whereToken{kind: "word", text: input[start:i], offset: start})
+		}
+	}
+	return tokens, nil
+}
+
+func matchWhereOperator(s string) string {
+	for _, candidate := range whereOperators {
+		if strings.HasPrefix(s, candidate.symbol) {
+			return candidate.symbol
+		}
+	}
+	return ""
+}
+
+func isWhereWordByte(c byte) bool {
+	return c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || strings.IndexByte("_.-:/+@", c) >= 0
+}
+
+// whereParser is a recursive-descent parser: or := and (OR and)*, and := not (AND not)*,
+// not := NOT not | '(' or ')' | comparison
+type whereParser struct {
+	tokens []whereToken
+	pos    int
+}
+
+func (p *whereParser) errorf(format string, args ...interface{}) error {
+	position := "end"
+	if p.pos < len(p.tokens) {
+		position = fmt.Sprintf("position %d", p.tokens[p.pos].offset+1)
+	}
+	return fmt.Errorf("invalid where expression at %s: %s", position, fmt.Sprintf(format, args...))
+}
+
+// keyword consumes the next token if it is the given case-insensitive keyword
+func (p *whereParser) keyword(word string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == "word" && strings.EqualFold(p.tokens[p.pos].text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *whereParser) next(kind string) (whereToken, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind {
+		p.pos++
+		return p.tokens[p.pos-1], true
+	}
+	return whereToken{}, false
+}
+
+func (p *whereParser) parseOr() (*whereExpr, error) {
+	return p.parseChain("or", "OR", p.parseAnd)
+}
+
+func (p *whereParser) parseAnd() (*whereExpr, error) {
+	return p.parseChain("and", "AND", p.parseNot)
+}
+
+// parseChain parses operands joined by a keyword into a single node of op
+func (p *whereParser) parseChain(op, keyword string, operand func() (*whereExpr, error)) (*whereExpr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*whereExpr{first}
+	for p.keyword(keyword) {
+		child, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &whereExpr{Op: op, Children: children}, nil
+}
+
+func (p *whereParser) parseNot() (*whereExpr, error) {
+	if p.keyword("NOT") {
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &whereExpr{Op: "not", Children: []*whereExpr{child}}, nil
+	}
+	if _, ok := p.next("("); ok {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.next(")"); !ok {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *whereParser) parseComparison() (*whereExpr, error) {
+	key, ok := p.next("word")
+	if !ok || isWhereKeyword(key.text) {
+		if ok {
+			p.pos--
+		}
+		return nil, p.errorf("expected a metadata key")
+	}
+	field := strings.TrimPrefix(key.text, "meta.")
+	if field == "" {
+		return nil, p.errorf("empty metadata key")
+	}
+
+	if p.keyword("IN") {
+		if _, ok := p.next("("); !ok {
+			return nil, p.errorf("expected '(' after IN")
+		}
+		expr := &whereExpr{Op: "in", Field: field}
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			expr.Values = append(expr.Values, value)
+			if _, ok := p.next(","); !ok {
+				break
+			}
+		}
+		if _, ok := p.next(")"); !ok {
+			return nil, p.errorf("expected ',' or ')' in IN list")
+		}
+		return expr, nil
+	}
+
+	symbol, ok := p.next("op")
+	if !ok {
+		return nil, p.errorf("expected a comparison operator after '%s'", key.text)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range whereOperators {
+		if candidate.symbol == symbol.text {
+			return &whereExpr{Op: candidate.op, Field: field, Values: []whereValue{value}}, nil
+		}
+	}
+	return nil, p.errorf("unknown operator '%s'", symbol.text)
+}
+
+func (p *whereParser) parseValue() (whereValue, error) {
+	if token, ok := p.next("string"); ok {
+		return whereValue{Text: token.text}, nil
+	}
+	token, ok := p.next("word")
+	if !ok || isWhereKeyword(token.text) {
+		if ok {
+			p.pos--
+		}
+		return whereValue{}, p.errorf("expected a value")
+	}
+	value := whereValue{Text: token.text}
+	if number, err := strconv.ParseFloat(token.text, 64); err == nil {
+		value.Number, value.IsNumber = number, true
+	}
+	return value, nil
+}
+
+func isWhereKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "AND", "OR", "NOT", "IN":
+		return true
+	}
+	return false
+}
+
+// Filter returns the structured filter sent to the server: {"and": [...]}, {"or": [...]},
+// {"not": {...}} and {"field": "version", "op": "gte", "value": 2} for comparisons
+func (e *whereExpr) Filter() map[string]interface{} {
+	switch e.Op {
+	case "and", "or":
+		children := make([]interface{}, len(e.Children))
+		for i, child := range e.Children {
+			children[i] = child.Filter()
+		}
+		return map[string]interface{}{e.Op: children}
+	case "not":
+		return map[string]interface{}{"not": e.Children[0].Filter()}
+	case "in":
+		values := make([]interface{}, len(e.Values))
+		for i, value := range e.Values {
+			values[i] = value.JSON()
+		}
+		return map[string]interface{}{"field": e.Field, "op": e.Op, "value": values}
+	default:
+		return map[string]interface{}{"field": e.Field, "op": e.Op, "value": e.Values[0].JSON()}
+	}
+}
+
+// JSON returns the literal as a JSON number or string
+func (v whereValue) JSON() interface{} {
+	if v.IsNumber {
+		return v.Number
+	}
+	return v.Text
+}
+
+// Matches evaluates the expression against document metadata. As with --selector, a missing key
+// satisfies only '!='.
+func (e *whereExpr) Matches(metadata map[string]interface{}) bool {
+	switch e.Op {
+	case "and":
+		for _, child := range e.Children {
+			if !child.Matches(metadata) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, child := range e.Children {
+			if child.Matches(metadata) {
+				return true
+			}
+		}
+		return false
+	case "not":
+		return !e.Children[0].Matches(metadata)
+	}
+
+	actual, ok := metadata[e.Field]
+	if !ok || actual == nil {
+		return e.Op == "ne"
+	}
+	switch e.Op {
+	case "eq":
+		return compareWhereValue(actual, e.Values[0]) == 0
+	case "ne":
+		return compareWhereValue(actual, e.Values[0]) != 0
+	case "in":
+		for _, value := range e.Values {
+			if compareWhereValue(actual, value) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp := compareWhereValue(actual, e.Values[0])
+	switch e.Op {
+	case "gt":
+		return cmp > 0
+	case "gte":
+		return cmp >= 0 && cmp != whereIncomparable
+	case "lt":
+		return cmp < 0 && cmp != whereIncomparable
+	case "lte":
+		return cmp <= 0 && cmp != whereIncomparable
+	}
+	return false
+}
+
+// whereIncomparable is returned by compareWhereValue for values that have no order, such as a
+// list compared with a number
+const whereIncomparable = -2
+
+// compareWhereValue orders a metadata value against a literal: numerically when both are numbers,
+// otherwise by their text. Lists compare equal when any element does.
+func compareWhereValue(actual interface{}, value whereValue) int {
+	if list, ok := actual.([]interface{}); ok {
+		for _, element := range list {
+			if compareWhereValue(element, value) == 0 {
+				return 0
+			}
+		}
+		return whereIncomparable
+	}
+	if value.IsNumber {
+		if number, ok := metadataNumber(actual); ok {
+			switch {
+			case number < value.Number:
+				return -1
+			case number > value.Number:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(actual), value.Text)
+}
+
+// metadataNumber converts a numeric metadata value, including numbers stored as text
+func metadataNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
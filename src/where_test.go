@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseWhereFilter(t *testing.T) {
+	tests := []struct {
+		where string
+		want  string
+	}{
+		{"meta.product=foo", `{"field":"product","op":"eq","value":"foo"}`},
+		{"meta.product=foo AND meta.version>=2", `{"and":[{"field":"product","op":"eq","value":"foo"},{"field":"version","op":"gte","value":2}]}`},
+		{"a=1 or b!=x and c<3", `{"or":[{"field":"a","op":"eq","value":1},{"and":[{"field":"b","op":"ne","value":"x"},{"field":"c","op":"lt","value":3}]}]}`},
+		{"(a=1 OR b=2) AND NOT c<>'two words'", `{"and":[{"or":[{"field":"a","op":"eq","value":1},{"field":"b","op":"eq","value":2}]},{"not":{"field":"c","op":"ne","value":"two words"}}]}`},
+		{"meta.status IN (open, \"in review\", 3)", `{"field":"status","op":"in","value":["open","in review",3]}`},
+		{"version='2'", `{"field":"version","op":"eq","value":"2"}`},
+		{"created>2024-01-01T00:00:00Z", `{"field":"created","op":"gt","value":"2024-01-01T00:00:00Z"}`},
+		{"score<=-0.5", `{"field":"score","op":"lte","value":-0.5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			expr, err := parseWhere(tt.where)
+			if err != nil {
+				t.Fatalf("parseWhere() error = %v", err)
+			}
+			got, _ := json.Marshal(expr.Filter())
+			if string(got) != tt.want {
+				t.Errorf("Filter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWhereErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                "empty where expression",
+		"product":         "expected a comparison operator after 'product'",
+		"product=":        "at end: expected a value",
+		"product=foo AND": "at end: expected a metadata key",
+		"AND product=foo": "at position 1: expected a metadata key",
+		"(a=1":            "expected ')'",
+		"a=1)":            "unexpected ')'",
+		"a IN (1, 2":      "expected ',' or ')' in IN list",
+		"a IN 1":          "expected '(' after IN",
+		"a='open":         "unterminated string",
+		"a=1 b=2":         "at position 5: unexpected 'b'",
+		"a=1 ; b=2":       "unexpected character ';'",
+		"a=OR":            "at position 3: expected a value",
+		"meta.=1":         "empty metadata key",
+	}
+	for where, want := range tests {
+		_, err := parseWhere(where)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseWhere(%q) error = %v, want %q", where, err, want)
+		}
+	}
+}
+
+func TestWhereMatches(t *testing.T) {
+	metadata := map[string]interface{}{
+		"product": "foo",
+		"version": 2.0,
+		"build":   "10",
+		"tags":    []interface{}{"beta", "api"},
+		"owner":   nil,
+	}
+	tests := []struct {
+		where string
+		want  bool
+	}{
+		{"product=foo", true},
+		{"product=bar", false},
+		{"product!=bar", true},
+		{"meta.product=foo AND meta.version>=2", true},
+		{"version>2", false},
+		{"version=2.0", true},
+		{"version<10", true},
+		{"build>9", true},    // numeric text compares as a number
+		{"build>'9'", false}, // quoted literals compare as text
+		{"product>fo", true}, // text compares lexically
+		{"missing=1", false},
+		{"missing!=1", true},
+		{"owner=x", false},
+		{"missing>1", false},
+		{"tags=api", true},
+		{"tags!=api", false},
+		{"tags>api", false},
+		{"tags IN (alpha, beta)", true},
+		{"product IN (bar, baz)", false},
+		{"product=bar OR version=2", true},
+		{"NOT (product=bar OR version=3)", true},
+		{"not product=foo", false},
+	}
+	for _, tt := range tests {
+		expr, err := parseWhere(tt.where)
+		if err != nil {
+			t.Fatalf("parseWhere(%q) error = %v", tt.where, err)
+		}
+		if got := expr.Matches(metadata); got != tt.want {
+			t.Errorf("%q.Matches() = %v, want %v", tt.where, got, tt.want)
+		}
+	}
+}
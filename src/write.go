@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -98,6 +97,10 @@ func init() {
 	for _, cmd := range commands {
 		cmd.Flags().StringVar(&writeDocumentFileName, "file-name", "", "File name containing the document content")
 		cmd.Flags().StringVar(&writeDocumentFileName, "doc-file-name", "", "File name containing the document content (alias for file-name)")
+		cmd.Flags().BoolVar(&extractRaw, "raw", false, "Send the file content as-is instead of extracting its text")
+		addMetadataFlags(cmd)
+		addSplitFlags(cmd)
+		addRedactFlags(cmd)
 		// DEPRECATED: embedding per document is ignored; kept temporarily for compatibility
 		cmd.Flags().StringVar(&writeDocumentEmbedding, "embed", "default", "(DEPRECATED) Embedding model for the document (ignored; embedding is per collection)")
 	}
@@ -114,18 +117,38 @@ func writeDocument(vdbName, collectionName, docName string) error {
 		return fmt.Errorf("file name is required (use --file-name or --doc-file-name)")
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(fileName); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", fileName)
+	// Check that the file exists; "-" reads standard input
+	if err := validateDocumentSource(fileName, ""); err != nil {
+		return err
+	}
+	if err := loadUserMetadata(); err != nil {
+		return err
+	}
+	if err := loadSizeLimits(); err != nil {
+		return err
+	}
+	if err := loadRedaction(); err != nil {
+		return err
 	}
 
 	if dryRun {
 		if !silent {
-			fmt.Printf("[DRY RUN] Would write document '%s' to collection '%s' of vector database '%s' from file '%s'\n", docName, collectionName, vdbName, fileName)
+			fmt.Printf("[DRY RUN] Would write document '%s' to collection '%s' of vector database '%s' from %s%s\n", docName, collectionName, vdbName, describeDocumentSource(fileName, ""), describeSplit())
 		}
 		return nil
 	}
 
+	extracted, err := readDocumentSource(fileName, "", extractRaw)
+	if err != nil {
+		return err
+	}
+	if err := redactDocument(docName, extracted); err != nil {
+		return fmt.Errorf("document '%s' was not written: %w", docName, err)
+	}
+	if err := checkDocumentSize(docName, extracted.Text); err != nil {
+		return err
+	}
+
 	// Get MCP server URI
 	serverURI, err := getMCPServerURI(mcpServerURI)
 	if err != nil {
@@ -183,6 +206,19 @@ func writeDocument(vdbName, collectionName, docName string) error {
 		}
 	}
 
+	// A document larger than --max-size is written as parts with --split-by
+	if shouldSplitDocument(extracted.Text) {
+		parts, err := writeDocumentParts(client, serverURI, vdbName, collectionName, docName, extracted, userMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to write document '%s' to collection '%s' of vector database '%s': %w", docName, collectionName, vdbName, err)
+		}
+		if !silent {
+			fmt.Printf("✅ Document '%s' written successfully to collection '%s' of vector database '%s' as %d parts of up to %s\n", docName, collectionName, vdbName, parts, formatBytes(partBytes))
+			printRedactionReport(redactionReport())
+		}
+		return nil
+	}
+
 	// Call the MCP server to write the document with panic recovery
 	var writeErr error
 	func() {
@@ -192,7 +228,7 @@ func writeDocument(vdbName, collectionName, docName string) error {
 				writeErr = fmt.Errorf("MCP server could not be reached at %s. Please ensure the server is running and accessible", serverURI)
 			}
 		}()
-		writeErr = client.WriteDocument(vdbName, collectionName, docName, fileName, writeDocumentEmbedding)
+		writeErr = client.WriteDocument(vdbName, collectionName, docName, extracted, userMetadata, writeDocumentEmbedding)
 	}()
 
 	if writeErr != nil {
@@ -201,6 +237,7 @@ func writeDocument(vdbName, collectionName, docName string) error {
 
 	if !silent {
 		fmt.Printf("✅ Document '%s' written successfully to collection '%s' of vector database '%s'\n", docName, collectionName, vdbName)
+		printRedactionReport(redactionReport())
 	}
 
 	if verbose {
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestDocumentBulkDeleteDryRun tests bulk document deletion in dry-run mode without a server
+func TestDocumentBulkDeleteDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "delete", "--selector=meta.source=jira", "--glob=drafts/*", "--vdb=test-db", "--collection=docs", "--dry-run", "--mcp-server-uri=http://localhost:1")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Delete command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would delete the selected documents from collection 'docs' in vector database 'test-db'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestDocumentBulkDeleteInvalidSelector tests that a malformed selector is rejected
+func TestDocumentBulkDeleteInvalidSelector(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "delete", "--selector=meta.team in (docs", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Delete command should fail with an invalid selector")
+	}
+	if !contains(string(output), "invalid --selector") {
+		t.Errorf("Should report the invalid selector, got: %s", string(output))
+	}
+}
+
+// TestDocumentBulkDeleteEmptyNameFile tests that a names file without names is rejected
+func TestDocumentBulkDeleteEmptyNameFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "names.txt")
+	if err := os.WriteFile(file, []byte("# nothing yet\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "delete", "--from-file="+file, "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Delete command should fail with an empty names file")
+	}
+	if !contains(string(output), "no document names in") {
+		t.Errorf("Should report the empty file, got: %s", string(output))
+	}
+}
+
+// TestDocumentDeleteRequiresSelection tests document delete without a name or selection flags
+func TestDocumentDeleteRequiresSelection(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "delete", "--vdb=test-db", "--collection=docs")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Delete command should fail without a document name")
+	}
+	if !contains(string(output), "accepts 1 arg") {
+		t.Errorf("Should report the missing selection, got: %s", string(output))
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestChatHelp tests the chat command help
+func TestChatHelp(t *testing.T) {
+	output, err := exec.Command("../maestro", "chat", "--help").Output()
+	if err != nil {
+		t.Fatalf("Failed to run chat help command: %v", err)
+	}
+	for _, expected := range []string{":collection", ":limit", ":vdb", ":save FILE", "~/.maestro/history", "--doc-limit"} {
+		if !contains(string(output), expected) {
+			t.Errorf("Chat help output should contain '%s'", expected)
+		}
+	}
+}
+
+// TestQueryInteractiveDryRun tests that query --interactive only reports in dry-run mode
+func TestQueryInteractiveDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "query", "--interactive", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("query --interactive --dry-run failed: %v\n%s", err, output)
+	}
+	if !contains(string(output), "[DRY RUN] Would start an interactive query session on vector database 'test-db'") {
+		t.Errorf("Unexpected dry-run output: %s", output)
+	}
+}
+
+// TestQueryInteractiveRejectsQuery tests that --interactive does not accept a query argument
+func TestQueryInteractiveRejectsQuery(t *testing.T) {
+	output, err := exec.Command("../maestro", "query", "-i", "what?", "--vdb=test-db").CombinedOutput()
+	if err == nil {
+		t.Fatal("query --interactive with a query argument should fail")
+	}
+	if !contains(string(output), "takes no query argument") {
+		t.Errorf("Unexpected error output: %s", output)
+	}
+}
+
+// TestChatScriptedSession pipes a session through stdin and checks the commands, history and transcript
+func TestChatScriptedSession(t *testing.T) {
+	home := t.TempDir()
+	transcript := filepath.Join(home, "transcript.md")
+	input := strings.Join([]string{
+		":limit 3",
+		":collection notes",
+		":limit zero",
+		"What is Maestro?",
+		":save " + transcript,
+		":quit",
+		"never sent",
+	}, "\n") + "\n"
+
+	cmd := exec.Command("../maestro", "chat", "--vdb=test-db", "--collection=docs")
+	cmd.Env = append(os.Environ(), "HOME="+home, "MAESTRO_MCP_SERVER_URI=http://localhost:1")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chat session failed: %v\n%s", err, output)
+	}
+
+	outputStr := string(output)
+	for _, expected := range []string{
+		"Document limit: 3",
+		"Collection: notes",
+		"limit must be a positive number, got 'zero'",
+		"failed to query vector database",
+		"Saved transcript of 1 queries",
+	} {
+		if !contains(outputStr, expected) {
+			t.Errorf("Session output should contain '%s':\n%s", expected, outputStr)
+		}
+	}
+
+	history, err := os.ReadFile(filepath.Join(home, ".maestro", "history"))
+	if err != nil {
+		t.Fatalf("History file was not written: %v", err)
+	}
+	if !contains(string(history), "What is Maestro?\n") || contains(string(history), "never sent") {
+		t.Errorf("Unexpected history:\n%s", history)
+	}
+
+	data, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatalf("Transcript was not saved: %v", err)
+	}
+	if !contains(string(data), "## What is Maestro?") || !contains(string(data), "test-db/notes · limit 3") {
+		t.Errorf("Unexpected transcript:\n%s", data)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeChunkingSample(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sample.txt")
+	text := "The first sentence is short. The second sentence is a little bit longer! Is this the third one? Yes."
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatalf("Failed to write sample file: %v", err)
+	}
+	return path
+}
+
+// TestChunkingPreviewFixed tests that Fixed chunking is previewed locally without a server
+func TestChunkingPreviewFixed(t *testing.T) {
+	path := writeChunkingSample(t)
+
+	cmd := exec.Command("../maestro", "chunking", "preview", path, "--strategy=Fixed", "--chunk-size=40", "--chunk-overlap=10")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Chunking preview failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !strings.Contains(outputStr, "Fixed, chunk_size=40, overlap=10") {
+		t.Errorf("Should describe the chunking configuration, got: %s", outputStr)
+	}
+	if !strings.Contains(outputStr, "Chunks: 3") {
+		t.Errorf("Should report the chunk count, got: %s", outputStr)
+	}
+	if !strings.Contains(outputStr, "Size histogram:") {
+		t.Errorf("Should print a size histogram, got: %s", outputStr)
+	}
+}
+
+// TestChunkingPreviewSentenceJSON tests JSON output of Sentence chunking
+func TestChunkingPreviewSentenceJSON(t *testing.T) {
+	path := writeChunkingSample(t)
+
+	cmd := exec.Command("../maestro", "chunking", "preview", path, "--strategy=Sentence", "--chunk-size=50", "-o", "json")
+	output, err := cmd.Output()
+
+	if err != nil {
+		t.Fatalf("Chunking preview failed: %v, output: %s", err, string(output))
+	}
+
+	var preview struct {
+		Strategy   string `json:"strategy"`
+		Source     string `json:"source"`
+		ChunkCount int    `json:"chunk_count"`
+		Chunks     []struct {
+			Text string `json:"text"`
+		} `json:"chunks"`
+	}
+	if err := json.Unmarshal(output, &preview); err != nil {
+		t.Fatalf("Output should be valid JSON: %v, output: %s", err, string(output))
+	}
+	if preview.Strategy != "Sentence" || preview.Source != "local" {
+		t.Errorf("Unexpected strategy or source: %+v", preview)
+	}
+	if preview.ChunkCount != len(preview.Chunks) || preview.ChunkCount < 2 {
+		t.Errorf("Unexpected chunk count %d for %d chunks", preview.ChunkCount, len(preview.Chunks))
+	}
+}
+
+// TestChunkingPreviewSemanticDryRun tests that Semantic chunking is delegated to the server
+func TestChunkingPreviewSemanticDryRun(t *testing.T) {
+	path := writeChunkingSample(t)
+
+	cmd := exec.Command("../maestro", "chunking", "preview", path, "--strategy=Semantic", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Chunking preview failed: %v, output: %s", err, string(output))
+	}
+
+	if !strings.Contains(string(output), "[DRY RUN] Would request Semantic chunks") {
+		t.Errorf("Should show dry run message for server chunking, got: %s", string(output))
+	}
+}
+
+// TestChunkingPreviewInvalidOverlap tests that an overlap larger than the chunk size is rejected
+func TestChunkingPreviewInvalidOverlap(t *testing.T) {
+	path := writeChunkingSample(t)
+
+	cmd := exec.Command("../maestro", "chunking", "preview", path, "--chunk-size=10", "--chunk-overlap=10")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Chunking preview should fail when overlap is not smaller than chunk size")
+	}
+	if !strings.Contains(string(output), "must be smaller than chunk_size") {
+		t.Errorf("Should explain the overlap error, got: %s", string(output))
+	}
+}
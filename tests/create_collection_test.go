@@ -1,7 +1,9 @@
 package main
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -186,3 +188,90 @@ func TestCreateCollectionWithSemanticFlagsDryRun(t *testing.T) {
 		t.Fatalf("Create collection with semantic flags failed: %v, output: %s", err, string(output))
 	}
 }
+
+// TestCreateCollectionRejectsInapplicableSemanticFlags ensures semantic flags are rejected for other strategies
+func TestCreateCollectionRejectsInapplicableSemanticFlags(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "create",
+		"--name=test-collection", "--vdb=test-db",
+		"--chunking-strategy=Fixed",
+		"--semantic-window-size=2",
+		"--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Create collection should fail for semantic flags with Fixed chunking, output: %s", string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "'window_size' (--semantic-window-size) does not apply to strategy 'Fixed'") {
+		t.Errorf("Should explain that the parameter does not apply, got: %s", outputStr)
+	}
+}
+
+// TestCreateCollectionRejectsUnknownStrategy ensures unknown strategies fail with a suggestion
+func TestCreateCollectionRejectsUnknownStrategy(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "create",
+		"--name=test-collection", "--vdb=test-db",
+		"--chunking-strategy=Sentense",
+		"--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Create collection should fail for an unknown strategy, output: %s", string(output))
+	}
+
+	if !contains(string(output), "did you mean 'Sentence'?") {
+		t.Errorf("Should suggest the closest strategy, got: %s", string(output))
+	}
+}
+
+// TestCreateCollectionWithChunkingConfigDryRun ensures a chunking config file is loaded and validated
+func TestCreateCollectionWithChunkingConfigDryRun(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "chunking.yaml")
+	if err := os.WriteFile(valid, []byte("strategy: Sentence\nparameters:\n  chunk_size: 512\n  overlap: 32\n"), 0644); err != nil {
+		t.Fatalf("Failed to write chunking config: %v", err)
+	}
+
+	cmd := exec.Command("../maestro", "collection", "create",
+		"--name=test-collection", "--vdb=test-db",
+		"--chunking-config="+valid,
+		"--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Create collection with chunking config failed: %v, output: %s", err, string(output))
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("strategy: Sentence\nparameters:\n  chunk_sise: 512\n"), 0644); err != nil {
+		t.Fatalf("Failed to write chunking config: %v", err)
+	}
+
+	cmd = exec.Command("../maestro", "collection", "create",
+		"--name=test-collection", "--vdb=test-db",
+		"--chunking-config="+invalid,
+		"--dry-run")
+	output, err = cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Create collection should fail for an invalid chunking config, output: %s", string(output))
+	}
+	if !contains(string(output), "did you mean 'chunk_size'?") {
+		t.Errorf("Should suggest the closest parameter, got: %s", string(output))
+	}
+}
+
+// TestCreateCollectionChunkingConfigConflictsWithFlags ensures --chunking-config is not combined with chunking flags
+func TestCreateCollectionChunkingConfigConflictsWithFlags(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "create",
+		"--name=test-collection", "--vdb=test-db",
+		"--chunking-config=chunking.yaml", "--chunk-size=256",
+		"--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Create collection should fail when combining --chunking-config with chunking flags, output: %s", string(output))
+	}
+	if !contains(string(output), "--chunking-config cannot be combined") {
+		t.Errorf("Should explain the conflicting flags, got: %s", string(output))
+	}
+}
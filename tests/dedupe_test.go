@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestCollectionDedupeHelp tests that collection dedupe documents its flags
+func TestCollectionDedupeHelp(t *testing.T) {
+	output, err := exec.Command("../maestro", "collection", "dedupe", "--help").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Dedupe help failed: %v, output: %s", err, string(output))
+	}
+	for _, flag := range []string{"--delete", "--threshold", "--output", "--concurrency"} {
+		if !contains(string(output), flag) {
+			t.Errorf("Dedupe help should mention %s, got: %s", flag, string(output))
+		}
+	}
+}
+
+// TestCollectionDedupeDryRun tests collection dedupe in dry-run mode
+func TestCollectionDedupeDryRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "[DRY RUN] Would report near-duplicate documents in collection 'wiki' of vector database 'test-db' (threshold 0.90)"},
+		{[]string{"--delete", "--threshold=0.75"}, "[DRY RUN] Would delete near-duplicate documents in collection 'wiki' of vector database 'test-db' (threshold 0.75)"},
+	}
+	for _, test := range tests {
+		args := append([]string{"collection", "dedupe", "--vdb=test-db", "--name=wiki", "--dry-run"}, test.args...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("Dedupe command with %v failed: %v, output: %s", test.args, err, string(output))
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Dedupe command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
+
+// TestCollectionDedupeInvalidOptions tests validation of collection dedupe flags
+func TestCollectionDedupeInvalidOptions(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--threshold=0"}, "similarity threshold must be greater than 0 and at most 1"},
+		{[]string{"--threshold=1.5"}, "similarity threshold must be greater than 0 and at most 1"},
+		{[]string{"-o", "yaml"}, "unsupported output format 'yaml'"},
+	}
+	for _, test := range tests {
+		args := append([]string{"collection", "dedupe", "--vdb=test-db", "--name=wiki", "--dry-run"}, test.args...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("Dedupe command with %v should fail", test.args)
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Dedupe command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
+
+// TestIngestDedupeOptions tests validation of --dedupe and --dedupe-threshold
+func TestIngestDedupeOptions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.md"), []byte("# Page"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=wiki", "--dedupe=skip", "--dry-run").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Ingest command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would ingest 1 file(s)") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{dir, "--dedupe=drop"}, "unsupported dedupe mode 'drop'"},
+		{[]string{dir, "--dedupe=warn", "--dedupe-threshold=2"}, "similarity threshold must be greater than 0 and at most 1"},
+		{[]string{"--resume=20240102-150405-a1b2", "--dedupe=skip"}, "--dedupe cannot be combined with --resume"},
+	}
+	for _, test := range tests {
+		args := append([]string{"document", "ingest", "--vdb=test-db", "--collection=wiki", "--dry-run"}, test.args...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("Ingest command with %v should fail", test.args)
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Ingest command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// writeGoldenDataset writes a small eval dataset and returns its path
+func writeGoldenDataset(t *testing.T, data string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "golden.jsonl")
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// TestEvalRetrievalDryRun tests the eval retrieval command in dry-run mode
+func TestEvalRetrievalDryRun(t *testing.T) {
+	dataset := writeGoldenDataset(t, `{"query": "How do I rotate API keys?", "expected": ["security.md"]}
+{"query": "How do I install Maestro?", "expected": ["install.md", "README.md"]}
+`)
+
+	cmd := exec.Command("../maestro", "eval", "retrieval", "--vdb=test-db", "--collection=docs", "--dataset="+dataset, "--k=10,1,5", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Eval command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would evaluate 2 queries from "+dataset+" against vector database 'test-db' at k = 1,5,10") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestEvalRetrievalBaselineDryRun tests that the baseline is read before connecting
+func TestEvalRetrievalBaselineDryRun(t *testing.T) {
+	dataset := writeGoldenDataset(t, `{"query": "q", "expected": ["a.md"]}`)
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(baseline, []byte(`{"k": [5], "metrics": {"recall@5": 0.8, "mrr": 0.6}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "eval", "retrieval", "--vdb=test-db", "--collection=docs", "--dataset="+dataset, "--baseline="+baseline, "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Eval command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would compare the results with "+baseline) {
+		t.Errorf("Should mention the baseline, got: %s", string(output))
+	}
+
+	if err := os.WriteFile(baseline, []byte(`[1, 2]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command("../maestro", "eval", "retrieval", "--vdb=test-db", "--collection=docs", "--dataset="+dataset, "--baseline="+baseline, "--dry-run")
+	output, err = cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Eval command should fail with an invalid baseline")
+	}
+	if !contains(string(output), "is not a report of 'maestro eval retrieval -o json'") {
+		t.Errorf("Should report the invalid baseline, got: %s", string(output))
+	}
+}
+
+// TestEvalRetrievalInvalidDataset tests that dataset errors name the line
+func TestEvalRetrievalInvalidDataset(t *testing.T) {
+	dataset := writeGoldenDataset(t, `{"query": "q", "expected": ["a.md"]}
+{"query": "no answer"}
+`)
+
+	cmd := exec.Command("../maestro", "eval", "retrieval", "--vdb=test-db", "--collection=docs", "--dataset="+dataset, "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Eval command should fail with an invalid dataset")
+	}
+	if !contains(string(output), "line 2: no expected documents") {
+		t.Errorf("Should report the invalid line, got: %s", string(output))
+	}
+}
+
+// TestEvalRetrievalInvalidFlags tests validation of the eval retrieval flags
+func TestEvalRetrievalInvalidFlags(t *testing.T) {
+	dataset := writeGoldenDataset(t, `{"query": "q", "expected": ["a.md"]}`)
+	cases := map[string][]string{
+		"--dataset is required":            {"--vdb=test-db", "--collection=docs"},
+		"--k values must be positive":      {"--vdb=test-db", "--collection=docs", "--dataset=" + dataset, "--k=0"},
+		"unsupported output format 'xml'":  {"--vdb=test-db", "--collection=docs", "--dataset=" + dataset, "-o", "xml"},
+		"--tolerance cannot be negative":   {"--vdb=test-db", "--collection=docs", "--dataset=" + dataset, "--tolerance=-0.1"},
+		"--concurrency must be at least 1": {"--vdb=test-db", "--collection=docs", "--dataset=" + dataset, "--concurrency=0"},
+		"failed to open dataset":           {"--vdb=test-db", "--collection=docs", "--dataset=missing.jsonl"},
+	}
+	for message, flags := range cases {
+		args := append([]string{"eval", "retrieval", "--dry-run"}, flags...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("Eval command with %v should fail", flags)
+		}
+		if !contains(string(output), message) {
+			t.Errorf("Should report %q, got: %s", message, string(output))
+		}
+	}
+}
+
+// TestEvalRetrievalHelp tests the eval retrieval help output
+func TestEvalRetrievalHelp(t *testing.T) {
+	cmd := exec.Command("../maestro", "eval", "retrieval", "--help")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Eval help failed: %v, output: %s", err, string(output))
+	}
+
+	for _, flag := range []string{"--dataset", "--k", "--baseline", "--tolerance", "--concurrency", "--output"} {
+		if !contains(string(output), flag) {
+			t.Errorf("Help should mention %s, got: %s", flag, string(output))
+		}
+	}
+}
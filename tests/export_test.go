@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestExportVectorDatabaseDryRun tests the vectordb export command in dry-run mode
+func TestExportVectorDatabaseDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "vectordb", "export", "test-db", "-o", "kb.tar.gz", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Export command failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would export vector database 'test-db' to 'kb.tar.gz'") {
+		t.Errorf("Should show dry run message for export, got: %s", outputStr)
+	}
+}
+
+// TestExportVectorDatabaseRequiresName tests that export requires a vector database name
+func TestExportVectorDatabaseRequiresName(t *testing.T) {
+	cmd := exec.Command("../maestro", "vectordb", "export", "--dry-run")
+	err := cmd.Run()
+
+	if err == nil {
+		t.Error("Export command should fail without a vector database name")
+	}
+}
+
+// TestImportVectorDatabaseMissingArchive tests import with a non-existent archive
+func TestImportVectorDatabaseMissingArchive(t *testing.T) {
+	cmd := exec.Command("../maestro", "vectordb", "import", "nonexistent.tar.gz", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Import command should fail with a non-existent archive")
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "failed to open archive") {
+		t.Errorf("Should show archive error, got: %s", outputStr)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestExtractMarkdownFile tests that document extract strips Markdown formatting
+func TestExtractMarkdownFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guide.md")
+	if err := os.WriteFile(file, []byte("# Guide\n\nRead the **[docs](https://example.com)** first.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "extract", file)
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Extract command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "# Guide") || !contains(string(output), "Read the docs first.") {
+		t.Errorf("Should show the extracted text, got: %s", string(output))
+	}
+	if contains(string(output), "**") || contains(string(output), "https://example.com") {
+		t.Errorf("Should strip Markdown formatting, got: %s", string(output))
+	}
+}
+
+// TestExtractRawJSON tests document extract with --raw and JSON output
+func TestExtractRawJSON(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guide.md")
+	if err := os.WriteFile(file, []byte("# Guide\n\n**bold**\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "extract", file, "--raw", "-o", "json")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Extract command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), `"extractor": "raw"`) || !contains(string(output), `**bold**`) {
+		t.Errorf("Should show the raw content as JSON, got: %s", string(output))
+	}
+}
+
+// TestExtractBinaryFile tests that binary files without an extractor are rejected
+func TestExtractBinaryFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(file, []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "extract", file)
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Extract command should fail for a binary file")
+	}
+	if !contains(string(output), "has no text extractor") {
+		t.Errorf("Should report the missing extractor, got: %s", string(output))
+	}
+}
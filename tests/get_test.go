@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestDocumentGetDryRun tests document get in dry-run mode
+func TestDocumentGetDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "get", "guide.md", "--vdb=test-db", "--collection=docs", "--out=guide.txt", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Get command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would get document 'guide.md' from collection 'docs' of vector database 'test-db' and write it to 'guide.txt'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestDocumentGetAllDryRun tests document get --all in dry-run mode
+func TestDocumentGetAllDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "get", "--all", "--vdb=test-db", "--collection=docs", "--out=dump", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Get command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would write every document of collection 'docs' of vector database 'test-db' to 'dump'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestDocumentGetInvalidFlags tests the flag combinations document get rejects
+func TestDocumentGetInvalidFlags(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--all", "--vdb=test-db", "--collection=docs"}, "--all requires --out DIR"},
+		{[]string{"guide.md", "--all", "--out=dump", "--vdb=test-db", "--collection=docs"}, "a document name cannot be combined with --all"},
+		{[]string{"guide.md", "-o", "yaml", "--vdb=test-db", "--collection=docs"}, "unsupported output format 'yaml'"},
+		{[]string{"--vdb=test-db", "--collection=docs", "--dry-run"}, "document name is required in non-interactive mode"},
+	}
+	for _, test := range tests {
+		cmd := exec.Command("../maestro", append([]string{"document", "get"}, test.args...)...)
+		output, err := cmd.CombinedOutput()
+
+		if err == nil {
+			t.Errorf("Get command with %v should fail", test.args)
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Get command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDocumentImportJSONLDryRun tests that document import lists the records it would write
+func TestDocumentImportJSONLDryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tickets.jsonl")
+	data := `{"id": "T-1", "body": "Printer is down", "status": "open"}
+{"id": "T-2", "body": "Reset password", "status": "closed"}
+`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "import", file, "--vdb=test-db", "--collection=tickets", "--text-field=body", "--name-field=id", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Import command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would import 2 record(s)") || !contains(string(output), "line 2 → T-2 (2 metadata field(s))") {
+		t.Errorf("Should list the records to import, got: %s", string(output))
+	}
+}
+
+// TestDocumentImportCSVFromStdin tests importing CSV from standard input
+func TestDocumentImportCSVFromStdin(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "import", "-", "--format=csv", "--vdb=test-db", "--collection=faq", "--text-field=answer", "--dry-run")
+	cmd.Stdin = strings.NewReader("question,answer\nHow do I log in?,Use SSO.\n")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Import command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "line 2 → stdin-1") {
+		t.Errorf("Should name records after stdin, got: %s", string(output))
+	}
+}
+
+// TestDocumentImportInvalidRecords tests that invalid records abort the import
+func TestDocumentImportInvalidRecords(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tickets.jsonl")
+	if err := os.WriteFile(file, []byte("{\"id\": \"T-1\", \"body\": \"ok\"}\n{\"id\": \"T-2\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "import", file, "--vdb=test-db", "--collection=tickets", "--text-field=body", "--name-field=id", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Import command should fail with an invalid record")
+	}
+	if !contains(string(output), "line 2: missing text field 'body'") {
+		t.Errorf("Should report the invalid record, got: %s", string(output))
+	}
+
+	cmd = exec.Command("../maestro", "document", "import", file, "--vdb=test-db", "--collection=tickets", "--text-field=body", "--name-field=id", "--skip-invalid", "--dry-run")
+	output, err = cmd.CombinedOutput()
+	if err != nil || !contains(string(output), "Would import 1 record(s)") {
+		t.Errorf("Should skip the invalid record with --skip-invalid, got: %v, %s", err, string(output))
+	}
+}
+
+// TestDocumentCreateFromStdinDryRun tests document create with --file=-
+func TestDocumentCreateFromStdinDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "create", "--name=notes", "--file=-", "--vdb=test-db", "--collection=docs", "--dry-run")
+	cmd.Stdin = strings.NewReader("some notes")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Create command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "from standard input") {
+		t.Errorf("Should read from standard input, got: %s", string(output))
+	}
+}
+
+// TestDocumentCreateURLDryRun tests document create with --url
+func TestDocumentCreateURLDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "create", "--name=guide", "--url=https://example.com/guide.html", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Create command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "from URL 'https://example.com/guide.html'") {
+		t.Errorf("Should show the URL source, got: %s", string(output))
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// createGitRepo creates a repository with one commit, skipping the test when git is missing
+func createGitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.org", "commit", "-q", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+// TestIngestRepoDryRun tests that ingest-repo selects tracked, non-ignored files in dry-run mode
+func TestIngestRepoDryRun(t *testing.T) {
+	dir := createGitRepo(t, map[string]string{
+		"README.md":                 "# Project",
+		"main.go":                   "package main",
+		"node_modules/lib/index.js": "module.exports = {}",
+		".maestroignore":            "*.go\n",
+	})
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("untracked"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("../maestro", "document", "ingest-repo", dir, "--vdb=test-db", "--collection=code", "--dry-run").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Ingest-repo command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would ingest 1 tracked file(s)") {
+		t.Errorf("Should ingest only README.md, got: %s", string(output))
+	}
+}
+
+// TestIngestRepoNotARepository tests that ingest-repo requires a git checkout
+func TestIngestRepoNotARepository(t *testing.T) {
+	output, err := exec.Command("../maestro", "document", "ingest-repo", t.TempDir(), "--vdb=test-db", "--collection=code", "--dry-run").CombinedOutput()
+	if err == nil {
+		t.Fatal("Ingest-repo outside a repository should fail")
+	}
+	if !contains(string(output), "is not inside a git repository") {
+		t.Errorf("Should report the missing repository, got: %s", string(output))
+	}
+}
+
+// TestIngestRepoHelp tests that ingest-repo documents its flags
+func TestIngestRepoHelp(t *testing.T) {
+	output, err := exec.Command("../maestro", "document", "ingest-repo", "--help").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Ingest-repo help failed: %v, output: %s", err, string(output))
+	}
+	for _, flag := range []string{"--include", "--exclude", "--vendored", "--prune", ".maestroignore"} {
+		if !contains(string(output), flag) {
+			t.Errorf("Ingest-repo help should mention %s, got: %s", flag, string(output))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const testIngestManifest = `{
+  "version": 1,
+  "id": "20240102-150405-a1b2",
+  "created_at": "2024-01-02T15:04:05Z",
+  "updated_at": "2024-01-02T15:09:00Z",
+  "database": "test-db",
+  "collection": "docs",
+  "paths": ["corpus"],
+  "files": [
+    {"path": "corpus/a.md", "document": "a.md", "size": 10, "sha256": "0123456789abcdef", "status": "ingested"},
+    {"path": "corpus/b.md", "document": "b.md", "size": 20, "sha256": "fedcba9876543210", "status": "failed", "error": "MCP server error: timeout"},
+    {"path": "corpus/c.md", "document": "c.md", "size": 30, "status": "pending"}
+  ]
+}
+`
+
+// ingestRunsCommand runs the CLI in a temporary directory holding one recorded ingestion run
+func ingestRunsCommand(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+	dir := t.TempDir()
+	manifestDir := filepath.Join(dir, ".maestro", "ingest")
+	if err := os.MkdirAll(manifestDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(manifestDir, "20240102-150405-a1b2.json"), []byte(testIngestManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	binary, err := filepath.Abs("../maestro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// TestIngestRunsList tests listing recorded ingestion runs
+func TestIngestRunsList(t *testing.T) {
+	output, err := ingestRunsCommand(t, "ingest", "runs", "list").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Runs list failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "20240102-150405-a1b2") || !contains(outputStr, "test-db/docs") {
+		t.Errorf("Should list the recorded run, got: %s", outputStr)
+	}
+}
+
+// TestIngestRunsShowFailed tests showing only the failed files of a run
+func TestIngestRunsShowFailed(t *testing.T) {
+	output, err := ingestRunsCommand(t, "ingest", "runs", "show", "20240102-150405-a1b2", "--failed").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Runs show failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "3 total, 1 ingested, 1 failed, 1 pending") {
+		t.Errorf("Should show status counts, got: %s", outputStr)
+	}
+	if !contains(outputStr, "MCP server error: timeout") || contains(outputStr, "c.md") {
+		t.Errorf("Should only list failed files, got: %s", outputStr)
+	}
+	if !contains(outputStr, "maestro document ingest --resume 20240102-150405-a1b2") {
+		t.Errorf("Should show the resume command, got: %s", outputStr)
+	}
+}
+
+// TestIngestRunsShowUnknown tests that showing an unknown run fails
+func TestIngestRunsShowUnknown(t *testing.T) {
+	output, err := ingestRunsCommand(t, "ingest", "runs", "show", "unknown").CombinedOutput()
+	if err == nil {
+		t.Error("Runs show should fail for an unknown run")
+	}
+	if !contains(string(output), "ingestion run 'unknown' not found") {
+		t.Errorf("Should report the unknown run, got: %s", string(output))
+	}
+}
+
+// TestIngestResumeDryRun tests resuming a run in dry-run mode
+func TestIngestResumeDryRun(t *testing.T) {
+	output, err := ingestRunsCommand(t, "document", "ingest", "--resume", "20240102-150405-a1b2", "--dry-run").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Resume failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would resume run '20240102-150405-a1b2', ingesting 2 of 3 file(s) into collection 'docs' of vector database 'test-db'") {
+		t.Errorf("Should show dry run resume message, got: %s", outputStr)
+	}
+	if contains(outputStr, "a.md") {
+		t.Errorf("Should not resume ingested files, got: %s", outputStr)
+	}
+}
+
+// TestIngestResumeWrongCollection tests that a resume targeting another collection fails
+func TestIngestResumeWrongCollection(t *testing.T) {
+	output, err := ingestRunsCommand(t, "document", "ingest", "--resume", "20240102-150405-a1b2", "--collection=other", "--dry-run").CombinedOutput()
+	if err == nil {
+		t.Error("Resume should fail for a different collection")
+	}
+	if !contains(string(output), "ingested into collection 'docs', not 'other'") {
+		t.Errorf("Should report the collection mismatch, got: %s", string(output))
+	}
+}
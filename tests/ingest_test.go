@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func writeIngestTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"guide.md", "notes.txt", "api/reference.md"} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("content of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestIngestDryRun tests document ingest in dry-run mode
+func TestIngestDryRun(t *testing.T) {
+	dir := writeIngestTree(t)
+
+	cmd := exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=docs", "--include=*.md", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Ingest command failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would ingest 2 file(s) into collection 'docs' of vector database 'test-db'") {
+		t.Errorf("Should show dry run message, got: %s", outputStr)
+	}
+	if !contains(outputStr, "→ api/reference.md") || !contains(outputStr, "→ guide.md") {
+		t.Errorf("Should list documents named by relative path, got: %s", outputStr)
+	}
+	if contains(outputStr, "notes.txt") {
+		t.Errorf("Should not include files excluded by --include, got: %s", outputStr)
+	}
+}
+
+// TestIngestNoMatchingFiles tests that ingest fails when the filters match nothing
+func TestIngestNoMatchingFiles(t *testing.T) {
+	dir := writeIngestTree(t)
+
+	cmd := exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=docs", "--include=*.pdf", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Ingest command should fail when no files match")
+	}
+	if !contains(string(output), "no files to ingest") {
+		t.Errorf("Should report that no files matched, got: %s", string(output))
+	}
+}
+
+// TestIngestMissingPath tests that ingest fails for a path that does not exist
+func TestIngestMissingPath(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "ingest", "does-not-exist", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Ingest command should fail for a missing path")
+	}
+	if !contains(string(output), "failed to read path 'does-not-exist'") {
+		t.Errorf("Should report the missing path, got: %s", string(output))
+	}
+}
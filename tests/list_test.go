@@ -478,3 +478,15 @@ func TestListChunkingStrategiesVerbose(t *testing.T) {
 		t.Errorf("Expected dry-run message, got: %s", outputStr)
 	}
 }
+
+func TestListDocumentsInvalidOutput(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "list", "--vdb=test-db", "--collection=test-collection", "-o", "xml", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("List documents should fail with an unsupported output format")
+	}
+	if !strings.Contains(string(output), "unsupported output format 'xml' (use text, wide or json)") {
+		t.Errorf("Expected unsupported output format error, got: %s", string(output))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestDocumentCreateReservedMeta tests that maestro's own metadata keys cannot be set with --meta
+func TestDocumentCreateReservedMeta(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "create", "--name=doc", "--file="+file, "--vdb=test-db", "--collection=docs", "--meta=doc_name=other", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Create command should fail with a reserved metadata key")
+	}
+	if !contains(string(output), "metadata key 'doc_name' is reserved") {
+		t.Errorf("Should report the reserved key, got: %s", string(output))
+	}
+}
+
+// TestDocumentIngestMetaFile tests that ingest validates --meta-file before writing
+func TestDocumentIngestMetaFile(t *testing.T) {
+	dir := writeIngestTree(t)
+	metaFile := filepath.Join(t.TempDir(), "meta.yaml")
+	if err := os.WriteFile(metaFile, []byte("owner: docs-team\nproduct: maestro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=docs", "--meta-file="+metaFile, "--meta=version=2", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Ingest command failed: %v, output: %s", err, string(output))
+	}
+
+	if err := os.WriteFile(metaFile, []byte("- not\n- a mapping\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=docs", "--meta-file="+metaFile, "--dry-run")
+	output, err = cmd.CombinedOutput()
+	if err == nil || !contains(string(output), "invalid metadata file") {
+		t.Errorf("Should reject a metadata file that is not a mapping, got: %v, %s", err, string(output))
+	}
+}
+
+// TestExtractFrontMatterJSON tests that document extract shows front matter as metadata
+func TestExtractFrontMatterJSON(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guide.md")
+	if err := os.WriteFile(file, []byte("---\nowner: docs-team\n---\n# Guide\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "extract", file, "-o", "json")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Extract command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), `"owner": "docs-team"`) || contains(string(output), "---") {
+		t.Errorf("Should show front matter as metadata, got: %s", string(output))
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestMigrateCollectionDryRun tests the collection migrate command in dry-run mode
+func TestMigrateCollectionDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "migrate", "--vdb=test-db", "--name=docs", "--to-name=docs-v2", "--embedding=new-model", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Migrate command failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would migrate collection 'docs' in vector database 'test-db' to collection 'docs-v2' in vector database 'test-db'") {
+		t.Errorf("Should show dry run message for migration, got: %s", outputStr)
+	}
+}
+
+// TestMigrateCollectionWithSwapDryRun tests that --swap is reported in dry-run mode
+func TestMigrateCollectionWithSwapDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "migrate", "--vdb=test-db", "--name=docs", "--to-vdb=other-db", "--to-name=docs", "--swap", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Migrate command failed with swap: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would delete collection 'docs' from vector database 'test-db' once document counts match") {
+		t.Errorf("Should show swap dry run message, got: %s", outputStr)
+	}
+}
+
+// TestMigrateCollectionRequiresTarget tests that migrate requires --to-name
+func TestMigrateCollectionRequiresTarget(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "migrate", "--vdb=test-db", "--name=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Migrate command should fail without --to-name")
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "--to-name flag is required") {
+		t.Errorf("Should show missing flag error, got: %s", outputStr)
+	}
+}
+
+// TestMigrateCollectionSameTarget tests that migrate rejects copying a collection onto itself
+func TestMigrateCollectionSameTarget(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "migrate", "--vdb=test-db", "--name=docs", "--to-name=docs", "--dry-run")
+	err := cmd.Run()
+
+	if err == nil {
+		t.Error("Migrate command should fail when the target is the source collection")
+	}
+}
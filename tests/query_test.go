@@ -240,3 +240,17 @@ func TestQueryCommandExists(t *testing.T) {
 		t.Error("Help output should contain 'query' command")
 	}
 }
+
+// TestQueryWhereDryRun tests that query accepts --where and the score thresholds
+func TestQueryWhereDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "query", "How do I upgrade?", "--vdb=test-db", "--collection=docs",
+		"--where", "meta.status IN (published, 'in review')", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("query --where --dry-run failed: %v\n%s", err, output)
+	}
+	expected := `[DRY RUN] Would query vector database with filter {"field":"status","op":"in","value":["published","in review"]}`
+	if !contains(string(output), expected) {
+		t.Errorf("Unexpected dry-run output: %s", output)
+	}
+}
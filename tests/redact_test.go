@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestIngestRedactDryRun tests that document ingest accepts --redact in dry-run mode
+func TestIngestRedactDryRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.md"), []byte("Contact ops@example.org"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "ingest", dir, "--vdb=test-db", "--collection=wiki", "--redact=mask", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Ingest command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would ingest 1 file(s)") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestIngestRedactInvalidOptions tests validation of --redact and --redact-config
+func TestIngestRedactInvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.md"), []byte("# Page"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "redact.yaml")
+	if err := os.WriteFile(config, []byte("disable: [fax]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--redact=scrub"}, "unsupported --redact mode 'scrub'"},
+		{[]string{"--redact-config=" + config}, "--redact-config requires --redact"},
+		{[]string{"--redact=mask", "--redact-config=" + config}, "unknown detector(s) fax"},
+	}
+	for _, test := range tests {
+		args := append([]string{"document", "ingest", dir, "--vdb=test-db", "--collection=wiki", "--dry-run"}, test.args...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("Ingest command with %v should fail", test.args)
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Ingest command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
+
+// TestIngestResumeRejectsRedact tests that a resumed run keeps the redaction of its manifest
+func TestIngestResumeRejectsRedact(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "ingest", "--resume=20240102-150405-a1b2", "--redact=mask")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Ingest command should fail for --redact with --resume")
+	}
+	if !contains(string(output), "cannot be combined with --resume") {
+		t.Errorf("Should reject --redact on resume, got: %s", string(output))
+	}
+}
@@ -157,3 +157,116 @@ func TestSearchCommandExists(t *testing.T) {
 		t.Error("Help output should contain 'search' command")
 	}
 }
+
+// TestSearchWhereDryRun tests that --where and the score thresholds are compiled in dry-run mode
+func TestSearchWhereDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "search", "release notes", "--vdb=test-db", "--collection=docs",
+		"--where", "meta.product=foo AND meta.version>=2", "--min-score", "0.5", "--max-distance", "0.4", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("search --where --dry-run failed: %v\n%s", err, output)
+	}
+	expected := `[DRY RUN] Would search vector database with filter {"and":[{"field":"product","op":"eq","value":"foo"},{"field":"version","op":"gte","value":2}]}, min score 0.5, max distance 0.4`
+	if !contains(string(output), expected) {
+		t.Errorf("Unexpected dry-run output: %s", output)
+	}
+}
+
+// TestSearchInvalidFilters tests that malformed --where expressions and thresholds are rejected
+func TestSearchInvalidFilters(t *testing.T) {
+	tests := map[string][]string{
+		"invalid where expression at end: expected a value": {"--where", "meta.version>="},
+		"--min-score must be between 0 and 1":               {"--min-score", "1.5"},
+		"--max-distance cannot be negative":                 {"--max-distance", "-1"},
+	}
+	for expected, flags := range tests {
+		args := append([]string{"search", "q", "--vdb=test-db", "--collection=docs", "--dry-run"}, flags...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("search %v should fail", flags)
+		}
+		if !contains(string(output), expected) {
+			t.Errorf("search %v output should contain '%s': %s", flags, expected, output)
+		}
+	}
+}
+
+// TestSearchTargetsDryRun tests that federated search lists its targets in dry-run mode
+func TestSearchTargetsDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "search", "deployment guide", "--target", "vdbA/docs", "--target", "vdbB/wiki",
+		"--target", "vdbC/*", "--where", "product=foo", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("search --target --dry-run failed: %v\n%s", err, output)
+	}
+	for _, expected := range []string{
+		"[DRY RUN] Would search 3 target(s): vdbA/docs, vdbB/wiki, vdbC/*",
+		`[DRY RUN] Would narrow results with filter {"field":"product","op":"eq","value":"foo"}`,
+	} {
+		if !contains(string(output), expected) {
+			t.Errorf("Dry-run output should contain '%s': %s", expected, output)
+		}
+	}
+}
+
+// TestSearchInvalidTargets tests that malformed targets and conflicting flags are rejected
+func TestSearchInvalidTargets(t *testing.T) {
+	tests := map[string][]string{
+		"invalid target 'vdbA' (use VDB/COLLECTION":              {"--target", "vdbA"},
+		"invalid target 'vdbA/[docs'":                            {"--target", "vdbA/[docs"},
+		"--target cannot be combined with --vdb or --collection": {"--target", "vdbA/docs", "--vdb", "vdbB"},
+		"--concurrency must be at least 1":                       {"--target", "vdbA/docs", "--concurrency", "0"},
+	}
+	for expected, flags := range tests {
+		args := append([]string{"search", "q", "--dry-run"}, flags...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("search %v should fail", flags)
+		}
+		if !contains(string(output), expected) {
+			t.Errorf("search %v output should contain '%s': %s", flags, expected, output)
+		}
+	}
+}
+
+// TestSearchRerankDryRun tests that --rerank reports the candidates it would fetch
+func TestSearchRerankDryRun(t *testing.T) {
+	tests := map[string][]string{
+		"[DRY RUN] Would re-rank 20 candidates with bm25 and return the best 5":    {"--rerank", "bm25"},
+		"[DRY RUN] Would re-rank 50 candidates with hybrid and return the best 10": {"--rerank", "hybrid", "--candidates", "50", "-d", "10", "--rerank-weight", "0.7"},
+	}
+	for expected, flags := range tests {
+		args := append([]string{"search", "ERR-1042", "--vdb=test-db", "--collection=docs", "--dry-run"}, flags...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("search %v failed: %v\n%s", flags, err, output)
+		}
+		if !contains(string(output), expected) {
+			t.Errorf("search %v output should contain '%s': %s", flags, expected, output)
+		}
+	}
+}
+
+// TestSearchInvalidRerank tests that invalid re-ranking flags are rejected
+func TestSearchInvalidRerank(t *testing.T) {
+	tests := map[string][]string{
+		"unsupported rerank mode 'cross' (use bm25 or hybrid)": {"--vdb=test-db", "--rerank", "cross"},
+		"--candidates (3) must be at least --doc-limit (5)":    {"--vdb=test-db", "--rerank", "bm25", "--candidates", "3"},
+		"--rerank-weight must be between 0 and 1":              {"--vdb=test-db", "--rerank", "hybrid", "--rerank-weight", "2"},
+		"--candidates and --rerank-weight require --rerank":    {"--vdb=test-db", "--candidates", "30"},
+		"--rerank cannot be combined with --target":            {"--target", "vdbA/docs", "--rerank", "bm25"},
+	}
+	for expected, flags := range tests {
+		args := append([]string{"search", "q", "--collection=docs", "--dry-run"}, flags...)
+		if flags[0] == "--target" {
+			args = append([]string{"search", "q", "--dry-run"}, flags...)
+		}
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("search %v should fail", flags)
+		}
+		if !contains(string(output), expected) {
+			t.Errorf("search %v output should contain '%s': %s", flags, expected, output)
+		}
+	}
+}
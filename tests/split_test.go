@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestDocumentCreateSplitDryRun tests document create --split-by in dry-run mode
+func TestDocumentCreateSplitDryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "handbook.md")
+	if err := os.WriteFile(file, []byte("# Handbook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "create", "--name=handbook", "--file="+file, "--vdb=test-db", "--collection=docs",
+		"--split-by=heading", "--max-size=2MiB", "--part-size=512KiB", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Create command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "split by heading into parts of up to 512.0 KiB if larger than 2.0 MiB") {
+		t.Errorf("Should describe the split, got: %s", string(output))
+	}
+}
+
+// TestDocumentCreateSplitInvalidOptions tests validation of the size and split options
+func TestDocumentCreateSplitInvalidOptions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "handbook.md")
+	if err := os.WriteFile(file, []byte("# Handbook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--split-by=chapter"}, "unsupported --split-by 'chapter'"},
+		{[]string{"--max-size=huge"}, "invalid --max-size"},
+		{[]string{"--split-by=size", "--max-size=1MiB", "--part-size=2MiB"}, "--part-size 2.0 MiB is larger than --max-size 1.0 MiB"},
+		{[]string{"--split-by=size", "--upsert"}, "--split-by cannot be combined with --upsert"},
+	}
+	for _, test := range tests {
+		args := append([]string{"document", "create", "--name=handbook", "--file=" + file, "--vdb=test-db", "--collection=docs", "--dry-run"}, test.args...)
+		output, err := exec.Command("../maestro", args...).CombinedOutput()
+		if err == nil {
+			t.Errorf("Create command with %v should fail", test.args)
+		}
+		if !contains(string(output), test.expected) {
+			t.Errorf("Create command with %v should report %q, got: %s", test.args, test.expected, string(output))
+		}
+	}
+}
+
+// TestDocumentCreateReservedPartName tests that part names cannot be created directly
+func TestDocumentCreateReservedPartName(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "handbook.md")
+	if err := os.WriteFile(file, []byte("# Handbook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "create", "--name=handbook#part-2", "--file="+file, "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Create command should fail for a part name")
+	}
+	if !contains(string(output), "reserved for the parts of split documents") {
+		t.Errorf("Should report the reserved name, got: %s", string(output))
+	}
+}
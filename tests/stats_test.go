@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestCollectionStatsDryRun tests the collection stats command in dry-run mode
+func TestCollectionStatsDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "stats", "--vdb=test-db", "--name=docs", "--sample=10", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Stats command failed: %v, output: %s", err, string(output))
+	}
+
+	if !contains(string(output), "[DRY RUN] Would compute statistics for collection 'docs' in vector database 'test-db'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestCollectionStatsInvalidOutput tests that an unknown output format is rejected
+func TestCollectionStatsInvalidOutput(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "stats", "--vdb=test-db", "--name=docs", "-o", "xml", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Stats command should fail with an unsupported output format")
+	}
+	if !contains(string(output), "unsupported output format 'xml'") {
+		t.Errorf("Should report the unsupported format, got: %s", string(output))
+	}
+}
+
+// TestCollectionStatsHelp tests the collection stats help output
+func TestCollectionStatsHelp(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "stats", "--help")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Stats help failed: %v, output: %s", err, string(output))
+	}
+
+	for _, flag := range []string{"--sample", "--top", "--output"} {
+		if !contains(string(output), flag) {
+			t.Errorf("Help should mention %s, got: %s", flag, string(output))
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestSyncDryRun tests document sync in dry-run mode without a reachable server
+func TestSyncDryRun(t *testing.T) {
+	dir := writeIngestTree(t)
+
+	cmd := exec.Command("../maestro", "document", "sync", dir, "--vdb=test-db", "--collection=docs", "--prune", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Sync command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would sync") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestSyncRequiresDirectory tests that sync rejects a file argument
+func TestSyncRequiresDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "sync", file, "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Sync command should fail for a file argument")
+	}
+	if !contains(string(output), "is not a directory") {
+		t.Errorf("Should report that the path is not a directory, got: %s", string(output))
+	}
+}
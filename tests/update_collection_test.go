@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestUpdateCollectionDryRun tests the collection update command in dry-run mode
+func TestUpdateCollectionDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "update", "--vdb=test-db", "--name=docs",
+		"--chunking-strategy=Sentence", "--chunk-size=512", "--rechunk", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Update command failed: %v, output: %s", err, string(output))
+	}
+
+	outputStr := string(output)
+	if !contains(outputStr, "[DRY RUN] Would update chunking of collection 'docs' in vector database 'test-db' to Sentence (chunk_size=512)") {
+		t.Errorf("Should show dry run message for the update, got: %s", outputStr)
+	}
+	if !contains(outputStr, "[DRY RUN] Would re-ingest every document of collection 'docs'") {
+		t.Errorf("Should show dry run message for re-chunking, got: %s", outputStr)
+	}
+}
+
+// TestUpdateCollectionKeepsStrategyDryRun tests that parameters alone keep the current strategy
+func TestUpdateCollectionKeepsStrategyDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "update", "--vdb=test-db", "--name=docs", "--chunk-overlap=64", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Update command failed: %v, output: %s", err, string(output))
+	}
+
+	if !contains(string(output), "to the current strategy with overlap=64") {
+		t.Errorf("Should keep the current strategy, got: %s", string(output))
+	}
+}
+
+// TestUpdateCollectionRequiresChange tests that update fails when nothing would change
+func TestUpdateCollectionRequiresChange(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "update", "--vdb=test-db", "--name=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Update command should fail without any chunking change")
+	}
+	if !contains(string(output), "nothing to update") {
+		t.Errorf("Should explain that nothing was requested, got: %s", string(output))
+	}
+}
+
+// TestUpdateCollectionRejectsInvalidConfig tests that an invalid chunking change is rejected
+func TestUpdateCollectionRejectsInvalidConfig(t *testing.T) {
+	cmd := exec.Command("../maestro", "collection", "update", "--vdb=test-db", "--name=docs",
+		"--chunking-strategy=Fixed", "--chunk-size=100", "--chunk-overlap=200", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Update command should fail when overlap exceeds chunk size")
+	}
+	if !contains(string(output), "overlap (200) must be smaller than chunk_size (100)") {
+		t.Errorf("Should explain the invalid overlap, got: %s", string(output))
+	}
+}
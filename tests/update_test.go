@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestDocumentUpdateDryRun tests document update in dry-run mode
+func TestDocumentUpdateDryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guide.md")
+	if err := os.WriteFile(file, []byte("# Guide v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "update", "guide", "--file="+file, "--vdb=test-db", "--collection=docs", "--keep-versions=3", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Update command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would update document 'guide' in collection 'docs' of vector database 'test-db' from file '"+file+"', keeping 3 version(s)") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestDocumentUpdateRequiresSource tests that document update needs new content
+func TestDocumentUpdateRequiresSource(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "update", "guide", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Update command should fail without --file or --url")
+	}
+	if !contains(string(output), "--file or --url is required") {
+		t.Errorf("Should report the missing source, got: %s", string(output))
+	}
+}
+
+// TestDocumentCreateUpsertDryRun tests document create --upsert in dry-run mode
+func TestDocumentCreateUpsertDryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guide.md")
+	if err := os.WriteFile(file, []byte("# Guide"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("../maestro", "document", "create", "--name=guide", "--file="+file, "--vdb=test-db", "--collection=docs", "--upsert", "--dry-run")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Create command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would create or replace document 'guide'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+
+	cmd = exec.Command("../maestro", "document", "create", "--name=guide", "--file="+file, "--vdb=test-db", "--collection=docs", "--keep-versions=2", "--dry-run")
+	output, err = cmd.CombinedOutput()
+	if err == nil || !contains(string(output), "requires --upsert") {
+		t.Errorf("Should require --upsert for --keep-versions, got: %v, %s", err, string(output))
+	}
+}
+
+// TestDocumentHistoryDryRun tests document history in dry-run mode
+func TestDocumentHistoryDryRun(t *testing.T) {
+	cmd := exec.Command("../maestro", "document", "history", "guide", "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("History command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would list the versions of document 'guide'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestWatchDryRun tests document watch in dry-run mode
+func TestWatchDryRun(t *testing.T) {
+	dir := writeIngestTree(t)
+
+	cmd := exec.Command("../maestro", "document", "watch", dir, "--vdb=test-db", "--collection=docs", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Watch command failed: %v, output: %s", err, string(output))
+	}
+	if !contains(string(output), "[DRY RUN] Would watch") || !contains(string(output), "collection 'docs' of vector database 'test-db'") {
+		t.Errorf("Should show dry run message, got: %s", string(output))
+	}
+}
+
+// TestWatchInvalidLogFormat tests that an unknown log format is rejected
+func TestWatchInvalidLogFormat(t *testing.T) {
+	dir := writeIngestTree(t)
+
+	cmd := exec.Command("../maestro", "document", "watch", dir, "--vdb=test-db", "--collection=docs", "--log-format=xml", "--dry-run")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Watch command should fail with an unsupported log format")
+	}
+	if !contains(string(output), "unsupported log format 'xml'") {
+		t.Errorf("Should report the unsupported log format, got: %s", string(output))
+	}
+}
+
+// TestWatchInvalidRetries tests that retry counts and delays are bounded
+func TestWatchInvalidRetries(t *testing.T) {
+	dir := writeIngestTree(t)
+	tests := map[string]string{
+		"--retries=34":     "--retries must be between 0 and 10",
+		"--retries=-1":     "--retries must be between 0 and 10",
+		"--retry-delay=0s": "--retry-delay must be positive and at most 1m0s",
+		"--retry-delay=2h": "--retry-delay must be positive and at most 1m0s",
+	}
+	for flag, message := range tests {
+		output, err := exec.Command("../maestro", "document", "watch", dir, "--vdb=test-db", "--collection=docs", flag, "--dry-run").CombinedOutput()
+		if err == nil {
+			t.Errorf("Watch command with %s should fail", flag)
+		}
+		if !contains(string(output), message) {
+			t.Errorf("Watch command with %s should report %q, got: %s", flag, message, string(output))
+		}
+	}
+}